@@ -162,7 +162,7 @@ RPCFLAGS="$RPCFLAGS --rpc-ws-enabled --rpc-ws-api=DEBUG,TRACE,ETH,NET,WEB3,ADMIN
 
 # Enable merge support if needed
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    echo "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+    echo "${HIVE_JWT_SECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
     RPCFLAGS="$RPCFLAGS --engine-host-allowlist=* --engine-jwt-secret /jwtsecret"
 fi
 
@@ -171,6 +171,9 @@ if [ "$HIVE_PARALLEL_TX_PROCESSING_DISABLED" = "true" ]; then
     FLAGS="$FLAGS --bonsai-parallel-tx-processing-enabled=false"
 fi
 
+# Add extra flags supplied by the simulator, on both the import and the run path.
+FLAGS="$FLAGS $HIVE_EXTRA_ARGS"
+
 # Start Besu.
 if [ -z "$HAS_IMPORT" ]; then
     cmd="$besu $FLAGS $RPCFLAGS"
@@ -76,6 +76,9 @@ if [ "$HIVE_CLIQUE_PRIVATEKEY" != "" ]; then
 fi
 
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
+    if [ "$HIVE_JWT_SECRET" != "" ]; then
+        echo -n $HIVE_JWT_SECRET > ./jwtsecret
+    fi
     FLAGS="$FLAGS --jwtSecret ./jwtsecret"
 fi
 
@@ -98,6 +101,7 @@ fi
 if [ "$HIVE_BOOTNODE" != "" ]; then
     FLAGS="$FLAGS --bootnodes=$HIVE_BOOTNODE"
 fi
+FLAGS="$FLAGS $HIVE_EXTRA_ARGS"
 echo "Running ethereumjs with flags $FLAGS"
 
 
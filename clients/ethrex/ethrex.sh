@@ -99,8 +99,8 @@ FLAGS="$FLAGS --http.addr=0.0.0.0  --authrpc.addr=0.0.0.0"
 
 # We don't support pre merge
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    JWT_SECRET="7365637265747365637265747365637265747365637265747365637265747365"
-    echo -n $JWT_SECRET > /jwt.secret
+    JWT_SECRET="${HIVE_JWT_SECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}"
+    echo -n ${JWT_SECRET#0x} > /jwt.secret
     FLAGS="$FLAGS  --authrpc.jwtsecret=/jwt.secret"
 else
     # We dont exit because some tests require this
@@ -110,7 +110,7 @@ fi
 # Set amount of milliseconds between each transaction broadcast
 FLAGS="$FLAGS --p2p.tx-broadcasting-interval=5"
 
-FLAGS="$FLAGS  $HIVE_ETHREX_FLAGS"
+FLAGS="$FLAGS  $HIVE_ETHREX_FLAGS $HIVE_EXTRA_ARGS"
 
 # Launch the main client.
 echo "Running ethrex with flags: $FLAGS"
@@ -142,7 +142,7 @@ FLAGS="$FLAGS --http --http.addr=0.0.0.0 --http.port=8545 --http.api=admin,debug
 FLAGS="$FLAGS --ws --ws.addr=0.0.0.0 --ws.origins \"*\" --ws.api=admin,debug,eth,miner,net,txpool,web3"
 
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    echo "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+    echo "${HIVE_JWT_SECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
     FLAGS="$FLAGS --authrpc.addr=0.0.0.0 --authrpc.port=8551 --authrpc.jwtsecret /jwtsecret"
 fi
 
@@ -161,5 +161,8 @@ FLAGS="$FLAGS --nat=extip:$ip"
 
 # Disable disk space free monitor
 FLAGS="$FLAGS --datadir.minfreedisk=0"
+
+# Add extra flags supplied by the simulator.
+FLAGS="$FLAGS $HIVE_EXTRA_ARGS"
 echo "Running go-ethereum with flags $FLAGS"
 $geth $FLAGS
@@ -157,13 +157,14 @@ FLAGS="$FLAGS --http --http.addr=0.0.0.0 --http.api=admin,debug,trace,eth,net,we
 FLAGS="$FLAGS --ws --ws.addr=0.0.0.0 --ws.api=admin,debug,trace,eth,net,web3"
 
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    JWT_SECRET="7365637265747365637265747365637265747365637265747365637265747365"
-    echo -n $JWT_SECRET > /jwt.secret
+    JWT_SECRET="${HIVE_JWT_SECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}"
+    echo -n ${JWT_SECRET#0x} > /jwt.secret
     FLAGS="$FLAGS --authrpc.addr=0.0.0.0 --authrpc.jwtsecret=/jwt.secret"
 fi
 
 # Configure NAT and disable pruning
 FLAGS="$FLAGS --nat none --block-interval 500000"
+FLAGS="$FLAGS $HIVE_EXTRA_ARGS"
 
 # Launch the main client.
 echo "Running reth with flags: $FLAGS"
@@ -32,6 +32,15 @@ var mod7702AccountCode []byte
 //go:embed bytecode/largelogs.bin
 var modLargeReceiptCode []byte
 
+//go:embed bytecode/genaccounts.bin
+var genaccountsCode []byte
+
+//go:embed bytecode/deepstorage.bin
+var deepstorageCode []byte
+
+//go:embed bytecode/fuzz.bin
+var fuzzCode []byte
+
 // //go:embed bytecode/deposit.bin
 // var depositCode []byte
 //
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/exp/maps"
+)
+
+// diffCommand compares two chain.rlp files. Like diff(1), it exits with status 1 when
+// the chains differ.
+func diffCommand(args []string) {
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 2 {
+		fatalf("Usage: hivechain diff <a.rlp> <b.rlp>")
+	}
+	a, err := loadChain(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	b, err := loadChain(flag.Arg(1))
+	if err != nil {
+		fatal(err)
+	}
+	d := diffChains(a, b)
+	d.print(os.Stdout)
+	if !d.equal() {
+		os.Exit(1)
+	}
+}
+
+// chainDiff is the result of comparing two chains.
+type chainDiff struct {
+	Ancestor *blockID // latest block shared by both chains, nil if none
+	Blocks   []*blockDiff
+	OnlyA    []blockID // blocks at heights not present in b
+	OnlyB    []blockID // blocks at heights not present in a
+}
+
+type blockID struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// blockDiff describes the differences between two blocks at the same height.
+type blockDiff struct {
+	Number   uint64
+	A, B     common.Hash
+	Fields   []fieldDiff   // differing header fields
+	OnlyATxs []common.Hash // transactions only in block a
+	OnlyBTxs []common.Hash // transactions only in block b
+	TxCountA int
+	TxCountB int
+}
+
+type fieldDiff struct {
+	Name string
+	A, B string
+}
+
+// diffChains compares the blocks of two chains by height.
+func diffChains(a, b []*types.Block) *chainDiff {
+	d := new(chainDiff)
+	d.Ancestor = commonAncestor(a, b)
+
+	bByNumber := make(map[uint64]*types.Block, len(b))
+	for _, block := range b {
+		bByNumber[block.NumberU64()] = block
+	}
+	aNumbers := make(map[uint64]bool, len(a))
+	for _, ba := range a {
+		aNumbers[ba.NumberU64()] = true
+		bb := bByNumber[ba.NumberU64()]
+		switch {
+		case bb == nil:
+			d.OnlyA = append(d.OnlyA, blockID{ba.NumberU64(), ba.Hash()})
+		case bb.Hash() != ba.Hash():
+			d.Blocks = append(d.Blocks, diffBlocks(ba, bb))
+		}
+	}
+	for _, bb := range b {
+		if !aNumbers[bb.NumberU64()] {
+			d.OnlyB = append(d.OnlyB, blockID{bb.NumberU64(), bb.Hash()})
+		}
+	}
+	return d
+}
+
+// commonAncestor finds the highest block known to both chains. Parent hashes are
+// considered as well, so chains which differ in their first block still have an
+// ancestor.
+func commonAncestor(a, b []*types.Block) *blockID {
+	known := make(map[common.Hash]uint64)
+	for _, block := range a {
+		known[block.Hash()] = block.NumberU64()
+		if block.NumberU64() > 0 {
+			known[block.ParentHash()] = block.NumberU64() - 1
+		}
+	}
+	var ancestor *blockID
+	check := func(hash common.Hash, num uint64) {
+		if _, ok := known[hash]; ok && (ancestor == nil || num > ancestor.Number) {
+			ancestor = &blockID{num, hash}
+		}
+	}
+	for _, block := range b {
+		check(block.Hash(), block.NumberU64())
+		if block.NumberU64() > 0 {
+			check(block.ParentHash(), block.NumberU64()-1)
+		}
+	}
+	return ancestor
+}
+
+func diffBlocks(a, b *types.Block) *blockDiff {
+	d := &blockDiff{
+		Number:   a.NumberU64(),
+		A:        a.Hash(),
+		B:        b.Hash(),
+		TxCountA: len(a.Transactions()),
+		TxCountB: len(b.Transactions()),
+	}
+
+	// Compare header fields using their JSON encoding.
+	fa, fb := headerFields(a.Header()), headerFields(b.Header())
+	keys := maps.Keys(fa)
+	for k := range fb {
+		if _, ok := fa[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if k == "hash" {
+			continue
+		}
+		if va, vb := fa[k], fb[k]; va != vb {
+			d.Fields = append(d.Fields, fieldDiff{Name: k, A: va, B: vb})
+		}
+	}
+
+	// Compare transactions.
+	txsA := make(map[common.Hash]bool)
+	for _, tx := range a.Transactions() {
+		txsA[tx.Hash()] = true
+	}
+	txsB := make(map[common.Hash]bool)
+	for _, tx := range b.Transactions() {
+		txsB[tx.Hash()] = true
+		if !txsA[tx.Hash()] {
+			d.OnlyBTxs = append(d.OnlyBTxs, tx.Hash())
+		}
+	}
+	for _, tx := range a.Transactions() {
+		if !txsB[tx.Hash()] {
+			d.OnlyATxs = append(d.OnlyATxs, tx.Hash())
+		}
+	}
+	return d
+}
+
+// headerFields returns the JSON-encoded header fields. Missing fields are "null".
+func headerFields(h *types.Header) map[string]string {
+	enc, err := json.Marshal(h)
+	if err != nil {
+		panic(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(enc, &raw); err != nil {
+		panic(err)
+	}
+	m := make(map[string]string, len(raw))
+	for k, v := range raw {
+		m[k] = string(v)
+	}
+	return m
+}
+
+func (d *chainDiff) equal() bool {
+	return len(d.Blocks) == 0 && len(d.OnlyA) == 0 && len(d.OnlyB) == 0
+}
+
+// print writes a summary of the differences.
+func (d *chainDiff) print(w io.Writer) {
+	if d.Ancestor != nil {
+		fmt.Fprintf(w, "common ancestor: %d (%x)\n", d.Ancestor.Number, d.Ancestor.Hash)
+	} else {
+		fmt.Fprintln(w, "no common ancestor")
+	}
+	if d.equal() {
+		fmt.Fprintln(w, "chains are equal")
+		return
+	}
+	for _, bd := range d.Blocks {
+		fmt.Fprintf(w, "block %d: %x != %x\n", bd.Number, bd.A, bd.B)
+		for _, f := range bd.Fields {
+			fmt.Fprintf(w, "  %s: %s != %s\n", f.Name, f.A, f.B)
+		}
+		if bd.TxCountA != bd.TxCountB || len(bd.OnlyATxs) > 0 || len(bd.OnlyBTxs) > 0 {
+			fmt.Fprintf(w, "  txs: %d in a, %d in b, %d only in a, %d only in b\n", bd.TxCountA, bd.TxCountB, len(bd.OnlyATxs), len(bd.OnlyBTxs))
+		}
+		for _, h := range bd.OnlyATxs {
+			fmt.Fprintf(w, "    - %x\n", h)
+		}
+		for _, h := range bd.OnlyBTxs {
+			fmt.Fprintf(w, "    + %x\n", h)
+		}
+	}
+	printRange(w, "a", d.OnlyA)
+	printRange(w, "b", d.OnlyB)
+}
+
+func printRange(w io.Writer, name string, blocks []blockID) {
+	if len(blocks) > 0 {
+		fmt.Fprintf(w, "%d blocks only in %s (%d..%d)\n", len(blocks), name, blocks[0].Number, blocks[len(blocks)-1].Number)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffChains(t *testing.T) {
+	cfg := generatorConfig{
+		chainLength:  20,
+		txInterval:   1,
+		txCount:      2,
+		forkInterval: 1,
+		outputDir:    t.TempDir(),
+		outputs:      []string{},
+		sideChains:   []sideChainSpec{{Name: "side", Branch: 15, Length: 8}},
+	}
+	cfg, _ = cfg.withDefaults()
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+	canon := g.blockchain.GetBlocksFromHash(g.blockchain.CurrentBlock().Hash(), 20)
+	slices.Reverse(canon)
+	side := g.sideChains[0].blocks
+
+	d := diffChains(canon, canon)
+	if !d.equal() || d.Ancestor == nil || d.Ancestor.Number != 20 {
+		t.Fatalf("chain not equal to itself: %+v", d)
+	}
+
+	d = diffChains(canon, side)
+	if d.Ancestor == nil || d.Ancestor.Number != 15 || d.Ancestor.Hash != canon[14].Hash() {
+		t.Fatalf("wrong ancestor %+v", d.Ancestor)
+	}
+	if len(d.Blocks) != 5 {
+		t.Fatalf("wrong number of differing blocks: %d", len(d.Blocks))
+	}
+	if len(d.OnlyA) != 15 || len(d.OnlyB) != 3 || d.OnlyB[0].Number != 21 {
+		t.Fatalf("wrong extra blocks: a %v, b %v", d.OnlyA, d.OnlyB)
+	}
+	first := d.Blocks[0]
+	if !slices.ContainsFunc(first.Fields, func(f fieldDiff) bool { return f.Name == "extraData" }) {
+		t.Errorf("extraData difference not reported: %+v", first.Fields)
+	}
+	if len(first.OnlyATxs) != first.TxCountA || len(first.OnlyBTxs) == 0 {
+		t.Errorf("wrong tx differences: %+v", first)
+	}
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/golang/snappy"
+)
+
+// This file implements the era1 archive format. An era1 file is an e2store file
+// containing up to 8192 blocks with their receipts and total difficulty:
+//
+//	era1        := Version | block-tuple* | Accumulator | BlockIndex
+//	block-tuple := CompressedHeader | CompressedBody | CompressedReceipts | TotalDifficulty
+//
+// See https://github.com/eth-clients/e2store-format-specs/blob/main/formats/era1.md
+
+const (
+	e2TypeVersion            = 0x3265
+	e2TypeCompressedHeader   = 0x03
+	e2TypeCompressedBody     = 0x04
+	e2TypeCompressedReceipts = 0x05
+	e2TypeTotalDifficulty    = 0x06
+	e2TypeAccumulator        = 0x07
+	e2TypeBlockIndex         = 0x3266
+
+	e2HeaderSize = 8
+
+	era1MaxBlocks = 8192
+	era1Network   = "hivechain"
+)
+
+// era1Filename returns the file name of an era1 archive.
+func era1Filename(epoch int, root common.Hash) string {
+	return fmt.Sprintf("%s-%05d-%x.era1", era1Network, epoch, root[:4])
+}
+
+// era1Builder creates an era1 archive.
+type era1Builder struct {
+	buf     bytes.Buffer
+	start   uint64
+	offsets []int
+	hashes  []common.Hash
+	tds     []*big.Int
+}
+
+// add appends a block to the archive.
+func (b *era1Builder) add(block *types.Block, receipts types.Receipts, td *big.Int) error {
+	if len(b.hashes) == era1MaxBlocks {
+		return fmt.Errorf("era1 archive is full")
+	}
+	if len(b.hashes) == 0 {
+		b.start = block.NumberU64()
+		writeE2Entry(&b.buf, e2TypeVersion, nil)
+	}
+	header, err := rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		return err
+	}
+	body, err := rlp.EncodeToBytes(block.Body())
+	if err != nil {
+		return err
+	}
+	rcpt, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		return err
+	}
+	b.offsets = append(b.offsets, b.buf.Len())
+	b.hashes = append(b.hashes, block.Hash())
+	b.tds = append(b.tds, new(big.Int).Set(td))
+	writeE2Entry(&b.buf, e2TypeCompressedHeader, snappyEncode(header))
+	writeE2Entry(&b.buf, e2TypeCompressedBody, snappyEncode(body))
+	writeE2Entry(&b.buf, e2TypeCompressedReceipts, snappyEncode(rcpt))
+	writeE2Entry(&b.buf, e2TypeTotalDifficulty, uint256LE(td))
+	return nil
+}
+
+// finish writes the accumulator and block index, and returns the archive content.
+func (b *era1Builder) finish() ([]byte, common.Hash) {
+	root := era1Accumulator(b.hashes, b.tds)
+	writeE2Entry(&b.buf, e2TypeAccumulator, root[:])
+
+	// Block offsets are relative to the start of the index entry.
+	indexPos := b.buf.Len()
+	index := make([]byte, 16+8*len(b.offsets))
+	binary.LittleEndian.PutUint64(index, b.start)
+	for i, offset := range b.offsets {
+		binary.LittleEndian.PutUint64(index[8+8*i:], uint64(int64(offset-indexPos)))
+	}
+	binary.LittleEndian.PutUint64(index[8+8*len(b.offsets):], uint64(len(b.offsets)))
+	writeE2Entry(&b.buf, e2TypeBlockIndex, index)
+	return b.buf.Bytes(), root
+}
+
+// era1Archive is the decoded content of an era1 file.
+type era1Archive struct {
+	start       uint64
+	blocks      []*types.Block
+	receipts    []types.Receipts
+	tds         []*big.Int
+	accumulator common.Hash
+}
+
+// readEra1 decodes an era1 archive and checks its index and accumulator.
+func readEra1(data []byte) (*era1Archive, error) {
+	var (
+		a       era1Archive
+		offsets []int
+		index   []byte
+		pos     int
+	)
+	for pos < len(data) {
+		typ, value, err := readE2Entry(data[pos:])
+		if err != nil {
+			return nil, fmt.Errorf("entry at offset %d: %v", pos, err)
+		}
+		switch {
+		case pos == 0:
+			if typ != e2TypeVersion {
+				return nil, errors.New("missing version entry")
+			}
+		case index != nil:
+			return nil, errors.New("unexpected entry after block index")
+		case typ == e2TypeCompressedHeader:
+			if a.accumulator != (common.Hash{}) {
+				return nil, errors.New("unexpected block after accumulator")
+			}
+			offsets = append(offsets, pos)
+			n, err := a.readBlock(data[pos:])
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %v", len(a.blocks), err)
+			}
+			pos += n
+			continue
+		case typ == e2TypeAccumulator:
+			if len(value) != 32 {
+				return nil, fmt.Errorf("invalid accumulator length %d", len(value))
+			}
+			a.accumulator = common.BytesToHash(value)
+		case typ == e2TypeBlockIndex:
+			index = value
+			if err := checkEra1Index(index, pos, offsets); err != nil {
+				return nil, err
+			}
+			a.start = binary.LittleEndian.Uint64(index)
+		}
+		pos += e2HeaderSize + len(value)
+	}
+
+	if len(a.blocks) == 0 {
+		return nil, errors.New("archive contains no blocks")
+	}
+	if index == nil {
+		return nil, errors.New("missing block index")
+	}
+	for i, b := range a.blocks {
+		if b.NumberU64() != a.start+uint64(i) {
+			return nil, fmt.Errorf("block %d has number %d, index starts at %d", i, b.NumberU64(), a.start)
+		}
+	}
+	hashes := make([]common.Hash, len(a.blocks))
+	for i, b := range a.blocks {
+		hashes[i] = b.Hash()
+	}
+	if root := era1Accumulator(hashes, a.tds); root != a.accumulator {
+		return nil, fmt.Errorf("accumulator mismatch: archive has %x, computed %x", a.accumulator, root)
+	}
+	return &a, nil
+}
+
+// readBlock decodes a block tuple, returning its size.
+func (a *era1Archive) readBlock(data []byte) (int, error) {
+	var (
+		pos     int
+		entries [4][]byte
+	)
+	kinds := []uint16{e2TypeCompressedHeader, e2TypeCompressedBody, e2TypeCompressedReceipts, e2TypeTotalDifficulty}
+	for i, kind := range kinds {
+		typ, value, err := readE2Entry(data[pos:])
+		if err != nil {
+			return 0, err
+		}
+		if typ != kind {
+			return 0, fmt.Errorf("unexpected entry type %#x, want %#x", typ, kind)
+		}
+		entries[i] = value
+		pos += e2HeaderSize + len(value)
+	}
+
+	var (
+		header   types.Header
+		body     types.Body
+		receipts types.Receipts
+	)
+	if err := snappyDecode(entries[0], &header); err != nil {
+		return 0, fmt.Errorf("invalid header: %v", err)
+	}
+	if err := snappyDecode(entries[1], &body); err != nil {
+		return 0, fmt.Errorf("invalid body: %v", err)
+	}
+	if err := snappyDecode(entries[2], &receipts); err != nil {
+		return 0, fmt.Errorf("invalid receipts: %v", err)
+	}
+	if len(entries[3]) != 32 {
+		return 0, fmt.Errorf("invalid total difficulty length %d", len(entries[3]))
+	}
+	tdBytes := slices.Clone(entries[3])
+	slices.Reverse(tdBytes)
+	td := new(big.Int).SetBytes(tdBytes)
+	a.blocks = append(a.blocks, types.NewBlockWithHeader(&header).WithBody(body))
+	a.receipts = append(a.receipts, receipts)
+	a.tds = append(a.tds, td)
+	return pos, nil
+}
+
+// checkEra1Index verifies the block index entry at position pos points to the blocks.
+func checkEra1Index(index []byte, pos int, offsets []int) error {
+	if len(index) < 16 || len(index)%8 != 0 {
+		return fmt.Errorf("invalid block index length %d", len(index))
+	}
+	count := binary.LittleEndian.Uint64(index[len(index)-8:])
+	if count != uint64(len(offsets)) || len(index) != 16+8*len(offsets) {
+		return fmt.Errorf("block index has %d entries, archive has %d blocks", count, len(offsets))
+	}
+	for i, offset := range offsets {
+		rel := int64(binary.LittleEndian.Uint64(index[8+8*i:]))
+		if rel != int64(offset-pos) {
+			return fmt.Errorf("block index entry %d has offset %d, want %d", i, rel, offset-pos)
+		}
+	}
+	return nil
+}
+
+// readEra1Dir reads all era1 archives in a directory. The archives must form a
+// contiguous chain, and their file names must match the accumulator root.
+func readEra1Dir(dir string) ([]*era1Archive, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.era1"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no era1 files in %s", dir)
+	}
+	slices.Sort(files)
+	checksums, err := readEra1Checksums(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var archives []*era1Archive
+	for epoch, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		a, err := readEra1(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if name := era1Filename(epoch, a.accumulator); filepath.Base(file) != name {
+			return nil, fmt.Errorf("%s: wrong file name, want %s", file, name)
+		}
+		if checksums != nil {
+			sum := common.Hash(sha256.Sum256(data))
+			if epoch >= len(checksums) || checksums[epoch] != sum {
+				return nil, fmt.Errorf("%s: checksum mismatch", file)
+			}
+		}
+		if err := checkEra1Blocks(a, archives); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		archives = append(archives, a)
+	}
+	if checksums != nil && len(checksums) != len(archives) {
+		return nil, fmt.Errorf("checksums.txt has %d entries, found %d era1 files", len(checksums), len(archives))
+	}
+	return archives, nil
+}
+
+// checkEra1Blocks verifies the receipts and total difficulty values of an archive.
+func checkEra1Blocks(a *era1Archive, prev []*era1Archive) error {
+	if len(prev) == 0 {
+		if a.start != 0 {
+			return fmt.Errorf("first archive starts at block %d", a.start)
+		}
+	} else if last := prev[len(prev)-1]; a.start != last.start+uint64(len(last.blocks)) {
+		return fmt.Errorf("archive starts at block %d, previous archive ends at %d", a.start, last.start+uint64(len(last.blocks))-1)
+	}
+	for i, b := range a.blocks {
+		if h := types.DeriveSha(a.receipts[i], trie.NewStackTrie(nil)); h != b.ReceiptHash() {
+			return fmt.Errorf("block %d: receipts root mismatch: header has %x, receipts have %x", b.Number(), b.ReceiptHash(), h)
+		}
+		var parentTD *big.Int
+		switch {
+		case i > 0:
+			parentTD = a.tds[i-1]
+		case len(prev) > 0:
+			last := prev[len(prev)-1]
+			parentTD = last.tds[len(last.tds)-1]
+		default:
+			parentTD = new(big.Int)
+		}
+		if want := new(big.Int).Add(parentTD, b.Difficulty()); a.tds[i].Cmp(want) != 0 {
+			return fmt.Errorf("block %d: total difficulty %v, want %v", b.Number(), a.tds[i], want)
+		}
+	}
+	return nil
+}
+
+// readEra1Checksums reads checksums.txt. It returns nil if the file does not exist.
+func readEra1Checksums(dir string) ([]common.Hash, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "checksums.txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var sums []common.Hash
+	for _, line := range strings.Fields(string(content)) {
+		var h common.Hash
+		if err := h.UnmarshalText([]byte(line)); err != nil {
+			return nil, fmt.Errorf("invalid checksums.txt: %v", err)
+		}
+		sums = append(sums, h)
+	}
+	return sums, nil
+}
+
+// era1Accumulator computes the SSZ hash tree root of the header records, a list of
+// (block-hash, total-difficulty) containers with a limit of 8192 entries.
+func era1Accumulator(hashes []common.Hash, tds []*big.Int) common.Hash {
+	layer := make([][32]byte, len(hashes))
+	for i := range hashes {
+		layer[i] = sha256.Sum256(append(hashes[i][:], uint256LE(tds[i])...))
+	}
+	var zero [32]byte
+	for size := era1MaxBlocks; size > 1; size /= 2 {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zero)
+		}
+		next := make([][32]byte, len(layer)/2)
+		for i := range next {
+			next[i] = sha256.Sum256(append(layer[2*i][:], layer[2*i+1][:]...))
+		}
+		layer = next
+		zero = sha256.Sum256(append(zero[:], zero[:]...))
+	}
+	root := zero
+	if len(layer) > 0 {
+		root = layer[0]
+	}
+	var length [32]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(len(hashes)))
+	return sha256.Sum256(append(root[:], length[:]...))
+}
+
+// writeE2Entry appends an e2store entry to buf.
+func writeE2Entry(buf *bytes.Buffer, typ uint16, value []byte) {
+	var header [e2HeaderSize]byte
+	binary.LittleEndian.PutUint16(header[:], typ)
+	binary.LittleEndian.PutUint32(header[2:], uint32(len(value)))
+	buf.Write(header[:])
+	buf.Write(value)
+}
+
+// readE2Entry decodes the e2store entry at the start of data.
+func readE2Entry(data []byte) (typ uint16, value []byte, err error) {
+	if len(data) < e2HeaderSize {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	typ = binary.LittleEndian.Uint16(data)
+	length := int(binary.LittleEndian.Uint32(data[2:]))
+	if reserved := binary.LittleEndian.Uint16(data[6:]); reserved != 0 {
+		return 0, nil, fmt.Errorf("non-zero reserved bytes in entry header")
+	}
+	if len(data) < e2HeaderSize+length {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return typ, data[e2HeaderSize : e2HeaderSize+length], nil
+}
+
+// snappyEncode compresses data in the snappy framing format.
+func snappyEncode(data []byte) []byte {
+	var buf bytes.Buffer
+	w := snappy.NewBufferedWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+// snappyDecode decompresses a snappy-framed RLP value.
+func snappyDecode(data []byte, v any) error {
+	content, err := io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return err
+	}
+	return rlp.DecodeBytes(content, v)
+}
+
+// uint256LE encodes a number as 32 little-endian bytes.
+func uint256LE(v *big.Int) []byte {
+	b := v.FillBytes(make([]byte, 32))
+	slices.Reverse(b)
+	return b
+}
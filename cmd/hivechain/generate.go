@@ -15,7 +15,9 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/triedb"
 	"golang.org/x/exp/slices"
 )
@@ -23,9 +25,12 @@ import (
 // generatorConfig is the configuration of the chain generator.
 type generatorConfig struct {
 	// genesis options
-	forkInterval int    // number of blocks between forks
-	lastFork     string // last enabled fork
-	merged       bool   // create a proof-of-stake chain
+	forkInterval  int                // number of blocks between forks
+	lastFork      string             // last enabled fork
+	merged        bool               // create a proof-of-stake chain
+	forkSchedule  *forkSchedule      // explicit fork activations (overrides the above)
+	alloc         types.GenesisAlloc // additional genesis accounts
+	extraAccounts []genAccount       // additional funded accounts
 
 	// chain options
 	txInterval        int    // frequency of blocks containing transactions
@@ -34,6 +39,13 @@ type generatorConfig struct {
 	gasLimit          uint64 // block gas limit
 	finalizedDistance int    // distance of finalized block from head
 
+	gasLimitChanges []gasLimitChange // gas limit targets
+	modifiers       []modifierSpec   // enabled modifiers (nil = all, except opt-in)
+	extraModifiers  []string         // opt-in modifiers enabled in addition
+	fuzz            bool             // enable random transactions
+	fuzzSeed        int64            // seed of fuzz mode
+	sideChains      []sideChainSpec  // side chains to generate
+
 	// output options
 	outputs   []string // enabled outputs
 	outputDir string   // path where output files should be placed
@@ -52,9 +64,27 @@ func (cfg generatorConfig) withDefaults() (generatorConfig, error) {
 	if cfg.gasLimit == 0 {
 		cfg.gasLimit = defaultGasLimit
 	}
+	for _, name := range cfg.extraModifiers {
+		if _, ok := modRegistry[name]; !ok {
+			return cfg, fmt.Errorf("unknown modifier %q", name)
+		}
+	}
 	return cfg, nil
 }
 
+// modifierEnabled reports whether the named block modifier runs.
+func (cfg *generatorConfig) modifierEnabled(name string) bool {
+	if slices.Contains(cfg.extraModifiers, name) {
+		return true
+	}
+	if cfg.modifiers != nil {
+		return slices.ContainsFunc(cfg.modifiers, func(spec modifierSpec) bool {
+			return spec.Name == name
+		})
+	}
+	return !cfg.fuzz && !optInMods[name]
+}
+
 // generator is the central object in the chain generation process.
 // It holds the configuration, state, and all instantiated transaction generators.
 type generator struct {
@@ -70,15 +100,42 @@ type generator struct {
 	modOffset int
 
 	// for write/export
-	blockchain *core.BlockChain
-	clRequests map[uint64][][]byte
+	blockchain    *core.BlockChain
+	sideChains    []*sideChain
+	sidecars      map[common.Hash]*blobSidecar // blob sidecars by tx hash
+	clRequests    map[common.Hash][][]byte     // execution requests by block hash
+	blockRequests map[uint64][][]byte          // requests of blocks being generated
 }
 
 type modifierInstance struct {
-	name string
+	name     string
+	from, to uint64 // block range, to == 0 means no limit
 	blockModifier
 }
 
+// active reports whether the modifier may run in the given block.
+func (m *modifierInstance) active(num uint64) bool {
+	return num >= m.from && (m.to == 0 || num <= m.to)
+}
+
+// blobSidecar is the sidecar of a blob transaction in the chain.
+type blobSidecar struct {
+	sidecar *types.BlobTxSidecar
+	osaka   bool // included after Osaka
+}
+
+// get returns the sidecar. For transactions included after Osaka, the sidecar is
+// converted to carry cell proofs. This is done lazily because computing cell proofs
+// is expensive.
+func (sc *blobSidecar) get() *types.BlobTxSidecar {
+	if sc.osaka {
+		if err := sc.sidecar.ToV1(); err != nil {
+			panic(err)
+		}
+	}
+	return sc.sidecar
+}
+
 type genAccount struct {
 	addr common.Address
 	key  *ecdsa.PrivateKey
@@ -92,21 +149,50 @@ func newGenerator(cfg generatorConfig) *generator {
 		rand:       rand.New(rand.NewSource(10)),
 		td:         new(big.Int).Set(genesis.Difficulty),
 		virgins:    cfg.createBlockModifiers(),
-		accounts:   slices.Clone(knownAccounts),
-		clRequests: make(map[uint64][][]byte),
+		accounts:   append(slices.Clone(knownAccounts), cfg.extraAccounts...),
+		clRequests: make(map[common.Hash][][]byte),
+		sidecars:   make(map[common.Hash]*blobSidecar),
 	}
 }
 
 func (cfg *generatorConfig) createBlockModifiers() (list []*modifierInstance) {
-	for name, new := range modRegistry {
+	// In fuzz mode, the other modifiers only run when enabled explicitly.
+	if cfg.fuzz {
 		list = append(list, &modifierInstance{
-			name:          name,
-			blockModifier: new(),
+			name:          "fuzz",
+			blockModifier: newModFuzz(cfg.fuzzSeed),
+		})
+	}
+	specs := cfg.modifiers
+	if specs == nil && !cfg.fuzz {
+		for name := range modRegistry {
+			if !optInMods[name] {
+				specs = append(specs, modifierSpec{Name: name})
+			}
+		}
+		slices.SortFunc(specs, func(a, b modifierSpec) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+	for _, name := range cfg.extraModifiers {
+		if !slices.ContainsFunc(specs, func(spec modifierSpec) bool { return spec.Name == name }) {
+			specs = append(specs, modifierSpec{Name: name})
+		}
+	}
+	for _, spec := range specs {
+		mod := modRegistry[spec.Name]()
+		if len(spec.Params) > 0 {
+			if err := mod.(configurableModifier).configure(spec.Params); err != nil {
+				panic(fmt.Sprintf("modifier %q: %v", spec.Name, err))
+			}
+		}
+		list = append(list, &modifierInstance{
+			name:          spec.Name,
+			from:          spec.From,
+			to:            spec.To,
+			blockModifier: mod,
 		})
 	}
-	slices.SortFunc(list, func(a, b *modifierInstance) int {
-		return strings.Compare(a.name, b.name)
-	})
 	return list
 }
 
@@ -120,19 +206,30 @@ func (g *generator) run() error {
 	trieconfig.Preimages = true
 	triedb := triedb.NewDatabase(db, &trieconfig)
 	genesis := g.genesis.MustCommit(db, triedb)
-	g.clRequests[0] = [][]byte{}
+	g.clRequests[genesis.Hash()] = [][]byte{}
 
 	// Create the blocks.
-	chain, _ := core.GenerateChain(g.genesis.Config, genesis, engine, db, g.cfg.chainLength, g.modifyBlock)
+	var genEngine consensus.Engine = engine
+	if len(g.cfg.gasLimitChanges) > 0 {
+		genEngine = &gasLimitEngine{Engine: engine, changes: g.cfg.gasLimitChanges}
+	}
+	g.blockRequests = make(map[uint64][][]byte)
+	chain, _ := core.GenerateChain(g.genesis.Config, genesis, genEngine, db, g.cfg.chainLength, g.modifyBlock)
+	g.storeRequests(chain)
 
 	// Import the chain. This runs all block validation rules.
 	bc, err := g.importChain(engine, chain)
 	if err != nil {
 		return err
 	}
+	g.blockchain = bc
+
+	// Create side chains.
+	if err := g.generateSideChains(db, genEngine); err != nil {
+		return err
+	}
 
 	// Write output files.
-	g.blockchain = bc
 	if err := g.write(); err != nil {
 		return err
 	}
@@ -150,10 +247,27 @@ func (g *generator) run() error {
 }
 
 func (g *generator) importChain(engine consensus.Engine, chain []*types.Block) (*core.BlockChain, error) {
+	blockchain, err := importBlocks(g.genesis, engine, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set finalized block.
+	headNum := blockchain.CurrentHeader().Number.Uint64()
+	finalizedNum := uint64(0)
+	if headNum > uint64(g.cfg.finalizedDistance) {
+		finalizedNum = headNum - uint64(g.cfg.finalizedDistance)
+	}
+	blockchain.SetFinalized(blockchain.GetHeaderByNumber(finalizedNum))
+	return blockchain, nil
+}
+
+// importBlocks creates an in-memory blockchain and inserts the given blocks.
+func importBlocks(genesis *core.Genesis, engine consensus.Engine, chain []*types.Block) (*core.BlockChain, error) {
 	db := rawdb.NewMemoryDatabase()
 	config := core.DefaultConfig().WithStateScheme("hash")
 	config.Preimages = true
-	blockchain, err := core.NewBlockChain(db, g.genesis, engine, config)
+	blockchain, err := core.NewBlockChain(db, genesis, engine, config)
 	if err != nil {
 		return nil, fmt.Errorf("can't create blockchain: %v", err)
 	}
@@ -162,16 +276,11 @@ func (g *generator) importChain(engine consensus.Engine, chain []*types.Block) (
 	i, err := blockchain.InsertChain(chain)
 	if err != nil {
 		blockchain.Stop()
-		return nil, fmt.Errorf("chain validation error (block %d): %v", chain[i].Number(), err)
-	}
-
-	// Set finalized block.
-	headNum := blockchain.CurrentHeader().Number.Uint64()
-	finalizedNum := uint64(0)
-	if headNum > uint64(g.cfg.finalizedDistance) {
-		finalizedNum = headNum - uint64(g.cfg.finalizedDistance)
+		if i < len(chain) {
+			return nil, fmt.Errorf("chain validation error (block %d, %x): %v", chain[i].Number(), chain[i].Hash(), err)
+		}
+		return nil, fmt.Errorf("chain validation error: %v", err)
 	}
-	blockchain.SetFinalized(blockchain.GetHeaderByNumber(finalizedNum))
 	return blockchain, nil
 }
 
@@ -180,7 +289,15 @@ func (g *generator) modifyBlock(i int, gen *core.BlockGen) {
 	g.setDifficulty(gen)
 	g.setParentBeaconRoot(gen)
 	g.runModifiers(i, gen)
-	g.clRequests[gen.Number().Uint64()] = gen.ConsensusLayerRequests()
+	g.blockRequests[gen.Number().Uint64()] = gen.ConsensusLayerRequests()
+}
+
+// storeRequests assigns the execution requests collected during block generation
+// to the generated blocks.
+func (g *generator) storeRequests(blocks []*types.Block) {
+	for _, b := range blocks {
+		g.clRequests[b.Hash()] = g.blockRequests[b.NumberU64()]
+	}
 }
 
 func (g *generator) setDifficulty(gen *core.BlockGen) {
@@ -223,7 +340,7 @@ func (g *generator) runModifiers(i int, gen *core.BlockGen) {
 	count := 0
 	refused := 0 // count of consecutive times apply() returned false
 	run := func(mod *modifierInstance) bool {
-		ok := mod.apply(ctx)
+		ok := mod.active(ctx.NumberU64()) && mod.apply(ctx)
 		if ok {
 			fmt.Println("    -", mod.name)
 			count++
@@ -251,3 +368,35 @@ func (g *generator) runModifiers(i int, gen *core.BlockGen) {
 		g.modOffset++
 	}
 }
+
+// gasLimitEngine wraps the consensus engine to apply gas limit changes. The gas limit of
+// each block is moved towards the configured target by the maximum amount allowed by the
+// protocol. The adjustment happens when the block is finalized, so the limit never drops
+// below the gas used by the block.
+type gasLimitEngine struct {
+	consensus.Engine
+	changes []gasLimitChange
+}
+
+func (e *gasLimitEngine) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, body *types.Body, receipts []*types.Receipt) (*types.Block, error) {
+	if target, ok := e.target(header.Number.Uint64()); ok {
+		step := header.GasLimit/params.GasLimitBoundDivisor - 1
+		switch {
+		case header.GasLimit < target:
+			header.GasLimit = min(header.GasLimit+step, target)
+		case header.GasLimit > target:
+			header.GasLimit = max(header.GasLimit-step, target, header.GasUsed, params.MinGasLimit)
+		}
+	}
+	return e.Engine.FinalizeAndAssemble(chain, header, state, body, receipts)
+}
+
+// target returns the gas limit target at the given block.
+func (e *gasLimitEngine) target(num uint64) (uint64, bool) {
+	for i := len(e.changes) - 1; i >= 0; i-- {
+		if num >= e.changes[i].Block {
+			return e.changes[i].GasLimit, true
+		}
+	}
+	return 0, false
+}
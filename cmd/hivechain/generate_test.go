@@ -2,7 +2,10 @@ package main
 
 import (
 	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestGenerate(t *testing.T) {
@@ -27,3 +30,39 @@ func TestGenerate(t *testing.T) {
 	names, _ := filepath.Glob(filepath.Join(outdir, "*"))
 	t.Log("output files:", names)
 }
+
+// This checks that opt-in modifiers and their genesis contracts are only present when
+// they are enabled.
+func TestOptInModifiers(t *testing.T) {
+	genAccs := common.HexToAddress(genAccsAddr)
+	names := func(cfg generatorConfig) []string {
+		var list []string
+		for _, m := range cfg.createBlockModifiers() {
+			list = append(list, m.name)
+		}
+		return list
+	}
+
+	cfg, _ := generatorConfig{}.withDefaults()
+	for _, name := range names(cfg) {
+		if optInMods[name] {
+			t.Errorf("opt-in modifier %q enabled by default", name)
+		}
+	}
+	if _, ok := cfg.createGenesis().Alloc[genAccs]; ok {
+		t.Error("genaccounts contract in default genesis")
+	}
+
+	cfg.extraModifiers = []string{"state-accounts", "tx-blobs"}
+	if list := names(cfg); !slices.Contains(list, "state-accounts") || !slices.Contains(list, "tx-blobs") || len(list) != len(modRegistry)-len(optInMods)+2 {
+		t.Errorf("wrong modifiers with -extra-modifiers: %v", list)
+	}
+	if _, ok := cfg.createGenesis().Alloc[genAccs]; !ok {
+		t.Error("genaccounts contract missing with state-accounts enabled")
+	}
+
+	cfg.extraModifiers = []string{"foo"}
+	if _, err := cfg.withDefaults(); err == nil {
+		t.Error("no error for unknown modifier")
+	}
+}
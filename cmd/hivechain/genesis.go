@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"maps"
 	"math/big"
 	"strings"
 
@@ -67,7 +68,7 @@ func (cfg *generatorConfig) createChainConfig() *params.ChainConfig {
 		chaincfg.BlobScheduleConfig = new(params.BlobScheduleConfig)
 	}
 	for fork, b := range forks {
-		timestamp := cfg.blockTimestamp(b)
+		timestamp := cfg.forkTimestamp(fork, b)
 
 		switch fork {
 		// number-based forks
@@ -148,10 +149,22 @@ func (cfg *generatorConfig) createGenesis() *core.Genesis {
 	for _, acc := range knownAccounts {
 		g.Alloc[acc.addr] = types.Account{Balance: initialBalance}
 	}
+	for _, acc := range cfg.extraAccounts {
+		g.Alloc[acc.addr] = types.Account{Balance: initialBalance}
+	}
 	addCancunSystemContracts(g.Alloc)
 	addPragueSystemContracts(g.Alloc)
 	addSnapTestContract(g.Alloc)
 	addModContracts(g.Alloc)
+	if cfg.modifierEnabled("state-accounts") {
+		addStateContracts(g.Alloc)
+	}
+	if cfg.fuzz {
+		addFuzzContracts(g.Alloc)
+	}
+	for addr, acc := range cfg.alloc {
+		g.Alloc[addr] = acc
+	}
 
 	return &g
 }
@@ -186,6 +199,8 @@ func addSnapTestContract(ga types.GenesisAlloc) {
 const (
 	emitAddr      = "0x7dcd17433742f4c0ca53122ab541d0ba67fc27df"
 	largeLogsAddr = "0x8dcd17433742f4c0ca53122ab541d0ba67fc27ff"
+	genAccsAddr   = "0x9dcd17433742f4c0ca53122ab541d0ba67fc27aa"
+	fuzzAddr      = "0xadcd17433742f4c0ca53122ab541d0ba67fc27f0"
 )
 
 // addModContracts adds the contracts used by block modifiers.
@@ -200,10 +215,46 @@ func addModContracts(ga types.GenesisAlloc) {
 	}
 }
 
+// addStateContracts adds the contracts used by the opt-in state modifiers. Like the fuzz
+// contracts, they are not part of the default genesis.
+func addStateContracts(ga types.GenesisAlloc) {
+	// The account generator contract pays 1 wei to each created account.
+	ga[common.HexToAddress(genAccsAddr)] = types.Account{
+		Code:    genaccountsCode,
+		Balance: initialBalance,
+	}
+}
+
+// addFuzzContracts adds the contracts used in fuzz mode. They are not part of the
+// default genesis, so that enabling fuzz mode does not change regular chains.
+func addFuzzContracts(ga types.GenesisAlloc) {
+	ga[common.HexToAddress(fuzzAddr)] = types.Account{
+		Code:    fuzzCode,
+		Balance: new(big.Int),
+	}
+	for _, acc := range fuzzAuthorities {
+		ga[acc.addr] = types.Account{Balance: big.NewInt(1)}
+	}
+}
+
 // forkBlocks computes the block numbers where forks occur. Forks get enabled based on the
 // forkInterval. If the total number of requested blocks (chainLength) is lower than
 // necessary, the remaining forks activate on the last chain block.
+//
+// When an explicit fork schedule is configured, it is used instead. Forks scheduled by
+// timestamp are assigned the block number where they activate.
 func (cfg *generatorConfig) forkBlocks() map[string]uint64 {
+	if cfg.forkSchedule != nil {
+		forkBlocks := maps.Clone(cfg.forkSchedule.Blocks)
+		if forkBlocks == nil {
+			forkBlocks = make(map[string]uint64)
+		}
+		for fork, t := range cfg.forkSchedule.Timestamps {
+			forkBlocks[fork] = (t + blocktimeSec - 1) / blocktimeSec
+		}
+		return forkBlocks
+	}
+
 	lastIndex := cfg.lastForkIndex()
 	forks := allForkNames[:lastIndex+1]
 	forkBlocks := make(map[string]uint64)
@@ -248,6 +299,16 @@ func (cfg *generatorConfig) lastForkIndex() int {
 	return index
 }
 
+// forkTimestamp returns the activation time of a fork scheduled at the given block.
+func (cfg *generatorConfig) forkTimestamp(fork string, block uint64) uint64 {
+	if cfg.forkSchedule != nil {
+		if t, ok := cfg.forkSchedule.Timestamps[fork]; ok {
+			return t
+		}
+	}
+	return cfg.blockTimestamp(block)
+}
+
 func (cfg *generatorConfig) blockTimestamp(num uint64) uint64 {
 	return num * blocktimeSec
 }
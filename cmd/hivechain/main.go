@@ -4,10 +4,30 @@
 //
 //	hivechain generate -length 10 -genesis ./genesis.json -blocktime 30 -output .
 //
+// Fuzz mode fills blocks with random transactions, deterministically for a seed:
+//
+//	hivechain generate -fuzz -seed 7 -length 100 -tx-count 20 -outdir .
+//
+// The chain can also be configured by a spec file:
+//
+//	hivechain generate -spec chain.yaml -outdir .
+//
 // The 'print' subcommand displays blocks in a chain.rlp file:
 //
 //	hivechain print -v chain.rlp
 //
+// The 'verify' subcommand imports a chain.rlp file to check that all blocks are valid:
+//
+//	hivechain verify genesis.json chain.rlp
+//
+// It also accepts a directory of era1 archives created by the 'era1' output:
+//
+//	hivechain verify genesis.json era1/
+//
+// The 'diff' subcommand compares two chain.rlp files:
+//
+//	hivechain diff a.rlp b.rlp
+//
 // The 'print-genesis' subcommand displays the block header fields of a genesis.json file:
 //
 //	hivechain print-genesis genesis.json
@@ -49,6 +69,10 @@ func main() {
 		generateCommand(os.Args[2:])
 	case "print":
 		printCommand(os.Args[2:])
+	case "verify":
+		verifyCommand(os.Args[2:])
+	case "diff":
+		diffCommand(os.Args[2:])
 	default:
 		flag.Usage()
 		os.Exit(1)
@@ -58,8 +82,10 @@ func main() {
 // generateCommand generates a test chain.
 func generateCommand(args []string) {
 	var (
-		cfg     generatorConfig
-		outlist = flag.String("outputs", "", "Enabled output modules")
+		cfg      generatorConfig
+		outlist  = flag.String("outputs", "", "Enabled output modules")
+		modlist  = flag.String("extra-modifiers", "", "Opt-in block modifiers to enable")
+		specfile = flag.String("spec", "", "Chain spec file (YAML or JSON)")
 	)
 	flag.IntVar(&cfg.chainLength, "length", 2, "The length of the pow chain to generate")
 	flag.Uint64Var(&cfg.gasLimit, "gaslimit", defaultGasLimit, "Block gas limit of the chain")
@@ -70,8 +96,24 @@ func generateCommand(args []string) {
 	flag.StringVar(&cfg.outputDir, "outdir", ".", "Destination directory")
 	flag.StringVar(&cfg.lastFork, "lastfork", "", "Name of the last fork to activate")
 	flag.BoolVar(&cfg.merged, "pos", false, "Create a PoS (merged) chain")
+	flag.BoolVar(&cfg.fuzz, "fuzz", false, "Fill blocks with random transactions")
+	flag.Int64Var(&cfg.fuzzSeed, "seed", 1, "Random seed for -fuzz")
 	flag.CommandLine.Parse(args)
 
+	// Apply the spec file. Flags are parsed again afterwards, so that options given on
+	// the command line override the spec.
+	if *specfile != "" {
+		spec, err := loadChainSpec(*specfile)
+		if err != nil {
+			fatal(err)
+		}
+		spec.apply(&cfg)
+		flag.CommandLine.Parse(args)
+	}
+
+	if *modlist != "" {
+		cfg.extraModifiers = splitAndTrim(*modlist)
+	}
 	if *outlist != "" {
 		if *outlist == "all" {
 			cfg.outputs = outputFunctionNames()
@@ -91,7 +133,7 @@ func generateCommand(args []string) {
 
 func usage() {
 	o := flag.CommandLine.Output()
-	fmt.Fprintln(o, "Usage: hivechain generate|print [options...]")
+	fmt.Fprintln(o, "Usage: hivechain generate|print|verify|diff [options...]")
 	flag.PrintDefaults()
 	fmt.Fprintln(o, "")
 	fmt.Fprintln(o, "List of available -outputs:")
@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -16,18 +17,31 @@ type blockModifier interface {
 	txInfo() any
 }
 
-var modRegistry = make(map[string]func() blockModifier)
+var (
+	modRegistry = make(map[string]func() blockModifier)
+	optInMods   = make(map[string]bool)
+)
 
 // register adds a block modifier.
 func register(name string, new func() blockModifier) {
 	modRegistry[name] = new
 }
 
+// registerOptIn adds a block modifier which does not run by default. It runs only when
+// listed in the modifiers of the spec file, or given in the -extra-modifiers flag.
+// Modifiers which are expensive or need additional genesis accounts are registered this
+// way, so that they do not change the chains created with default settings.
+func registerOptIn(name string, new func() blockModifier) {
+	modRegistry[name] = new
+	optInMods[name] = true
+}
+
 type genBlockContext struct {
-	index   int
-	block   *core.BlockGen
-	gen     *generator
-	txcount int
+	index     int
+	block     *core.BlockGen
+	gen       *generator
+	txcount   int
+	blobcount int
 }
 
 // Number returns the block number.
@@ -50,12 +64,24 @@ func (ctx *genBlockContext) HasGas(gas uint64) bool {
 	return ctx.block.Gas() > gas
 }
 
+// Gas returns the amount of gas left in the block.
+func (ctx *genBlockContext) Gas() uint64 {
+	return ctx.block.Gas()
+}
+
 // AddNewTx adds a transaction into the block.
 func (ctx *genBlockContext) AddNewTx(sender *genAccount, data types.TxData) *types.Transaction {
 	tx, err := types.SignNewTx(sender.key, ctx.Signer(), data)
 	if err != nil {
 		panic(err)
 	}
+	if sidecar := tx.BlobTxSidecar(); sidecar != nil {
+		ctx.gen.sidecars[tx.Hash()] = &blobSidecar{
+			sidecar: sidecar,
+			osaka:   ctx.ChainConfig().IsOsaka(ctx.Number(), ctx.Timestamp()),
+		}
+		ctx.blobcount += len(sidecar.Blobs)
+	}
 	ctx.block.AddTx(tx.WithoutBlobTxSidecar())
 	ctx.txcount++
 	return tx
@@ -94,6 +120,36 @@ func (ctx *genBlockContext) AccountNonce(addr common.Address) uint64 {
 	return ctx.block.TxNonce(addr)
 }
 
+// BlobCount returns the number of blobs added to the block so far.
+func (ctx *genBlockContext) BlobCount() int {
+	return ctx.blobcount
+}
+
+// BlobConfig returns the blob schedule of the current fork, or nil before Cancun.
+func (ctx *genBlockContext) BlobConfig() *params.BlobConfig {
+	cfg := ctx.ChainConfig()
+	sched := cfg.BlobScheduleConfig
+	switch {
+	case sched == nil:
+		return nil
+	case cfg.IsOsaka(ctx.Number(), ctx.Timestamp()):
+		return sched.Osaka
+	case cfg.IsPrague(ctx.Number(), ctx.Timestamp()):
+		return sched.Prague
+	case cfg.IsCancun(ctx.Number(), ctx.Timestamp()):
+		return sched.Cancun
+	default:
+		return nil
+	}
+}
+
+// BlobBaseFee returns the blob base fee of the current block.
+func (ctx *genBlockContext) BlobBaseFee() *big.Int {
+	cfg := ctx.ChainConfig()
+	excess := eip4844.CalcExcessBlobGas(cfg, ctx.ParentBlock().Header(), ctx.Timestamp())
+	return eip4844.CalcBlobFee(cfg, &types.Header{Time: ctx.Timestamp(), ExcessBlobGas: &excess})
+}
+
 // Signer returns a signer for the current block.
 func (ctx *genBlockContext) Signer() types.Signer {
 	return ctx.block.Signer()
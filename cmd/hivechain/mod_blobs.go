@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func init() {
+	registerOptIn("tx-blobs", func() blockModifier {
+		return &modBlobs{
+			fill:      "target",
+			maxBlocks: 4,
+		}
+	})
+}
+
+// modBlobs fills blocks with blob transactions carrying random blobs. The number of
+// blobs in the block is raised to the target or maximum of the current fork's blob
+// schedule.
+//
+// Computing KZG commitments and proofs is slow, so the modifier only fills a limited
+// number of blocks.
+type modBlobs struct {
+	fill      string // "target" or "max"
+	maxBlocks int    // number of blocks to fill
+
+	blocks int
+	txs    []blobTxInfo
+}
+
+type blobTxInfo struct {
+	TxHash     common.Hash    `json:"txhash"`
+	Block      hexutil.Uint64 `json:"block"`
+	BlobHashes []common.Hash  `json:"blobHashes"`
+}
+
+// configure sets the fill level and number of blocks.
+func (m *modBlobs) configure(params json.RawMessage) error {
+	var p struct {
+		Fill   string `json:"fill"`
+		Blocks int    `json:"blocks"`
+	}
+	if err := strictUnmarshal(params, &p); err != nil {
+		return err
+	}
+	switch p.Fill {
+	case "":
+	case "target", "max":
+		m.fill = p.Fill
+	default:
+		return fmt.Errorf("invalid fill %q", p.Fill)
+	}
+	if p.Blocks != 0 {
+		m.maxBlocks = p.Blocks
+	}
+	return nil
+}
+
+func (m *modBlobs) apply(ctx *genBlockContext) bool {
+	bcfg := ctx.BlobConfig()
+	if bcfg == nil || m.blocks >= m.maxBlocks {
+		return false
+	}
+	want := bcfg.Target
+	if m.fill == "max" {
+		want = bcfg.Max
+	}
+	if ctx.BlobCount() >= want {
+		return false
+	}
+
+	sender := ctx.TxSenderAccount()
+	recipient := pickRecipient(ctx)
+	blobFeeCap := new(big.Int).Mul(ctx.BlobBaseFee(), big.NewInt(2))
+	for ctx.BlobCount() < want && ctx.HasGas(params.TxGas) {
+		count := min(want-ctx.BlobCount(), params.BlobTxMaxBlobs)
+		sidecar := randomBlobSidecar(int64(ctx.TxRandomValue()), count)
+		tx := ctx.AddNewTx(sender, &types.BlobTx{
+			Nonce:      ctx.AccountNonce(sender.addr),
+			GasTipCap:  uint256.NewInt(1),
+			GasFeeCap:  uint256.MustFromBig(ctx.TxGasFeeCap()),
+			Gas:        params.TxGas,
+			To:         recipient,
+			Value:      uint256.NewInt(1),
+			BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+			BlobHashes: sidecar.BlobHashes(),
+			Sidecar:    sidecar,
+		})
+		m.txs = append(m.txs, blobTxInfo{
+			TxHash:     tx.Hash(),
+			Block:      hexutil.Uint64(ctx.NumberU64()),
+			BlobHashes: tx.BlobHashes(),
+		})
+	}
+	m.blocks++
+	return true
+}
+
+// randomBlobSidecar creates a sidecar containing random blobs.
+func randomBlobSidecar(seed int64, count int) *types.BlobTxSidecar {
+	rng := rand.New(rand.NewSource(seed))
+	sidecar := new(types.BlobTxSidecar)
+	for range count {
+		var blob kzg4844.Blob
+		// Fill the field elements. The first byte of each element is left zero to
+		// ensure the value is within the BLS modulus.
+		for i := 0; i < len(blob); i += 32 {
+			rng.Read(blob[i+1 : i+32])
+		}
+		commitment, err := kzg4844.BlobToCommitment(&blob)
+		if err != nil {
+			panic(err)
+		}
+		proof, err := kzg4844.ComputeBlobProof(&blob, commitment)
+		if err != nil {
+			panic(err)
+		}
+		sidecar.Blobs = append(sidecar.Blobs, blob)
+		sidecar.Commitments = append(sidecar.Commitments, commitment)
+		sidecar.Proofs = append(sidecar.Proofs, proof)
+	}
+	return sidecar
+}
+
+func (m *modBlobs) txInfo() any {
+	return m.txs
+}
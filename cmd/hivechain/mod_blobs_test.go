@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+const blobsTestSpec = `
+length: 4
+txInterval: 1
+txCount: 1
+pos: true
+forks:
+  blocks: {homestead: 0, tangerinewhistle: 0, spuriousdragon: 0, byzantium: 0, constantinople: 0,
+           petersburg: 0, istanbul: 0, muirglacier: 0, berlin: 0, london: 0, arrowglacier: 0,
+           grayglacier: 0, merge: 0, shanghai: 0, cancun: 0}
+  timestamps: {prague: 20, osaka: 30}
+modifiers:
+  - name: tx-blobs
+    params: {fill: max, blocks: 3}
+outputs: [blobs, newpayload]
+`
+
+func TestBlobs(t *testing.T) {
+	spec, err := parseChainSpec([]byte(blobsTestSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outdir := t.TempDir()
+	cfg := generatorConfig{outputDir: outdir}
+	spec.apply(&cfg)
+	cfg, _ = cfg.withDefaults()
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the blocks are filled. There is one block for each fork.
+	for num := uint64(1); num <= 3; num++ {
+		b := g.blockchain.GetBlockByNumber(num)
+		want := 6 // cancun
+		switch {
+		case g.genesis.Config.IsOsaka(b.Number(), b.Time()):
+			want = g.genesis.Config.BlobScheduleConfig.Osaka.Max
+		case g.genesis.Config.IsPrague(b.Number(), b.Time()):
+			want = g.genesis.Config.BlobScheduleConfig.Prague.Max
+		}
+		if have := int(*b.BlobGasUsed() / 131072); have != want {
+			t.Errorf("block %d has %d blobs, want %d", num, have, want)
+		}
+	}
+
+	// Check blobs.json.
+	var blobs map[common.Hash]struct {
+		Blob       *kzg4844.Blob
+		Commitment kzg4844.Commitment
+		Proof      *kzg4844.Proof
+		CellProofs []kzg4844.Proof
+	}
+	readJSON(t, filepath.Join(outdir, "blobs.json"), &blobs)
+	for _, b := range g.blockchain.GetBlocksFromHash(g.blockchain.CurrentBlock().Hash(), 4) {
+		osaka := g.genesis.Config.IsOsaka(b.Number(), b.Time())
+		for _, tx := range b.Transactions() {
+			for _, h := range tx.BlobHashes() {
+				blob, ok := blobs[h]
+				if !ok {
+					t.Fatalf("blob %v missing", h)
+				}
+				if osaka {
+					err = kzg4844.VerifyCellProofs([]kzg4844.Blob{*blob.Blob}, []kzg4844.Commitment{blob.Commitment}, blob.CellProofs)
+				} else {
+					err = kzg4844.VerifyBlobProof(blob.Blob, blob.Commitment, *blob.Proof)
+				}
+				if err != nil {
+					t.Errorf("block %d: blob %v invalid: %v", b.NumberU64(), h, err)
+				}
+			}
+		}
+	}
+
+	// Check the newpayload.json requests only contain the standard fields, and that
+	// newpayload-blobs.json has the bundle of each request with blobs.
+	var newpayload []map[string]json.RawMessage
+	readJSON(t, filepath.Join(outdir, "newpayload.json"), &newpayload)
+	var bundles map[string]*engine.BlobsBundle
+	readJSON(t, filepath.Join(outdir, "newpayload-blobs.json"), &bundles)
+	for i, np := range newpayload[1:] {
+		if len(np) != 4 {
+			t.Errorf("newpayload %d: unexpected fields in request", i+1)
+		}
+		var id string
+		json.Unmarshal(np["id"], &id)
+		var params []json.RawMessage
+		json.Unmarshal(np["params"], &params)
+		var hashes []common.Hash
+		json.Unmarshal(params[1], &hashes)
+		bundle := bundles[id]
+		if len(hashes) == 0 {
+			if bundle != nil {
+				t.Errorf("newpayload %d: unexpected blobs bundle", i+1)
+			}
+			continue
+		}
+		if bundle == nil || len(bundle.Blobs) != len(hashes) || len(bundle.Commitments) != len(hashes) {
+			t.Fatalf("newpayload %d: wrong blobs bundle", i+1)
+		}
+		proofsPerBlob := 1
+		if len(blobs[hashes[0]].CellProofs) > 0 {
+			proofsPerBlob = kzg4844.CellProofsPerBlob
+		}
+		if len(bundle.Proofs) != len(hashes)*proofsPerBlob {
+			t.Errorf("newpayload %d: wrong number of proofs %d", i+1, len(bundle.Proofs))
+		}
+		for j, h := range hashes {
+			if blob := blobs[h]; blob.Blob == nil || !bytes.Equal(bundle.Blobs[j], blob.Blob[:]) {
+				t.Errorf("newpayload %d: blob %d does not match %v", i+1, j, h)
+			}
+		}
+	}
+}
+
+func readJSON(t *testing.T, file string, v any) {
+	t.Helper()
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/json"
+
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -33,6 +35,20 @@ type modCreateSpam struct {
 	gas  uint64
 }
 
+// configure sets the gas used by the spam contract constructor.
+func (m *modCreateSpam) configure(params json.RawMessage) error {
+	var p struct {
+		Gas uint64 `json:"gas"`
+	}
+	if err := strictUnmarshal(params, &p); err != nil {
+		return err
+	}
+	if p.Gas != 0 {
+		m.gas = p.Gas
+	}
+	return nil
+}
+
 func (m *modCreateSpam) apply(ctx *genBlockContext) bool {
 	gas := ctx.TxCreateIntrinsicGas(m.code) + m.gas
 	if !ctx.HasGas(gas) {
@@ -0,0 +1,516 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// modFuzz fills blocks with random transactions. It is enabled by the -fuzz flag.
+//
+// All random choices are made using a generator with the configured seed, so the chain
+// is the same for every run with the same seed and configuration. Each transaction
+// performs one of the fuzzer actions below. Some actions are expected to fail, e.g.
+// reverts and out-of-gas. Contract interactions mostly use the fuzz contract (see
+// contracts/fuzz.eas), which is added to the genesis block in fuzz mode.
+type modFuzz struct {
+	seed    int64
+	rng     *rand.Rand
+	created []common.Address // contracts created by the fuzzer
+
+	txs []fuzzTxInfo
+}
+
+type fuzzTxInfo struct {
+	TxHash common.Hash    `json:"txhash"`
+	Block  hexutil.Uint64 `json:"block"`
+	Type   hexutil.Uint64 `json:"type"`
+	Kind   string         `json:"kind"`
+}
+
+// Fuzzer actions.
+const (
+	fuzzTransfer    = "transfer"     // value transfer
+	fuzzStore       = "store"        // storage write
+	fuzzRevert      = "revert"       // call which reverts
+	fuzzOutOfGas    = "out-of-gas"   // call which runs out of gas
+	fuzzPrecompile  = "precompile"   // direct call of a precompile with random input
+	fuzzCall        = "call"         // call from the fuzz contract to another contract
+	fuzzLog         = "log"          // log with random data
+	fuzzCreate      = "create"       // contract creation with random code
+	fuzzCreate2     = "create2"      // CREATE2 using a small set of salts, causing collisions
+	fuzzBigCalldata = "big-calldata" // call with large calldata
+	fuzzSetCode     = "setcode"      // EIP-7702 delegation
+)
+
+var fuzzActions = []string{
+	fuzzTransfer,
+	fuzzStore,
+	fuzzRevert,
+	fuzzOutOfGas,
+	fuzzPrecompile,
+	fuzzCall,
+	fuzzLog,
+	fuzzCreate,
+	fuzzCreate2,
+	fuzzBigCalldata,
+	fuzzSetCode,
+}
+
+// Operations of the fuzz contract.
+const (
+	fuzzOpStore   = 0
+	fuzzOpRevert  = 1
+	fuzzOpLoop    = 2
+	fuzzOpCreate2 = 3
+	fuzzOpCall    = 4
+	fuzzOpLog     = 5
+	fuzzOpStop    = 0xff
+)
+
+// fuzzCreate2Code is the runtime code of contracts created by CREATE2. The first one
+// self-destructs, so the address can be reused before Cancun.
+var fuzzCreate2Code = [][]byte{
+	{byte(vm.CALLER), byte(vm.SELFDESTRUCT)},
+	{byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.SSTORE)},
+}
+
+// fuzzTx is a transaction chosen by the fuzzer.
+type fuzzTx struct {
+	kind  string
+	to    *common.Address
+	data  []byte
+	value *big.Int
+	gas   uint64 // gas for execution, in addition to the intrinsic gas
+	auths []types.SetCodeAuthorization
+
+	created *common.Address // address of created contract
+}
+
+// fuzzAuthorities are the EIP-7702 authorities used by the fuzzer. They are funded in
+// the genesis block, so their nonce is always available.
+var fuzzAuthorities = func() (list []*genAccount) {
+	for i := range 4 {
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("hivechain fuzz authority %d", i))))
+		if err != nil {
+			panic(err)
+		}
+		list = append(list, &genAccount{key: key, addr: crypto.PubkeyToAddress(key.PublicKey)})
+	}
+	return list
+}()
+
+func newModFuzz(seed int64) *modFuzz {
+	return &modFuzz{seed: seed, rng: rand.New(rand.NewSource(seed))}
+}
+
+func (m *modFuzz) apply(ctx *genBlockContext) bool {
+	// End the block early sometimes, to vary the number of transactions.
+	if m.rng.Intn(8) == 0 {
+		return false
+	}
+	var ftx *fuzzTx
+	for ftx == nil {
+		ftx = m.choose(ctx, fuzzActions[m.rng.Intn(len(fuzzActions))])
+	}
+
+	sender := &ctx.gen.accounts[m.rng.Intn(len(ctx.gen.accounts))]
+	txdata := m.txdata(ctx, sender, ftx)
+	if txdata == nil {
+		return false // out of gas
+	}
+	if ftx.kind == fuzzCreate {
+		addr := crypto.CreateAddress(sender.addr, ctx.AccountNonce(sender.addr))
+		ftx.created = &addr
+	}
+	if ftx.created != nil && !slices.Contains(m.created, *ftx.created) {
+		m.created = append(m.created, *ftx.created)
+	}
+	tx := ctx.AddNewTx(sender, txdata)
+	m.txs = append(m.txs, fuzzTxInfo{
+		TxHash: tx.Hash(),
+		Block:  hexutil.Uint64(ctx.NumberU64()),
+		Type:   hexutil.Uint64(tx.Type()),
+		Kind:   ftx.kind,
+	})
+	return true
+}
+
+func (m *modFuzz) txInfo() any {
+	return map[string]any{
+		"seed": m.seed,
+		"txs":  m.txs,
+	}
+}
+
+// choose creates the transaction for an action. It returns nil if the action is not
+// possible in the current block.
+func (m *modFuzz) choose(ctx *genBlockContext, kind string) *fuzzTx {
+	var (
+		cfg  = ctx.ChainConfig()
+		fuzz = common.HexToAddress(fuzzAddr)
+		ftx  = &fuzzTx{kind: kind, to: &fuzz, value: new(big.Int)}
+	)
+	switch kind {
+	case fuzzTransfer:
+		to := m.randomAddress(ctx)
+		ftx.to = &to
+		ftx.value.SetInt64(m.rng.Int63n(1e9) + 1)
+		ftx.gas = 50000 // in case the recipient has code
+
+	case fuzzStore:
+		value := make([]byte, 32)
+		if m.rng.Intn(4) > 0 {
+			m.rng.Read(value)
+		}
+		ftx.data = fuzzCalldata(fuzzOpStore, m.randomKey(), value)
+		ftx.gas = 30000
+
+	case fuzzRevert:
+		if !cfg.IsByzantium(ctx.Number()) {
+			return nil
+		}
+		ftx.data = append([]byte{fuzzOpRevert}, m.randomBytes(64)...)
+		ftx.gas = 10000
+
+	case fuzzOutOfGas:
+		ftx.data = []byte{fuzzOpLoop}
+		ftx.gas = 1000 + uint64(m.rng.Intn(20000))
+
+	case fuzzPrecompile:
+		to := m.randomPrecompile(ctx)
+		ftx.to = &to
+		ftx.data = m.randomBytes(256)
+		ftx.gas = 1000 + uint64(m.rng.Intn(200000))
+
+	case fuzzCall:
+		if !cfg.IsByzantium(ctx.Number()) {
+			return nil
+		}
+		var target common.Address
+		var input []byte
+		switch m.rng.Intn(4) {
+		case 0:
+			target = m.randomPrecompile(ctx)
+			input = m.randomBytes(256)
+		case 1:
+			target = common.HexToAddress(emitAddr)
+			input = m.randomBytes(64)
+		case 2:
+			// Call the fuzz contract itself with a random operation.
+			target = fuzz
+			input = append([]byte{byte(m.rng.Intn(fuzzOpLog + 1))}, m.randomBytes(128)...)
+			input = append(input, make([]byte, 64)...) // ensure operation offsets are valid
+		default:
+			target = m.randomAddress(ctx)
+			input = m.randomBytes(64)
+		}
+		ftx.data = fuzzCalldata(fuzzOpCall, common.BytesToHash(target[:]), input)
+		ftx.gas = 100000 + uint64(m.rng.Intn(100000))
+
+	case fuzzLog:
+		ftx.data = append([]byte{fuzzOpLog}, m.randomBytes(256)...)
+		ftx.gas = 3000 + 16*uint64(len(ftx.data))
+
+	case fuzzCreate:
+		// The code may start with 0xEF, which is rejected since London.
+		code := m.randomBytes(32)
+		if len(code) == 0 || m.rng.Intn(8) == 0 {
+			code = append([]byte{0xef}, code...)
+		}
+		ftx.to = nil
+		ftx.data = initCodeFor(code[:min(len(code), 32)])
+		ftx.gas = 10000 + params.CreateDataGas*32
+
+	case fuzzCreate2:
+		if !cfg.IsConstantinople(ctx.Number()) {
+			return nil
+		}
+		salt := common.BigToHash(big.NewInt(m.rng.Int63n(4)))
+		initcode := initCodeFor(fuzzCreate2Code[m.rng.Intn(len(fuzzCreate2Code))])
+		ftx.data = fuzzCalldata(fuzzOpCreate2, salt, initcode)
+		ftx.gas = 80000
+		addr := crypto.CreateAddress2(fuzz, salt, crypto.Keccak256(initcode))
+		ftx.created = &addr
+
+	case fuzzBigCalldata:
+		// Half of the calldata is zero.
+		size := 1024 + m.rng.Intn(63*1024)
+		ftx.data = make([]byte, size)
+		m.rng.Read(ftx.data[size/2:])
+		ftx.data[0] = fuzzOpStop
+		if m.rng.Intn(2) == 0 {
+			m.rng.Shuffle(size, func(i, j int) { ftx.data[i], ftx.data[j] = ftx.data[j], ftx.data[i] })
+			to := ctx.gen.accounts[m.rng.Intn(len(ctx.gen.accounts))].addr
+			ftx.to = &to
+		}
+
+	case fuzzSetCode:
+		if !cfg.IsPrague(ctx.Number(), ctx.Timestamp()) {
+			return nil
+		}
+		ftx.auths = m.randomAuthorizations(ctx)
+		to := fuzzAuthorities[m.rng.Intn(len(fuzzAuthorities))].addr
+		ftx.to = &to
+		ftx.data = fuzzCalldata(fuzzOpStore, m.randomKey(), m.randomBytes(32))
+		ftx.gas = 100000
+
+	default:
+		panic("unknown fuzzer action " + kind)
+	}
+	return ftx
+}
+
+// txdata creates a transaction of random type. It returns nil if the gas limit of the
+// transaction exceeds the remaining block gas.
+func (m *modFuzz) txdata(ctx *genBlockContext, sender *genAccount, ftx *fuzzTx) types.TxData {
+	var (
+		cfg      = ctx.ChainConfig()
+		num      = ctx.Number()
+		time     = ctx.Timestamp()
+		nonce    = ctx.AccountNonce(sender.addr)
+		txType   = m.randomTxType(ctx, ftx)
+		tip      = big.NewInt(1 + m.rng.Int63n(3))
+		feeCap   = new(big.Int).Add(ctx.TxGasFeeCap(), tip)
+		accesses types.AccessList
+	)
+	if txType != types.LegacyTxType {
+		accesses = m.randomAccessList(ctx, ftx.to)
+	}
+	gas, err := core.IntrinsicGas(ftx.data, accesses, ftx.auths, ftx.to == nil, cfg.IsHomestead(num), cfg.IsIstanbul(num), cfg.IsShanghai(num, time))
+	if err != nil {
+		panic(err)
+	}
+	gas += ftx.gas
+	if cfg.IsPrague(num, time) {
+		floor, err := core.FloorDataGas(ftx.data)
+		if err != nil {
+			panic(err)
+		}
+		gas = max(gas, floor)
+	}
+	if cfg.IsOsaka(num, time) {
+		gas = min(gas, params.MaxTxGas)
+	}
+	if !ctx.HasGas(gas) {
+		return nil
+	}
+
+	switch txType {
+	case types.LegacyTxType:
+		return &types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: feeCap,
+			Gas:      gas,
+			To:       ftx.to,
+			Value:    ftx.value,
+			Data:     ftx.data,
+		}
+	case types.AccessListTxType:
+		return &types.AccessListTx{
+			Nonce:      nonce,
+			GasPrice:   feeCap,
+			Gas:        gas,
+			To:         ftx.to,
+			Value:      ftx.value,
+			Data:       ftx.data,
+			AccessList: accesses,
+		}
+	case types.DynamicFeeTxType:
+		return &types.DynamicFeeTx{
+			Nonce:      nonce,
+			GasTipCap:  tip,
+			GasFeeCap:  feeCap,
+			Gas:        gas,
+			To:         ftx.to,
+			Value:      ftx.value,
+			Data:       ftx.data,
+			AccessList: accesses,
+		}
+	case types.BlobTxType:
+		blobs := 1 + m.rng.Intn(min(2, ctx.BlobConfig().Max-ctx.BlobCount()))
+		sidecar := randomBlobSidecar(m.rng.Int63(), blobs)
+		blobFeeCap := new(big.Int).Mul(ctx.BlobBaseFee(), big.NewInt(2))
+		return &types.BlobTx{
+			Nonce:      nonce,
+			GasTipCap:  uint256.MustFromBig(tip),
+			GasFeeCap:  uint256.MustFromBig(feeCap),
+			Gas:        gas,
+			To:         *ftx.to,
+			Value:      uint256.MustFromBig(ftx.value),
+			Data:       ftx.data,
+			AccessList: accesses,
+			BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+			BlobHashes: sidecar.BlobHashes(),
+			Sidecar:    sidecar,
+		}
+	case types.SetCodeTxType:
+		return &types.SetCodeTx{
+			ChainID:    uint256.MustFromBig(cfg.ChainID),
+			Nonce:      nonce,
+			GasTipCap:  uint256.MustFromBig(tip),
+			GasFeeCap:  uint256.MustFromBig(feeCap),
+			Gas:        gas,
+			To:         *ftx.to,
+			Value:      uint256.MustFromBig(ftx.value),
+			Data:       ftx.data,
+			AccessList: accesses,
+			AuthList:   ftx.auths,
+		}
+	default:
+		panic("unhandled tx type")
+	}
+}
+
+// randomTxType chooses a transaction type that is valid for the transaction.
+func (m *modFuzz) randomTxType(ctx *genBlockContext, ftx *fuzzTx) byte {
+	if ftx.auths != nil {
+		return types.SetCodeTxType
+	}
+	var (
+		cfg     = ctx.ChainConfig()
+		txTypes = []byte{types.LegacyTxType}
+	)
+	if cfg.IsBerlin(ctx.Number()) {
+		txTypes = append(txTypes, types.AccessListTxType)
+	}
+	if cfg.IsLondon(ctx.Number()) {
+		txTypes = append(txTypes, types.DynamicFeeTxType)
+	}
+	if bcfg := ctx.BlobConfig(); bcfg != nil && ftx.to != nil && ctx.BlobCount() < bcfg.Max {
+		txTypes = append(txTypes, types.BlobTxType)
+	}
+	return txTypes[m.rng.Intn(len(txTypes))]
+}
+
+// randomAccessList creates an access list that may contain the transaction recipient
+// and a precompile.
+func (m *modFuzz) randomAccessList(ctx *genBlockContext, to *common.Address) types.AccessList {
+	list := make(types.AccessList, 0)
+	if to != nil && m.rng.Intn(2) == 0 {
+		tuple := types.AccessTuple{Address: *to, StorageKeys: []common.Hash{}}
+		for range m.rng.Intn(3) {
+			tuple.StorageKeys = append(tuple.StorageKeys, common.BigToHash(big.NewInt(m.rng.Int63n(16))))
+		}
+		list = append(list, tuple)
+	}
+	if m.rng.Intn(4) == 0 {
+		list = append(list, types.AccessTuple{Address: m.randomPrecompile(ctx), StorageKeys: []common.Hash{}})
+	}
+	return list
+}
+
+// randomAuthorizations creates EIP-7702 authorizations for one or two authorities.
+// Some of them are invalid because of a wrong chain ID or nonce, which means they are
+// skipped during execution.
+func (m *modFuzz) randomAuthorizations(ctx *genBlockContext) []types.SetCodeAuthorization {
+	var (
+		chainID = uint256.MustFromBig(ctx.ChainConfig().ChainID)
+		auths   []types.SetCodeAuthorization
+	)
+	for _, i := range m.rng.Perm(len(fuzzAuthorities))[:1+m.rng.Intn(2)] {
+		authority := fuzzAuthorities[i]
+		auth := types.SetCodeAuthorization{
+			ChainID: *chainID,
+			Address: m.randomDelegation(),
+			Nonce:   ctx.AccountNonce(authority.addr),
+		}
+		switch m.rng.Intn(8) {
+		case 0:
+			auth.ChainID = uint256.Int{} // valid on all chains
+		case 1:
+			auth.ChainID.AddUint64(chainID, 1)
+		case 2:
+			auth.Nonce++
+		}
+		signed, err := types.SignSetCode(authority.key, auth)
+		if err != nil {
+			panic(err)
+		}
+		auths = append(auths, signed)
+	}
+	return auths
+}
+
+// randomDelegation chooses the target of an EIP-7702 delegation. The zero address
+// clears the delegation.
+func (m *modFuzz) randomDelegation() common.Address {
+	switch n := m.rng.Intn(4); {
+	case n == 0:
+		return common.Address{}
+	case n == 1 && len(m.created) > 0:
+		return m.created[m.rng.Intn(len(m.created))]
+	default:
+		return common.HexToAddress(fuzzAddr)
+	}
+}
+
+// randomAddress chooses a call target. This may be an account, a new address, a contract
+// created by the fuzzer, or an EIP-7702 authority.
+func (m *modFuzz) randomAddress(ctx *genBlockContext) common.Address {
+	switch n := m.rng.Intn(4); {
+	case n == 0:
+		var addr common.Address
+		m.rng.Read(addr[:])
+		return addr
+	case n == 1 && len(m.created) > 0:
+		return m.created[m.rng.Intn(len(m.created))]
+	case n == 2:
+		return fuzzAuthorities[m.rng.Intn(len(fuzzAuthorities))].addr
+	default:
+		return ctx.gen.accounts[m.rng.Intn(len(ctx.gen.accounts))].addr
+	}
+}
+
+// randomPrecompile chooses one of the precompiles active in the current block.
+func (m *modFuzz) randomPrecompile(ctx *genBlockContext) common.Address {
+	cfg := ctx.ChainConfig()
+	merge, ok := ctx.gen.mergeBlock()
+	isMerge := ok && ctx.NumberU64() >= merge
+	// The list is sorted because go-ethereum creates it from a map.
+	precompiles := slices.Clone(vm.ActivePrecompiles(cfg.Rules(ctx.Number(), isMerge, ctx.Timestamp())))
+	slices.SortFunc(precompiles, func(a, b common.Address) int { return a.Cmp(b) })
+	return precompiles[m.rng.Intn(len(precompiles))]
+}
+
+// randomBytes returns random data with a random length up to maxSize.
+func (m *modFuzz) randomBytes(maxSize int) []byte {
+	b := make([]byte, m.rng.Intn(maxSize+1))
+	m.rng.Read(b)
+	return b
+}
+
+// randomKey returns one of a small set of storage keys.
+func (m *modFuzz) randomKey() common.Hash {
+	return common.BigToHash(big.NewInt(m.rng.Int63n(16)))
+}
+
+// fuzzCalldata creates calldata for the fuzz contract.
+func fuzzCalldata(op byte, arg common.Hash, tail []byte) []byte {
+	data := append([]byte{op}, arg[:]...)
+	return append(data, tail...)
+}
+
+// initCodeFor creates init code which deploys the given code of at most 32 bytes.
+func initCodeFor(code []byte) []byte {
+	n := len(code)
+	initcode := []byte{byte(vm.PUSH1) + byte(n-1)}
+	initcode = append(initcode, code...)
+	return append(initcode,
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), byte(n),
+		byte(vm.PUSH1), byte(32-n),
+		byte(vm.RETURN),
+	)
+}
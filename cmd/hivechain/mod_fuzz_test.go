@@ -0,0 +1,85 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestFuzz(t *testing.T) {
+	generate := func(seed int64) (*generator, string) {
+		outdir := t.TempDir()
+		cfg := generatorConfig{
+			chainLength:  40,
+			txCount:      20,
+			forkInterval: 2,
+			fuzz:         true,
+			fuzzSeed:     seed,
+			outputDir:    outdir,
+			outputs:      []string{"txinfo"},
+		}
+		cfg, err := cfg.withDefaults()
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := newGenerator(cfg)
+		if err := g.run(); err != nil {
+			t.Fatal(err)
+		}
+		return g, outdir
+	}
+
+	g, outdir := generate(1)
+	if g2, _ := generate(1); g2.blockchain.CurrentBlock().Hash() != g.blockchain.CurrentBlock().Hash() {
+		t.Fatal("chain differs for same seed")
+	}
+	if g3, _ := generate(2); g3.blockchain.CurrentBlock().Hash() == g.blockchain.CurrentBlock().Hash() {
+		t.Fatal("chain is equal for different seeds")
+	}
+
+	// Check all actions and transaction types were used.
+	var txinfo struct {
+		Fuzz struct {
+			Seed int64        `json:"seed"`
+			Txs  []fuzzTxInfo `json:"txs"`
+		} `json:"fuzz"`
+	}
+	readJSON(t, filepath.Join(outdir, "txinfo.json"), &txinfo)
+	kinds := make(map[string]bool)
+	txTypes := make(map[uint64]bool)
+	for _, tx := range txinfo.Fuzz.Txs {
+		kinds[tx.Kind] = true
+		txTypes[uint64(tx.Type)] = true
+	}
+	for _, kind := range fuzzActions {
+		if !kinds[kind] {
+			t.Errorf("no %s transaction", kind)
+		}
+	}
+	for _, typ := range []uint64{types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.BlobTxType, types.SetCodeTxType} {
+		if !txTypes[typ] {
+			t.Errorf("no transaction of type %d", typ)
+		}
+	}
+
+	// Some transactions should fail.
+	var failed, succeeded int
+	for n := uint64(1); n <= g.blockchain.CurrentBlock().Number.Uint64(); n++ {
+		block := g.blockchain.GetBlockByNumber(n)
+		for _, r := range g.blockchain.GetReceiptsByHash(block.Hash()) {
+			if len(r.PostState) > 0 {
+				continue
+			}
+			if r.Status == types.ReceiptStatusFailed {
+				failed++
+			} else {
+				succeeded++
+			}
+		}
+	}
+	t.Logf("%d transactions, %d failed, %d succeeded after Byzantium", len(txinfo.Fuzz.Txs), failed, succeeded)
+	if failed == 0 || succeeded == 0 {
+		t.Error("expected both failed and successful transactions")
+	}
+}
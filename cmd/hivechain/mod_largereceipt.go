@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/json"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -27,6 +29,20 @@ type modLargeReceipt struct {
 	block    uint64 // block number where txs were included
 }
 
+// configure sets the number of transactions in the block.
+func (m *modLargeReceipt) configure(params json.RawMessage) error {
+	var p struct {
+		TxCount uint64 `json:"txCount"`
+	}
+	if err := strictUnmarshal(params, &p); err != nil {
+		return err
+	}
+	if p.TxCount != 0 {
+		m.txCount = p.TxCount
+	}
+	return nil
+}
+
 func (m *modLargeReceipt) apply(ctx *genBlockContext) bool {
 	if m.didRun || !ctx.HasGas(m.gasLimit*m.txCount) {
 		return false
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// These modifiers create large amounts of state, for testing state sync. Unlike the
+// randomstorage and randomcode spam modifiers, they can fill entire blocks, so a
+// chain with a big state can be created in relatively few blocks. They are opt-in, so
+// that they only run in chains created for state tests.
+
+func init() {
+	registerOptIn("state-accounts", func() blockModifier {
+		return &modStateAccounts{count: 100}
+	})
+	registerOptIn("state-storage", func() blockModifier {
+		return &modStateStorage{
+			contracts: 2,
+			slots:     500,
+			clear:     100,
+			destroy:   1,
+		}
+	})
+}
+
+// Gas estimates for the state contracts. These are upper bounds across all forks.
+const (
+	genAccountGas     = 37500 // per account created by genaccounts
+	genAccountTxGas   = 50000 // genaccounts tx overhead, including counter update
+	storageSetGas     = 22500 // per slot set by deepstorage
+	storageClearGas   = 5200  // per slot cleared by deepstorage
+	storageTxGas      = 30000 // deepstorage tx overhead
+	storageDestroyGas = 50000
+)
+
+// txGasLimit returns the maximum gas a transaction in the current block can use.
+func txGasLimit(ctx *genBlockContext) uint64 {
+	return min(ctx.Gas(), params.MaxTxGas)
+}
+
+// modStateAccounts creates accounts by sending 1 wei to addresses derived from a
+// counter. Accounts are created by calling the genaccounts contract, which is added to
+// genesis when the modifier is enabled. The address of account i is keccak256(i), with
+// i as a 32-byte integer.
+type modStateAccounts struct {
+	count uint64 // number of accounts to create
+	info  *stateAccountsInfo
+}
+
+type stateAccountsInfo struct {
+	Contract   common.Address `json:"contract"`
+	Accounts   hexutil.Uint64 `json:"accounts"`
+	FirstBlock hexutil.Uint64 `json:"firstBlock"`
+	LastBlock  hexutil.Uint64 `json:"lastBlock"`
+}
+
+// genAccountAddress returns the address of the i'th account created by state-accounts.
+func genAccountAddress(i uint64) common.Address {
+	key := uint256.NewInt(i).Bytes32()
+	return common.BytesToAddress(crypto.Keccak256(key[:]))
+}
+
+// configure sets the number of accounts.
+func (m *modStateAccounts) configure(params json.RawMessage) error {
+	var p struct {
+		Count uint64 `json:"count"`
+	}
+	if err := strictUnmarshal(params, &p); err != nil {
+		return err
+	}
+	if p.Count != 0 {
+		m.count = p.Count
+	}
+	return nil
+}
+
+func (m *modStateAccounts) apply(ctx *genBlockContext) bool {
+	if m.info == nil {
+		m.info = &stateAccountsInfo{
+			Contract:   common.HexToAddress(genAccsAddr),
+			FirstBlock: hexutil.Uint64(ctx.NumberU64()),
+		}
+	}
+	sender := ctx.TxSenderAccount()
+	added := false
+	for uint64(m.info.Accounts) < m.count {
+		gas := txGasLimit(ctx)
+		if gas < genAccountTxGas+genAccountGas {
+			break
+		}
+		n := min(m.count-uint64(m.info.Accounts), (gas-genAccountTxGas)/genAccountGas)
+		data := uint256.NewInt(n).Bytes32()
+		ctx.AddNewTx(sender, &types.LegacyTx{
+			Nonce:    ctx.AccountNonce(sender.addr),
+			Gas:      genAccountTxGas + n*genAccountGas,
+			GasPrice: ctx.TxGasFeeCap(),
+			To:       &m.info.Contract,
+			Data:     data[:],
+		})
+		m.info.Accounts += hexutil.Uint64(n)
+		m.info.LastBlock = hexutil.Uint64(ctx.NumberU64())
+		added = true
+	}
+	return added
+}
+
+func (m *modStateAccounts) txInfo() any {
+	if m.info == nil || m.info.Accounts == 0 {
+		return nil
+	}
+	return m.info
+}
+
+// modStateStorage creates contracts with large storage, and then deletes some of it.
+// It works in phases, where each phase starts in a new block:
+//
+//   - deploy the deepstorage contracts
+//   - fill the storage of each contract, possibly across many blocks
+//   - clear some slots of each contract
+//   - self-destruct some of the contracts
+//
+// Note that since Cancun, self-destruct no longer removes the contract (EIP-6780).
+type modStateStorage struct {
+	contracts int    // number of contracts
+	slots     uint64 // storage slots per contract
+	clear     uint64 // slots cleared per contract
+	destroy   int    // number of contracts to self-destruct
+
+	phase      int
+	phaseBlock uint64 // block where the previous phase ended
+	info       []*storageContractInfo
+}
+
+const (
+	storagePhaseDeploy = iota
+	storagePhaseFill
+	storagePhaseClear
+	storagePhaseDestroy
+	storagePhaseDone
+)
+
+// storageContractInfo is the txinfo of a contract created by state-storage. Slot i has
+// the key keccak256(i) and value i+1. Cleared slots are the first ones.
+type storageContractInfo struct {
+	Contract  common.Address  `json:"contract"`
+	Block     hexutil.Uint64  `json:"block"`
+	Slots     hexutil.Uint64  `json:"slots"`
+	Cleared   hexutil.Uint64  `json:"cleared"`
+	Destroyed *hexutil.Uint64 `json:"destroyed"`
+}
+
+// configure sets the number of contracts and slots.
+func (m *modStateStorage) configure(params json.RawMessage) error {
+	var p struct {
+		Contracts *int    `json:"contracts"`
+		Slots     *uint64 `json:"slots"`
+		Clear     *uint64 `json:"clear"`
+		Destroy   *int    `json:"destroy"`
+	}
+	if err := strictUnmarshal(params, &p); err != nil {
+		return err
+	}
+	if p.Contracts != nil {
+		m.contracts = *p.Contracts
+	}
+	if p.Slots != nil {
+		m.slots = *p.Slots
+	}
+	if p.Clear != nil {
+		m.clear = *p.Clear
+	}
+	if p.Destroy != nil {
+		m.destroy = *p.Destroy
+	}
+	switch {
+	case m.contracts < 1:
+		return errors.New("contracts must be positive")
+	case m.clear > m.slots:
+		return errors.New("clear exceeds slots")
+	case m.destroy < 0 || m.destroy > m.contracts:
+		return errors.New("destroy exceeds contracts")
+	}
+	return nil
+}
+
+func (m *modStateStorage) apply(ctx *genBlockContext) bool {
+	num := ctx.NumberU64()
+	if m.phase == storagePhaseDone || (m.phase != storagePhaseDeploy && num == m.phaseBlock) {
+		return false
+	}
+	var added, done bool
+	switch m.phase {
+	case storagePhaseDeploy:
+		added, done = m.deploy(ctx)
+	case storagePhaseFill:
+		added, done = m.modify(ctx, 1, m.slots, storageSetGas, func(c *storageContractInfo) *hexutil.Uint64 { return &c.Slots })
+	case storagePhaseClear:
+		added, done = m.modify(ctx, 0, m.clear, storageClearGas, func(c *storageContractInfo) *hexutil.Uint64 { return &c.Cleared })
+	case storagePhaseDestroy:
+		added, done = m.selfdestruct(ctx)
+	}
+	if done {
+		// Advance to the next phase. It will run in the next block.
+		m.phase++
+		m.phaseBlock = num
+	}
+	return added
+}
+
+// deploy creates the contracts.
+func (m *modStateStorage) deploy(ctx *genBlockContext) (added, done bool) {
+	code, gas := codeToDeploy(ctx, deepstorageCode)
+	sender := ctx.TxSenderAccount()
+	for len(m.info) < m.contracts && ctx.HasGas(gas) {
+		nonce := ctx.AccountNonce(sender.addr)
+		ctx.AddNewTx(sender, &types.LegacyTx{
+			Nonce:    nonce,
+			Gas:      gas,
+			GasPrice: ctx.TxGasFeeCap(),
+			Data:     code,
+		})
+		m.info = append(m.info, &storageContractInfo{
+			Contract: crypto.CreateAddress(sender.addr, nonce),
+			Block:    hexutil.Uint64(ctx.NumberU64()),
+		})
+		added = true
+	}
+	return added, len(m.info) == m.contracts
+}
+
+// modify sets or clears the first n slots of all contracts. The progress of each
+// contract is tracked in the counter returned by the field function.
+func (m *modStateStorage) modify(ctx *genBlockContext, op, n, slotGas uint64, field func(*storageContractInfo) *hexutil.Uint64) (added, done bool) {
+	sender := ctx.TxSenderAccount()
+	for _, c := range m.info {
+		progress := field(c)
+		for uint64(*progress) < n {
+			gas := txGasLimit(ctx)
+			if gas < storageTxGas+slotGas {
+				return added, false
+			}
+			count := min(n-uint64(*progress), (gas-storageTxGas)/slotGas)
+			ctx.AddNewTx(sender, &types.LegacyTx{
+				Nonce:    ctx.AccountNonce(sender.addr),
+				Gas:      storageTxGas + count*slotGas,
+				GasPrice: ctx.TxGasFeeCap(),
+				To:       &c.Contract,
+				Data:     deepstorageCalldata(op, uint64(*progress), count),
+			})
+			*progress += hexutil.Uint64(count)
+			added = true
+		}
+	}
+	return added, true
+}
+
+// selfdestruct destroys the first contracts.
+func (m *modStateStorage) selfdestruct(ctx *genBlockContext) (added, done bool) {
+	sender := ctx.TxSenderAccount()
+	for _, c := range m.info[:m.destroy] {
+		if c.Destroyed != nil {
+			continue
+		}
+		if !ctx.HasGas(storageDestroyGas) {
+			return added, false
+		}
+		ctx.AddNewTx(sender, &types.LegacyTx{
+			Nonce:    ctx.AccountNonce(sender.addr),
+			Gas:      storageDestroyGas,
+			GasPrice: ctx.TxGasFeeCap(),
+			To:       &c.Contract,
+		})
+		num := hexutil.Uint64(ctx.NumberU64())
+		c.Destroyed = &num
+		added = true
+	}
+	return added, true
+}
+
+// deepstorageCalldata encodes a call to the deepstorage contract.
+func deepstorageCalldata(op, start, count uint64) []byte {
+	data := make([]byte, 0, 96)
+	for _, v := range []uint64{op, start, count} {
+		w := uint256.NewInt(v).Bytes32()
+		data = append(data, w[:]...)
+	}
+	return data
+}
+
+// deepstorageKey returns the storage key of slot i.
+func deepstorageKey(i uint64) common.Hash {
+	w := uint256.NewInt(i).Bytes32()
+	return crypto.Keccak256Hash(w[:])
+}
+
+func (m *modStateStorage) txInfo() any {
+	if len(m.info) == 0 {
+		return nil
+	}
+	return m.info
+}
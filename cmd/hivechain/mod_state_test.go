@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestStateModifiers(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		chainLength:  20,
+		txCount:      2,
+		forkInterval: 1,
+		lastFork:     "shanghai", // for self-destruct
+		outputDir:    outdir,
+		outputs:      []string{"txinfo", "headstate-stats"},
+		modifiers: []modifierSpec{
+			{Name: "state-accounts", Params: []byte(`{"count": 2000}`)},
+			{Name: "state-storage", Params: []byte(`{"contracts": 3, "slots": 2000, "clear": 50, "destroy": 1}`)},
+		},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// All transactions must succeed, otherwise the gas estimates are too low. Receipts
+	// before Byzantium have no status, but failed transactions use all their gas.
+	for n := uint64(1); n <= g.blockchain.CurrentBlock().Number.Uint64(); n++ {
+		block := g.blockchain.GetBlockByNumber(n)
+		for i, r := range g.blockchain.GetReceiptsByHash(block.Hash()) {
+			tx := block.Transactions()[i]
+			if r.GasUsed == tx.Gas() || (len(r.PostState) == 0 && r.Status != types.ReceiptStatusSuccessful) {
+				t.Fatalf("block %d: tx %d to %v failed (gas %d, used %d)", n, i, tx.To(), tx.Gas(), r.GasUsed)
+			}
+		}
+	}
+
+	state, err := g.blockchain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Check accounts.
+	for _, i := range []uint64{0, 1, 1000, 1999} {
+		if bal := state.GetBalance(genAccountAddress(i)); bal.Uint64() != 1 {
+			t.Errorf("account %d has balance %v", i, bal)
+		}
+	}
+	if state.Exist(genAccountAddress(2000)) {
+		t.Error("too many accounts created")
+	}
+
+	// Check storage.
+	var txinfo struct {
+		Accounts *stateAccountsInfo     `json:"state-accounts"`
+		Storage  []*storageContractInfo `json:"state-storage"`
+	}
+	readJSON(t, filepath.Join(outdir, "txinfo.json"), &txinfo)
+	if txinfo.Accounts == nil || txinfo.Accounts.Accounts != 2000 {
+		t.Fatalf("wrong state-accounts txinfo: %+v", txinfo.Accounts)
+	}
+	if len(txinfo.Storage) != 3 {
+		t.Fatalf("wrong number of storage contracts in txinfo: %d", len(txinfo.Storage))
+	}
+	for i, c := range txinfo.Storage {
+		if c.Slots != 2000 || c.Cleared != 50 {
+			t.Errorf("contract %d: wrong txinfo %+v", i, c)
+		}
+		destroyed := i == 0
+		if (c.Destroyed != nil) != destroyed {
+			t.Errorf("contract %d: wrong destroyed block %v", i, c.Destroyed)
+		}
+		if destroyed {
+			if state.Exist(c.Contract) {
+				t.Errorf("contract %d still exists", i)
+			}
+			continue
+		}
+		for _, slot := range []uint64{0, 49, 50, 1999} {
+			want := common.Hash{}
+			if slot >= 50 {
+				want = common.BigToHash(new(big.Int).SetUint64(slot + 1))
+			}
+			if v := state.GetState(c.Contract, deepstorageKey(slot)); v != want {
+				t.Errorf("contract %d: slot %d has value %x, want %x", i, slot, v, want)
+			}
+		}
+	}
+
+	// Check statistics.
+	var stats stateStats
+	readJSON(t, filepath.Join(outdir, "headstate-stats.json"), &stats)
+	if stats.Accounts < 2000 {
+		t.Errorf("wrong account count %d", stats.Accounts)
+	}
+	if stats.StorageSlots < 2*1950 || stats.MaxStorageSlots != 1950 {
+		t.Errorf("wrong storage slot counts: total %d, max %d", stats.StorageSlots, stats.MaxStorageSlots)
+	}
+	if stats.AccountTrie.Nodes == 0 || stats.StorageTrie.MaxDepth == 0 {
+		t.Errorf("missing trie statistics: %+v", stats)
+	}
+}
+
+// BenchmarkStateAccounts measures the generation of a chain with many accounts. It
+// reports the number of accounts created per second.
+func BenchmarkStateAccounts(b *testing.B) {
+	const count = 20000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cfg := generatorConfig{
+			chainLength: 10,
+			txCount:     1,
+			txInterval:  1,
+			gasLimit:    100_000_000,
+			lastFork:    "prague",
+			merged:      true,
+			outputDir:   b.TempDir(),
+			modifiers: []modifierSpec{
+				{Name: "state-accounts", Params: []byte(fmt.Sprintf(`{"count": %d}`, count))},
+			},
+		}
+		cfg, err := cfg.withDefaults()
+		if err != nil {
+			b.Fatal(err)
+		}
+		g := newGenerator(cfg)
+		if err := g.run(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(count*b.N)/b.Elapsed().Seconds(), "accounts/s")
+}
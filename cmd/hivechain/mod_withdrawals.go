@@ -1,38 +1,62 @@
 package main
 
 import (
+	"encoding/json"
+
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func init() {
 	register("withdrawals", func() blockModifier {
 		return &modWithdrawals{
-			info: make(map[uint64]withdrawalsInfo),
+			info:     make(map[uint64]withdrawalsInfo),
+			perBlock: 2,
+			amount:   100,
 		}
 	})
 }
 
 type modWithdrawals struct {
-	info map[uint64]withdrawalsInfo
+	info     map[uint64]withdrawalsInfo
+	perBlock int    // maximum number of withdrawals in a block
+	amount   uint64 // withdrawal amount in gwei
 }
 
 type withdrawalsInfo struct {
 	Withdrawals []*types.Withdrawal `json:"withdrawals"`
 }
 
+// configure sets the number of withdrawals per block and the withdrawal amount.
+func (m *modWithdrawals) configure(params json.RawMessage) error {
+	var p struct {
+		PerBlock int    `json:"perBlock"`
+		Amount   uint64 `json:"amount"`
+	}
+	if err := strictUnmarshal(params, &p); err != nil {
+		return err
+	}
+	if p.PerBlock != 0 {
+		m.perBlock = p.PerBlock
+	}
+	if p.Amount != 0 {
+		m.amount = p.Amount
+	}
+	return nil
+}
+
 func (m *modWithdrawals) apply(ctx *genBlockContext) bool {
 	if !ctx.ChainConfig().IsShanghai(ctx.Number(), ctx.Timestamp()) {
 		return false
 	}
 	info := m.info[ctx.NumberU64()]
-	if len(info.Withdrawals) >= 2 {
+	if len(info.Withdrawals) >= m.perBlock {
 		return false
 	}
 
 	w := types.Withdrawal{
 		Validator: 5,
 		Address:   pickRecipient(ctx),
-		Amount:    100,
+		Amount:    m.amount,
 	}
 	w.Index = ctx.block.AddWithdrawal(&w)
 	info.Withdrawals = append(info.Withdrawals, &w)
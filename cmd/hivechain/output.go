@@ -1,33 +1,45 @@
 package main
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"golang.org/x/exp/maps"
 )
 
 var outputFunctions = map[string]func(*generator) error{
-	"genesis":        (*generator).writeGenesis,
-	"forkenv":        (*generator).writeForkEnv,
-	"chain":          (*generator).writeChain,
-	"powchain":       (*generator).writePoWChain,
-	"headstate":      (*generator).writeState,
-	"headblock":      (*generator).writeHeadBlock,
-	"accounts":       (*generator).writeAccounts,
-	"txinfo":         (*generator).writeTxInfo,
-	"fcu":            (*generator).writeEngineFcU,
-	"newpayload":     (*generator).writeEngineNewPayload,
-	"headfcu":        (*generator).writeEngineHeadFcU,
-	"headnewpayload": (*generator).writeEngineHeadNewPayload,
+	"genesis":         (*generator).writeGenesis,
+	"forkenv":         (*generator).writeForkEnv,
+	"chain":           (*generator).writeChain,
+	"powchain":        (*generator).writePoWChain,
+	"era1":            (*generator).writeEra1,
+	"headstate":       (*generator).writeState,
+	"headstate-stats": (*generator).writeStateStats,
+	"headblock":       (*generator).writeHeadBlock,
+	"accounts":        (*generator).writeAccounts,
+	"blobs":           (*generator).writeBlobs,
+	"txinfo":          (*generator).writeTxInfo,
+	"txpool":          (*generator).writeTxPool,
+	"fcu":             (*generator).writeEngineFcU,
+	"buildfcu":        (*generator).writeEngineBuildFcU,
+	"replay":          (*generator).writeEngineReplay,
+	"newpayload":      (*generator).writeEngineNewPayload,
+	"headfcu":         (*generator).writeEngineHeadFcU,
+	"headnewpayload":  (*generator).writeEngineHeadNewPayload,
+	"sidechains":      (*generator).writeSideChains,
 }
 
 func outputFunctionNames() []string {
@@ -93,6 +105,63 @@ func (g *generator) writeAccounts() error {
 	return g.writeJSON("accounts.json", &m)
 }
 
+// writeBlobs writes the blob sidecars of all blob transactions.
+func (g *generator) writeBlobs() error {
+	type blobObj struct {
+		Blob       *kzg4844.Blob      `json:"blob"`
+		Commitment kzg4844.Commitment `json:"commitment"`
+		Proof      *kzg4844.Proof     `json:"proof,omitempty"`
+		CellProofs []kzg4844.Proof    `json:"cellProofs,omitempty"`
+	}
+	m := make(map[common.Hash]*blobObj)
+	for _, bsc := range g.sidecars {
+		sc := bsc.get()
+		for i, h := range sc.BlobHashes() {
+			obj := &blobObj{Blob: &sc.Blobs[i], Commitment: sc.Commitments[i]}
+			if sc.Version == types.BlobSidecarVersion1 {
+				obj.CellProofs, _ = sc.CellProofsAt(i)
+			} else {
+				obj.Proof = &sc.Proofs[i]
+			}
+			m[h] = obj
+		}
+	}
+	return g.writeJSON("blobs.json", &m)
+}
+
+// writeEra1 writes all blocks and receipts, including the genesis block, as era1
+// archives of 8192 blocks each. The archives are placed in the era1 subdirectory,
+// along with a checksums.txt file containing the SHA256 hash of each archive.
+func (g *generator) writeEra1() error {
+	dir := filepath.Join(g.cfg.outputDir, "era1")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var (
+		lastBlock = g.blockchain.CurrentBlock().Number.Uint64()
+		td        = new(big.Int)
+		checksums []string
+	)
+	for start := uint64(0); start <= lastBlock; start += era1MaxBlocks {
+		var b era1Builder
+		for num := start; num <= lastBlock && num < start+era1MaxBlocks; num++ {
+			block := g.blockchain.GetBlockByNumber(num)
+			td.Add(td, block.Difficulty())
+			receipts := g.blockchain.GetReceiptsByHash(block.Hash())
+			if err := b.add(block, receipts, td); err != nil {
+				return err
+			}
+		}
+		data, root := b.finish()
+		file := era1Filename(int(start/era1MaxBlocks), root)
+		if err := os.WriteFile(filepath.Join(dir, file), data, 0644); err != nil {
+			return err
+		}
+		checksums = append(checksums, common.Hash(sha256.Sum256(data)).Hex())
+	}
+	return os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(strings.Join(checksums, "\n")+"\n"), 0644)
+}
+
 // writeState writes the chain state dump.
 func (g *generator) writeState() error {
 	headstate, err := g.blockchain.State()
@@ -162,5 +231,12 @@ func (g *generator) writeTxInfo() error {
 	for _, inst := range g.mods {
 		m[inst.name] = inst.txInfo()
 	}
+	if len(g.sideChains) > 0 {
+		sc := make(map[string]*sideChainInfo, len(g.sideChains))
+		for _, c := range g.sideChains {
+			sc[c.spec.Name] = c.info(g.blockchain)
+		}
+		m["sidechains"] = sc
+	}
 	return g.writeJSON("txinfo.json", &m)
 }
@@ -5,22 +5,32 @@ import (
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// writeEngineNewPayload writes engine API newPayload requests for the chain.
+// writeEngineNewPayload writes engine API newPayload requests for the chain, and the
+// blobs bundles of the requests, keyed by request ID.
 // Note this only works for post-merge blocks.
 func (g *generator) writeEngineNewPayload() error {
 	list := make([]*rpcRequest, 0)
+	bundles := make(map[string]*engine.BlobsBundle)
 	start, ok := g.mergeBlock()
 	if ok {
 		last := g.blockchain.CurrentBlock().Number.Uint64()
 		for num := start; num <= last; num++ {
 			b := g.blockchain.GetBlockByNumber(num)
-			list = append(list, g.block2newpayload(b))
+			np := g.block2newpayload(b)
+			list = append(list, np)
+			if bundle := g.blobsBundle(b); bundle != nil {
+				bundles[np.ID] = bundle
+			}
 		}
 	}
-	return g.writeJSON("newpayload.json", list)
+	if err := g.writeJSON("newpayload.json", list); err != nil {
+		return err
+	}
+	return g.writeJSON("newpayload-blobs.json", bundles)
 }
 
 // writeEngineFcU writes engine API forkchoiceUpdated requests for the chain.
@@ -32,12 +42,48 @@ func (g *generator) writeEngineFcU() error {
 		last := g.blockchain.CurrentBlock().Number.Uint64()
 		for num := start; num <= last; num++ {
 			b := g.blockchain.GetBlockByNumber(num)
-			list = append(list, g.block2fcu(b))
+			list = append(list, g.block2fcu(b, g.blockchain.CurrentFinalBlock()))
 		}
 	}
 	return g.writeJSON("fcu.json", list)
 }
 
+// writeEngineBuildFcU writes engine API forkchoiceUpdated requests with payload
+// attributes. For each post-merge block, the request sets the parent block as head and
+// carries the attributes for building the block.
+func (g *generator) writeEngineBuildFcU() error {
+	list := make([]*rpcRequest, 0)
+	start, ok := g.mergeBlock()
+	if ok {
+		last := g.blockchain.CurrentBlock().Number.Uint64()
+		for num := max(start, 1); num <= last; num++ {
+			b := g.blockchain.GetBlockByNumber(num)
+			list = append(list, g.block2buildfcu(b, g.blockchain.CurrentFinalBlock()))
+		}
+	}
+	return g.writeJSON("buildfcu.json", list)
+}
+
+// writeEngineReplay writes a script of JSON-RPC batches, which takes a client from the
+// merge block to the head block using only the engine API. There is one batch per block,
+// containing newPayload and forkchoiceUpdated for the block. For chains that are
+// proof-of-stake from genesis, the script is complete.
+func (g *generator) writeEngineReplay() error {
+	batches := make([][]*rpcRequest, 0)
+	start, ok := g.mergeBlock()
+	if ok {
+		if start > 1 {
+			fmt.Printf("warning: replay starts at merge block %d, pre-merge blocks must be imported\n", start)
+		}
+		last := g.blockchain.CurrentBlock().Number.Uint64()
+		for num := max(start, 1); num <= last; num++ {
+			b := g.blockchain.GetBlockByNumber(num)
+			batches = append(batches, []*rpcRequest{g.block2newpayload(b), g.block2fcu(b, g.blockchain.CurrentFinalBlock())})
+		}
+	}
+	return g.writeJSON("replay.json", batches)
+}
+
 // writeEngineHeadNewPayload writes an engine API newPayload request for the head block.
 func (g *generator) writeEngineHeadNewPayload() error {
 	h := g.blockchain.CurrentBlock()
@@ -50,7 +96,7 @@ func (g *generator) writeEngineHeadNewPayload() error {
 func (g *generator) writeEngineHeadFcU() error {
 	h := g.blockchain.CurrentBlock()
 	b := g.blockchain.GetBlock(h.Hash(), h.Number.Uint64())
-	fcu := g.block2fcu(b)
+	fcu := g.block2fcu(b, g.blockchain.CurrentFinalBlock())
 	return g.writeJSON("headfcu.json", fcu)
 }
 
@@ -92,11 +138,15 @@ func (g *generator) block2newpayload(b *types.Block) *rpcRequest {
 	switch {
 	case cfg.IsPrague(b.Number(), b.Time()):
 		method = "engine_newPayloadV4"
-		requests, ok := g.clRequests[b.NumberU64()]
+		requests, ok := g.clRequests[b.Hash()]
 		if !ok {
 			panic(fmt.Sprintf("missing execution requests for block %d", b.NumberU64()))
 		}
-		params = append(params, blobHashes, b.BeaconRoot(), requests)
+		hexRequests := make([]hexutil.Bytes, len(requests))
+		for i, r := range requests {
+			hexRequests[i] = r
+		}
+		params = append(params, blobHashes, b.BeaconRoot(), hexRequests)
 	case cfg.IsCancun(b.Number(), b.Time()):
 		method = "engine_newPayloadV3"
 		params = append(params, blobHashes, b.BeaconRoot())
@@ -109,8 +159,79 @@ func (g *generator) block2newpayload(b *types.Block) *rpcRequest {
 	return &rpcRequest{JsonRPC: "2.0", ID: id, Method: method, Params: params}
 }
 
-func (g *generator) block2fcu(b *types.Block) *rpcRequest {
-	finalized := g.blockchain.CurrentFinalBlock()
+// blobsBundle returns the blobs, commitments and proofs of the blob transactions in b,
+// in the format used by engine_getPayload. For blocks after Osaka, the proofs are cell
+// proofs. It returns nil if the block has no blobs.
+func (g *generator) blobsBundle(b *types.Block) *engine.BlobsBundle {
+	var bundle *engine.BlobsBundle
+	for _, tx := range b.Transactions() {
+		bsc := g.sidecars[tx.Hash()]
+		if bsc == nil {
+			continue
+		}
+		sc := bsc.get()
+		if bundle == nil {
+			bundle = &engine.BlobsBundle{Commitments: []hexutil.Bytes{}, Proofs: []hexutil.Bytes{}, Blobs: []hexutil.Bytes{}}
+		}
+		for i := range sc.Blobs {
+			bundle.Blobs = append(bundle.Blobs, sc.Blobs[i][:])
+			bundle.Commitments = append(bundle.Commitments, sc.Commitments[i][:])
+		}
+		for _, p := range sc.Proofs {
+			bundle.Proofs = append(bundle.Proofs, p[:])
+		}
+	}
+	return bundle
+}
+
+func (g *generator) block2fcu(b *types.Block, finalized *types.Header) *rpcRequest {
+	fc := forkchoiceState(b, finalized)
+	id := fmt.Sprintf("fcu%d", b.NumberU64())
+	return &rpcRequest{JsonRPC: "2.0", ID: id, Method: g.fcuMethod(b), Params: []any{fc, nil}}
+}
+
+// block2buildfcu creates a forkchoiceUpdated request which starts building block b on
+// top of its parent.
+func (g *generator) block2buildfcu(b *types.Block, finalized *types.Header) *rpcRequest {
+	parent := g.blockchain.GetBlock(b.ParentHash(), b.NumberU64()-1)
+	fc := forkchoiceState(parent, finalized)
+
+	attrV1 := payloadAttributesV1{
+		Timestamp:             hexutil.Uint64(b.Time()),
+		Random:                b.MixDigest(),
+		SuggestedFeeRecipient: b.Coinbase(),
+	}
+	var attr any = &attrV1
+	cfg := g.genesis.Config
+	if cfg.IsShanghai(b.Number(), b.Time()) {
+		withdrawals := b.Withdrawals()
+		if withdrawals == nil {
+			withdrawals = make(types.Withdrawals, 0)
+		}
+		attrV2 := payloadAttributesV2{attrV1, withdrawals}
+		attr = &attrV2
+		if cfg.IsCancun(b.Number(), b.Time()) {
+			attr = &payloadAttributesV3{attrV2, b.BeaconRoot()}
+		}
+	}
+	// The method version is determined by the timestamp of the new block.
+	id := fmt.Sprintf("buildfcu%d", b.NumberU64())
+	return &rpcRequest{JsonRPC: "2.0", ID: id, Method: g.fcuMethod(b), Params: []any{fc, attr}}
+}
+
+func (g *generator) fcuMethod(b *types.Block) string {
+	cfg := g.genesis.Config
+	switch {
+	case cfg.IsCancun(b.Number(), b.Time()):
+		return "engine_forkchoiceUpdatedV3"
+	case cfg.IsShanghai(b.Number(), b.Time()):
+		return "engine_forkchoiceUpdatedV2"
+	default:
+		return "engine_forkchoiceUpdatedV1"
+	}
+}
+
+func forkchoiceState(b *types.Block, finalized *types.Header) *engine.ForkchoiceStateV1 {
 	fc := engine.ForkchoiceStateV1{
 		HeadBlockHash:      b.Hash(),
 		SafeBlockHash:      b.Hash(),
@@ -121,19 +242,25 @@ func (g *generator) block2fcu(b *types.Block) *rpcRequest {
 	if b.NumberU64() > finalized.Number.Uint64() {
 		fc.FinalizedBlockHash = finalized.Hash()
 	}
+	return &fc
+}
 
-	var method string
-	cfg := g.genesis.Config
-	switch {
-	case cfg.IsCancun(b.Number(), b.Time()):
-		method = "engine_forkchoiceUpdatedV3"
-	case cfg.IsShanghai(b.Number(), b.Time()):
-		method = "engine_forkchoiceUpdatedV2"
-	default:
-		method = "engine_forkchoiceUpdatedV1"
-	}
-	id := fmt.Sprintf("fcu%d", b.NumberU64())
-	return &rpcRequest{JsonRPC: "2.0", ID: id, Method: method, Params: []any{&fc, nil}}
+// These are the payload attribute versions of the engine API. Unlike
+// engine.PayloadAttributes, they only contain the fields of their version.
+type payloadAttributesV1 struct {
+	Timestamp             hexutil.Uint64 `json:"timestamp"`
+	Random                common.Hash    `json:"prevRandao"`
+	SuggestedFeeRecipient common.Address `json:"suggestedFeeRecipient"`
+}
+
+type payloadAttributesV2 struct {
+	payloadAttributesV1
+	Withdrawals types.Withdrawals `json:"withdrawals"`
+}
+
+type payloadAttributesV3 struct {
+	payloadAttributesV2
+	BeaconRoot *common.Hash `json:"parentBeaconBlockRoot"`
 }
 
 type rpcRequest struct {
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// This test rebuilds the chain from the newPayload requests of the replay script, and
+// imports it using the verify command. It also checks that the forkchoiceUpdated and
+// buildfcu requests match the blocks.
+func TestEngineReplay(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		chainLength:  20,
+		txCount:      1,
+		txInterval:   1,
+		forkInterval: 3,
+		merged:       true,
+		lastFork:     "osaka",
+		outputDir:    outdir,
+		outputs:      []string{"genesis", "replay", "buildfcu"},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var replay [][]rpcRequestJSON
+	readJSON(t, filepath.Join(outdir, "replay.json"), &replay)
+	var buildfcu []rpcRequestJSON
+	readJSON(t, filepath.Join(outdir, "buildfcu.json"), &buildfcu)
+	head := g.blockchain.CurrentBlock()
+	if len(replay) != int(head.Number.Uint64()) || len(buildfcu) != len(replay) {
+		t.Fatalf("wrong number of requests: %d batches, %d buildfcu", len(replay), len(buildfcu))
+	}
+
+	var blocks []*types.Block
+	for i, batch := range replay {
+		if len(batch) != 2 {
+			t.Fatalf("batch %d: wrong number of requests %d", i, len(batch))
+		}
+		block := payloadToBlock(t, batch[0])
+		if block.NumberU64() != uint64(i+1) {
+			t.Fatalf("batch %d: wrong block number %d", i, block.NumberU64())
+		}
+		var fc engine.ForkchoiceStateV1
+		if err := json.Unmarshal(batch[1].Params[0], &fc); err != nil {
+			t.Fatalf("batch %d: invalid forkchoice state: %v", i, err)
+		}
+		if fc.HeadBlockHash != block.Hash() {
+			t.Errorf("batch %d: forkchoiceUpdated head %x, want %x", i, fc.HeadBlockHash, block.Hash())
+		}
+		checkBuildFcU(t, buildfcu[i], g.blockchain.GetBlockByHash(block.Hash()))
+		blocks = append(blocks, block)
+	}
+
+	genesis, err := loadGenesis(filepath.Join(outdir, "genesis.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc, err := verifyChain(genesis, blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+	if h := bc.CurrentBlock().Hash(); h != head.Hash() {
+		t.Fatalf("wrong head %x after replay, want %x", h, head.Hash())
+	}
+}
+
+type rpcRequestJSON struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// payloadToBlock converts a newPayload request into a block. The block hash given in
+// the payload is checked.
+func payloadToBlock(t *testing.T, req rpcRequestJSON) *types.Block {
+	t.Helper()
+	var (
+		ed         engine.ExecutableData
+		hashes     []common.Hash
+		beaconRoot *common.Hash
+		requests   [][]byte
+	)
+	if err := json.Unmarshal(req.Params[0], &ed); err != nil {
+		t.Fatalf("%s: invalid payload: %v", req.Method, err)
+	}
+	if len(req.Params) > 1 {
+		json.Unmarshal(req.Params[1], &hashes)
+		json.Unmarshal(req.Params[2], &beaconRoot)
+	}
+	if len(req.Params) > 3 {
+		var hexRequests []hexutil.Bytes
+		json.Unmarshal(req.Params[3], &hexRequests)
+		requests = make([][]byte, len(hexRequests))
+		for i, r := range hexRequests {
+			requests[i] = r
+		}
+	}
+	block, err := engine.ExecutableDataToBlock(ed, hashes, beaconRoot, requests)
+	if err != nil {
+		t.Fatalf("%s: block %d: %v", req.Method, ed.Number, err)
+	}
+	return block
+}
+
+// checkBuildFcU checks that a buildfcu request has the parent of b as head, and the
+// payload attributes of b.
+func checkBuildFcU(t *testing.T, req rpcRequestJSON, b *types.Block) {
+	t.Helper()
+	var (
+		fc   engine.ForkchoiceStateV1
+		attr engine.PayloadAttributes
+	)
+	if err := json.Unmarshal(req.Params[0], &fc); err != nil {
+		t.Fatalf("block %d: invalid buildfcu forkchoice state: %v", b.NumberU64(), err)
+	}
+	if err := json.Unmarshal(req.Params[1], &attr); err != nil {
+		t.Fatalf("block %d: invalid buildfcu attributes: %v", b.NumberU64(), err)
+	}
+	if fc.HeadBlockHash != b.ParentHash() {
+		t.Errorf("block %d: buildfcu head %x, want parent %x", b.NumberU64(), fc.HeadBlockHash, b.ParentHash())
+	}
+	if attr.Timestamp != b.Time() || attr.Random != b.MixDigest() || attr.SuggestedFeeRecipient != b.Coinbase() {
+		t.Errorf("block %d: wrong buildfcu attributes %+v", b.NumberU64(), attr)
+	}
+	if len(attr.Withdrawals) != len(b.Withdrawals()) {
+		t.Errorf("block %d: buildfcu has %d withdrawals, want %d", b.NumberU64(), len(attr.Withdrawals), len(b.Withdrawals()))
+	}
+	if want := b.BeaconRoot(); (attr.BeaconRoot == nil) != (want == nil) || (want != nil && *attr.BeaconRoot != *want) {
+		t.Errorf("block %d: buildfcu beacon root %v, want %v", b.NumberU64(), attr.BeaconRoot, want)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// stateStats contains statistics about the head state.
+type stateStats struct {
+	Root common.Hash `json:"root"`
+
+	Accounts        uint64 `json:"accounts"`
+	Contracts       uint64 `json:"contracts"`
+	UniqueCode      uint64 `json:"uniqueCode"`
+	CodeSize        uint64 `json:"codeSize"` // total size of unique code
+	StorageSlots    uint64 `json:"storageSlots"`
+	MaxStorageSlots uint64 `json:"maxStorageSlots"` // slots of the largest storage trie
+
+	AccountTrie trieStats `json:"accountTrie"`
+	StorageTrie trieStats `json:"storageTrie"` // totals across all storage tries
+}
+
+// trieStats counts the stored (hashed) nodes of a trie.
+type trieStats struct {
+	Nodes    uint64 `json:"nodes"`
+	Size     uint64 `json:"size"`     // total size of RLP-encoded nodes
+	MaxDepth int    `json:"maxDepth"` // maximum path length in nibbles
+}
+
+// writeStateStats writes statistics about the head state. This is useful for large
+// states, where writing the full dump with the headstate output is impractical.
+func (g *generator) writeStateStats() error {
+	stats, err := computeStateStats(g.blockchain.TrieDB(), g.blockchain.CurrentBlock().Root, g.blockchain.ContractCodeWithPrefix)
+	if err != nil {
+		return err
+	}
+	return g.writeJSON("headstate-stats.json", stats)
+}
+
+func computeStateStats(db *triedb.Database, root common.Hash, code func(common.Hash) []byte) (*stateStats, error) {
+	stats := &stateStats{Root: root}
+	tr, err := trie.New(trie.StateTrieID(root), db)
+	if err != nil {
+		return nil, err
+	}
+	nodeIt, err := tr.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+	seenCode := make(map[common.Hash]bool)
+	for nodeIt.Next(true) {
+		stats.AccountTrie.add(nodeIt)
+		if !nodeIt.Leaf() {
+			continue
+		}
+		acc, err := types.FullAccount(nodeIt.LeafBlob())
+		if err != nil {
+			return nil, err
+		}
+		stats.Accounts++
+		codeHash := common.BytesToHash(acc.CodeHash)
+		if codeHash != types.EmptyCodeHash {
+			stats.Contracts++
+			if !seenCode[codeHash] {
+				seenCode[codeHash] = true
+				stats.UniqueCode++
+				stats.CodeSize += uint64(len(code(codeHash)))
+			}
+		}
+		if acc.Root != types.EmptyRootHash {
+			owner := common.BytesToHash(nodeIt.LeafKey())
+			slots, err := stats.StorageTrie.addStorage(db, trie.StorageTrieID(root, owner, acc.Root))
+			if err != nil {
+				return nil, err
+			}
+			stats.StorageSlots += slots
+			stats.MaxStorageSlots = max(stats.MaxStorageSlots, slots)
+		}
+	}
+	if err := nodeIt.Error(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// add counts the current node of the iterator. Nodes embedded in their parent are not
+// counted since they are not stored separately.
+func (ts *trieStats) add(it trie.NodeIterator) {
+	if it.Hash() == (common.Hash{}) {
+		return
+	}
+	ts.Nodes++
+	ts.Size += uint64(len(it.NodeBlob()))
+	ts.MaxDepth = max(ts.MaxDepth, len(it.Path()))
+}
+
+// addStorage counts the nodes of a storage trie. It returns the number of slots.
+func (ts *trieStats) addStorage(db *triedb.Database, id *trie.ID) (slots uint64, err error) {
+	tr, err := trie.New(id, db)
+	if err != nil {
+		return 0, err
+	}
+	it, err := tr.NodeIterator(nil)
+	if err != nil {
+		return 0, err
+	}
+	for it.Next(true) {
+		ts.add(it)
+		if it.Leaf() {
+			slots++
+		}
+	}
+	return slots, it.Error()
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// Expected outcomes of transaction pool scenarios.
+const (
+	txPending  = "pending"  // tx is accepted and executable
+	txQueued   = "queued"   // tx is accepted, but not executable because of a nonce gap
+	txReplaced = "replaced" // tx is accepted, then replaced by a later tx of the scenario
+	txRejected = "rejected" // tx is not accepted
+)
+
+// Replacement price bumps (in percent) required by transaction pools. These are the
+// defaults of go-ethereum.
+const (
+	txPoolPriceBump     = 10
+	txPoolBlobPriceBump = 100
+)
+
+// txPoolOutput is the content of txpool.json.
+type txPoolOutput struct {
+	Head          common.Hash       `json:"head"`
+	HeadNumber    hexutil.Uint64    `json:"headNumber"`
+	BaseFee       *hexutil.Big      `json:"baseFee,omitempty"`     // base fee of the next block
+	BlobBaseFee   *hexutil.Big      `json:"blobBaseFee,omitempty"` // blob base fee of the next block
+	PriceBump     int               `json:"priceBump"`
+	BlobPriceBump int               `json:"blobPriceBump"`
+	Scenarios     []*txPoolScenario `json:"scenarios"`
+}
+
+// txPoolScenario is a list of transactions from a single sender. The transactions
+// should be submitted in order, and the expected outcome applies after all transactions
+// of the scenario have been submitted.
+type txPoolScenario struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Sender      common.Address `json:"sender"`
+	Txs         []*txPoolTx    `json:"txs"`
+}
+
+type txPoolTx struct {
+	Hash   common.Hash    `json:"hash"`
+	Nonce  hexutil.Uint64 `json:"nonce"`
+	Tx     hexutil.Bytes  `json:"tx"` // binary encoding, blob txs include the sidecar
+	Expect string         `json:"expect"`
+	Error  string         `json:"error,omitempty"` // error message for rejected txs
+}
+
+// writeTxPool writes txpool.json, containing transactions which are not included in
+// the chain. They are signed against the head state and can be used to test the
+// transaction pool of a client which has imported the chain.
+func (g *generator) writeTxPool() error {
+	b, err := newTxPoolBuilder(g)
+	if err != nil {
+		return err
+	}
+	b.build()
+	if b.err != nil {
+		return b.err
+	}
+	return g.writeJSON("txpool.json", b.out)
+}
+
+// txPoolBuilder creates the transaction pool scenarios.
+type txPoolBuilder struct {
+	g       *generator
+	head    *types.Header
+	state   *state.StateDB
+	signer  types.Signer
+	baseFee *big.Int // nil before London
+	blobFee *big.Int // nil before Cancun
+	osaka   bool
+
+	senders []genAccount
+	out     *txPoolOutput
+	err     error
+}
+
+func newTxPoolBuilder(g *generator) (*txPoolBuilder, error) {
+	head := g.blockchain.CurrentHeader()
+	statedb, err := g.blockchain.State()
+	if err != nil {
+		return nil, err
+	}
+	config := g.genesis.Config
+	next := &types.Header{Number: new(big.Int).Add(head.Number, big.NewInt(1)), Time: head.Time + blocktimeSec}
+	b := &txPoolBuilder{
+		g:       g,
+		head:    head,
+		state:   statedb,
+		signer:  types.MakeSigner(config, next.Number, next.Time),
+		osaka:   config.IsOsaka(next.Number, next.Time),
+		senders: g.accounts[1:],
+		out: &txPoolOutput{
+			Head:          head.Hash(),
+			HeadNumber:    hexutil.Uint64(head.Number.Uint64()),
+			PriceBump:     txPoolPriceBump,
+			BlobPriceBump: txPoolBlobPriceBump,
+			Scenarios:     []*txPoolScenario{},
+		},
+	}
+	if config.IsLondon(next.Number) {
+		b.baseFee = eip1559.CalcBaseFee(config, head)
+		b.out.BaseFee = (*hexutil.Big)(b.baseFee)
+	}
+	if config.IsCancun(next.Number, next.Time) {
+		excess := eip4844.CalcExcessBlobGas(config, head, next.Time)
+		next.ExcessBlobGas = &excess
+		b.blobFee = eip4844.CalcBlobFee(config, next)
+		b.out.BlobBaseFee = (*hexutil.Big)(b.blobFee)
+	}
+	return b, nil
+}
+
+func (b *txPoolBuilder) build() {
+	b.buildPending()
+	b.buildReplacements()
+	b.buildInvalid()
+	if b.blobFee != nil {
+		b.buildBlobs()
+	}
+}
+
+// buildPending creates scenarios with valid transactions.
+func (b *txPoolBuilder) buildPending() {
+	s, sender, nonce := b.scenario("pending", "consecutive nonces")
+	for i := range uint64(3) {
+		s.add(b.transfer(sender, nonce+i, 0, 0), txPending, nil)
+	}
+
+	s, sender, nonce = b.scenario("queued", "nonce gap before the first tx")
+	s.add(b.transfer(sender, nonce+1, 0, 0), txQueued, nil)
+	s.add(b.transfer(sender, nonce+2, 0, 0), txQueued, nil)
+
+	s, sender, nonce = b.scenario("gap-filled", "queued tx becomes pending when the gap is filled")
+	s.add(b.transfer(sender, nonce+1, 0, 0), txPending, nil)
+	s.add(b.transfer(sender, nonce, 0, 0), txPending, nil)
+}
+
+// buildReplacements creates scenarios where a transaction is replaced by another
+// transaction with the same nonce.
+func (b *txPoolBuilder) buildReplacements() {
+	s, sender, nonce := b.scenario("replace", "replacement with sufficient fee bump")
+	s.add(b.transfer(sender, nonce, 0, 0), txReplaced, nil)
+	s.add(b.transfer(sender, nonce, 2*txPoolPriceBump, 2*txPoolPriceBump), txPending, nil)
+
+	s, sender, nonce = b.scenario("replace-underpriced", "replacement with insufficient fee bump")
+	s.add(b.transfer(sender, nonce, 0, 0), txPending, nil)
+	s.add(b.transfer(sender, nonce, txPoolPriceBump/2, txPoolPriceBump/2), txRejected, txpool.ErrReplaceUnderpriced)
+
+	if b.baseFee != nil {
+		s, sender, nonce = b.scenario("replace-feecap-only", "replacement bumps the fee cap, but not the tip")
+		s.add(b.transfer(sender, nonce, 0, 0), txPending, nil)
+		s.add(b.transfer(sender, nonce, 0, 2*txPoolPriceBump), txRejected, txpool.ErrReplaceUnderpriced)
+	}
+
+	s, sender, nonce = b.scenario("replace-queued", "replacement of a queued tx")
+	s.add(b.transfer(sender, nonce+1, 0, 0), txReplaced, nil)
+	s.add(b.transfer(sender, nonce+1, 2*txPoolPriceBump, 2*txPoolPriceBump), txQueued, nil)
+}
+
+// buildInvalid creates scenarios with transactions that must be rejected.
+func (b *txPoolBuilder) buildInvalid() {
+	// The first account is the sender of all transactions in the chain.
+	if first := b.g.accounts[0]; b.state.GetNonce(first.addr) > 0 {
+		s := b.scenarioWithSender("nonce-too-low", "nonce below the account nonce", first)
+		nonce := b.state.GetNonce(first.addr)
+		s.add(b.transfer(&first, nonce-1, 0, 0), txRejected, core.ErrNonceTooLow)
+	}
+
+	s, sender, nonce := b.scenario("intrinsic-gas", "gas limit below intrinsic gas")
+	s.add(b.sign(sender, b.txdata(nonce, 0, 0, params.TxGas-1, big.NewInt(1))), txRejected, core.ErrIntrinsicGas)
+
+	s, sender, nonce = b.scenario("insufficient-funds", "value exceeds the sender balance")
+	balance := b.state.GetBalance(sender.addr).ToBig()
+	s.add(b.sign(sender, b.txdata(nonce, 0, 0, params.TxGas, balance)), txRejected, core.ErrInsufficientFunds)
+
+	s, sender, nonce = b.scenario("underpriced", "zero gas tip")
+	data := b.txdata(nonce, 0, 0, params.TxGas, big.NewInt(1))
+	switch tx := data.(type) {
+	case *types.DynamicFeeTx:
+		tx.GasTipCap = new(big.Int)
+	case *types.LegacyTx:
+		tx.GasPrice = new(big.Int)
+	}
+	s.add(b.sign(sender, data), txRejected, txpool.ErrTxGasPriceTooLow)
+
+	s, sender, nonce = b.scenario("gas-limit", "gas limit above the block gas limit")
+	gasErr := txpool.ErrGasLimit
+	if b.osaka {
+		gasErr = core.ErrGasLimitTooHigh
+	}
+	s.add(b.sign(sender, b.txdata(nonce, 0, 0, b.head.GasLimit+1, big.NewInt(1))), txRejected, gasErr)
+
+	s, sender, nonce = b.scenario("wrong-chainid", "signed for a different chain ID")
+	otherChain := new(big.Int).Add(b.g.genesis.Config.ChainID, big.NewInt(1))
+	data = b.txdata(nonce, 0, 0, params.TxGas, big.NewInt(1))
+	if tx, ok := data.(*types.DynamicFeeTx); ok {
+		tx.ChainID = otherChain
+	}
+	tx, err := types.SignNewTx(sender.key, types.LatestSignerForChainID(otherChain), data)
+	b.setErr(err)
+	s.add(tx, txRejected, txpool.ErrInvalidSender)
+}
+
+// buildBlobs creates blob transaction scenarios. Blob transaction replacement requires
+// a higher fee bump, and nonce gaps are not allowed.
+func (b *txPoolBuilder) buildBlobs() {
+	s, sender, nonce := b.scenario("blob-pending", "blob transaction")
+	s.add(b.blobTx(sender, nonce, 0), txPending, nil)
+
+	s, sender, nonce = b.scenario("blob-replace", "blob tx replacement with sufficient fee bump")
+	s.add(b.blobTx(sender, nonce, 0), txReplaced, nil)
+	s.add(b.blobTx(sender, nonce, txPoolBlobPriceBump), txPending, nil)
+
+	s, sender, nonce = b.scenario("blob-replace-underpriced", "blob tx replacement with regular fee bump")
+	s.add(b.blobTx(sender, nonce, 0), txPending, nil)
+	s.add(b.blobTx(sender, nonce, txPoolBlobPriceBump/2), txRejected, txpool.ErrReplaceUnderpriced)
+
+	s, sender, nonce = b.scenario("blob-gap", "blob transaction with nonce gap")
+	s.add(b.blobTx(sender, nonce+1, 0), txRejected, core.ErrNonceTooHigh)
+}
+
+// scenario adds a scenario with a new sender account.
+func (b *txPoolBuilder) scenario(name, description string) (s *txPoolScenario, sender *genAccount, nonce uint64) {
+	if len(b.senders) == 0 {
+		b.setErr(fmt.Errorf("txpool: no account left for scenario %q", name))
+		sender = &b.g.accounts[0]
+	} else {
+		sender = &b.senders[0]
+		b.senders = b.senders[1:]
+	}
+	s = b.scenarioWithSender(name, description, *sender)
+	return s, sender, b.state.GetNonce(sender.addr)
+}
+
+func (b *txPoolBuilder) scenarioWithSender(name, description string, sender genAccount) *txPoolScenario {
+	s := &txPoolScenario{Name: name, Description: description, Sender: sender.addr, Txs: []*txPoolTx{}}
+	b.out.Scenarios = append(b.out.Scenarios, s)
+	return s
+}
+
+func (b *txPoolBuilder) setErr(err error) {
+	if b.err == nil && err != nil {
+		b.err = err
+	}
+}
+
+// fees returns the gas tip and fee cap with the given increase in percent. For legacy
+// transactions, the fee cap is used as the gas price.
+func (b *txPoolBuilder) fees(tipBump, capBump int64) (tip, feeCap *big.Int) {
+	tip = big.NewInt(params.GWei)
+	feeCap = new(big.Int).Set(tip)
+	if b.baseFee != nil {
+		feeCap.Add(feeCap, new(big.Int).Mul(b.baseFee, big.NewInt(2)))
+	}
+	return bump(tip, tipBump), bump(feeCap, capBump)
+}
+
+func bump(v *big.Int, percent int64) *big.Int {
+	v = new(big.Int).Mul(v, big.NewInt(100+percent))
+	return v.Div(v, big.NewInt(100))
+}
+
+// txdata creates a value transfer to the first account.
+func (b *txPoolBuilder) txdata(nonce uint64, tipBump, capBump int64, gas uint64, value *big.Int) types.TxData {
+	tip, feeCap := b.fees(tipBump, capBump)
+	to := b.g.accounts[0].addr
+	if b.baseFee == nil {
+		return &types.LegacyTx{Nonce: nonce, GasPrice: feeCap, Gas: gas, To: &to, Value: value}
+	}
+	return &types.DynamicFeeTx{
+		ChainID:   b.g.genesis.Config.ChainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        &to,
+		Value:     value,
+	}
+}
+
+func (b *txPoolBuilder) transfer(sender *genAccount, nonce uint64, tipBump, capBump int64) *types.Transaction {
+	return b.sign(sender, b.txdata(nonce, tipBump, capBump, params.TxGas, big.NewInt(1)))
+}
+
+// blobTx creates a transaction with one blob. All fees are increased by feeBump percent.
+func (b *txPoolBuilder) blobTx(sender *genAccount, nonce uint64, feeBump int64) *types.Transaction {
+	tip, feeCap := b.fees(feeBump, feeBump)
+	blobFeeCap := bump(new(big.Int).Mul(b.blobFee, big.NewInt(2)), feeBump)
+	sidecar := randomBlobSidecar(int64(len(b.out.Scenarios))<<32|feeBump, 1)
+	if b.osaka {
+		b.setErr(sidecar.ToV1())
+	}
+	to := b.g.accounts[0].addr
+	return b.sign(sender, &types.BlobTx{
+		ChainID:    uint256.MustFromBig(b.g.genesis.Config.ChainID),
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(tip),
+		GasFeeCap:  uint256.MustFromBig(feeCap),
+		Gas:        params.TxGas,
+		To:         to,
+		Value:      uint256.NewInt(1),
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+}
+
+func (b *txPoolBuilder) sign(sender *genAccount, data types.TxData) *types.Transaction {
+	tx, err := types.SignNewTx(sender.key, b.signer, data)
+	b.setErr(err)
+	return tx
+}
+
+// add appends a transaction to the scenario.
+func (s *txPoolScenario) add(tx *types.Transaction, expect string, err error) {
+	if tx == nil {
+		return // signing failed
+	}
+	enc, encErr := tx.MarshalBinary()
+	if encErr != nil {
+		panic(encErr)
+	}
+	ptx := &txPoolTx{Hash: tx.Hash(), Nonce: hexutil.Uint64(tx.Nonce()), Tx: enc, Expect: expect}
+	if err != nil {
+		ptx.Error = err.Error()
+	}
+	if (expect == txRejected) != (err != nil) {
+		panic("txpool: rejected transactions must have an error")
+	}
+	s.Txs = append(s.Txs, ptx)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// This test submits the txpool scenarios to the go-ethereum transaction pool.
+func TestTxPool(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		chainLength: 10,
+		txCount:     1,
+		outputDir:   outdir,
+		outputs:     []string{"txpool"},
+		modifiers:   []modifierSpec{{Name: "tx-transfer-eip1559"}},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out txPoolOutput
+	readJSON(t, filepath.Join(outdir, "txpool.json"), &out)
+	if out.Head != g.blockchain.CurrentBlock().Hash() {
+		t.Fatal("wrong head hash")
+	}
+	for _, name := range []string{"pending", "nonce-too-low", "replace-feecap-only", "blob-replace"} {
+		if !slices.ContainsFunc(out.Scenarios, func(s *txPoolScenario) bool { return s.Name == name }) {
+			t.Errorf("missing scenario %q", name)
+		}
+	}
+
+	legacyPool := legacypool.New(legacypool.DefaultConfig, g.blockchain)
+	blobPool := blobpool.New(blobpool.Config{Datadir: t.TempDir()}, g.blockchain, nil)
+	pool, err := txpool.New(1, g.blockchain, []txpool.SubPool{legacyPool, blobPool})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	for _, s := range out.Scenarios {
+		for i, ptx := range s.Txs {
+			var tx types.Transaction
+			if err := tx.UnmarshalBinary(ptx.Tx); err != nil {
+				t.Fatalf("%s: tx %d: %v", s.Name, i, err)
+			}
+			if tx.Hash() != ptx.Hash {
+				t.Errorf("%s: tx %d: wrong hash", s.Name, i)
+			}
+			err := pool.Add([]*types.Transaction{&tx}, true)[0]
+			switch {
+			case ptx.Expect == txRejected && err == nil:
+				t.Errorf("%s: tx %d: not rejected", s.Name, i)
+			case ptx.Expect == txRejected && !strings.Contains(err.Error(), ptx.Error):
+				t.Errorf("%s: tx %d: wrong error %q, want %q", s.Name, i, err, ptx.Error)
+			case ptx.Expect != txRejected && err != nil:
+				t.Errorf("%s: tx %d: rejected: %v", s.Name, i, err)
+			}
+		}
+		if err := pool.Sync(); err != nil {
+			t.Fatal(err)
+		}
+		pending, queued := pool.ContentFrom(s.Sender)
+		for i, ptx := range s.Txs {
+			var status string
+			switch {
+			case containsTx(pending, ptx.Hash):
+				status = txPending
+			case containsTx(queued, ptx.Hash):
+				status = txQueued
+			case pool.Has(ptx.Hash):
+				status = txPending // the blob pool does not list its content
+			case ptx.Expect == txReplaced || ptx.Expect == txRejected:
+				status = ptx.Expect
+			}
+			if status != ptx.Expect {
+				t.Errorf("%s: tx %d: status %q, want %q", s.Name, i, status, ptx.Expect)
+			}
+		}
+	}
+}
+
+func containsTx(txs []*types.Transaction, hash common.Hash) bool {
+	return slices.ContainsFunc(txs, func(tx *types.Transaction) bool { return tx.Hash() == hash })
+}
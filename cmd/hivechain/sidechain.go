@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Side chain perturbations. These define how the transactions of side chain blocks
+// differ from the canonical chain.
+const (
+	perturbTransfers = "transfers" // blocks contain new value transfers (the default)
+	perturbReplay    = "replay"    // blocks contain the txs of the canonical block at the same height
+	perturbEmpty     = "empty"     // blocks contain no transactions
+)
+
+var perturbations = []string{perturbTransfers, perturbReplay, perturbEmpty}
+
+// sideChainSpec configures a side chain.
+type sideChainSpec struct {
+	Name         string `json:"name"`
+	Branch       uint64 `json:"branch"`       // number of the last block shared with the canonical chain
+	Length       int    `json:"length"`       // number of side chain blocks
+	Perturbation string `json:"perturbation"` // how side chain blocks differ
+	TxCount      int    `json:"txCount"`      // number of transfers per block
+}
+
+// sideChain is a generated side chain.
+type sideChain struct {
+	spec   sideChainSpec
+	blocks []*types.Block
+}
+
+// sideChainInfo is the information about a side chain in txinfo.json.
+type sideChainInfo struct {
+	Branch hexutil.Uint64 `json:"branch"`
+	Blocks []common.Hash  `json:"blocks"`
+
+	// These list the transactions which differ between the branches. Only canonical
+	// blocks up to the height of the side chain head are considered.
+	OnlyCanonical []common.Hash `json:"onlyCanonical"`
+	OnlySide      []common.Hash `json:"onlySide"`
+}
+
+// generateSideChains creates the configured side chains. The blocks are validated by
+// inserting them into the blockchain, but they do not become canonical.
+func (g *generator) generateSideChains(db ethdb.Database, engine consensus.Engine) error {
+	head := g.blockchain.CurrentBlock().Number.Uint64()
+	for _, spec := range g.cfg.sideChains {
+		if spec.Branch > head {
+			return fmt.Errorf("side chain %q: branch block %d is beyond head %d", spec.Name, spec.Branch, head)
+		}
+		if merge, ok := g.mergeBlock(); ok && spec.Branch+1 < merge && spec.Branch+uint64(spec.Length) >= merge {
+			return fmt.Errorf("side chain %q: must not span the merge block %d", spec.Name, merge)
+		}
+
+		fmt.Println("generating side chain", spec.Name)
+		parent := g.blockchain.GetBlockByNumber(spec.Branch)
+		g.blockRequests = make(map[uint64][][]byte)
+		blocks, _ := core.GenerateChain(g.genesis.Config, parent, engine, db, spec.Length, func(i int, gen *core.BlockGen) {
+			g.modifySideBlock(&spec, gen)
+		})
+		g.storeRequests(blocks)
+		for _, b := range blocks {
+			if _, err := g.blockchain.InsertBlockWithoutSetHead(b, false); err != nil {
+				return fmt.Errorf("side chain %q: invalid block %d: %v", spec.Name, b.NumberU64(), err)
+			}
+		}
+		g.sideChains = append(g.sideChains, &sideChain{spec: spec, blocks: blocks})
+	}
+	return nil
+}
+
+func (g *generator) modifySideBlock(spec *sideChainSpec, gen *core.BlockGen) {
+	if merge, ok := g.mergeBlock(); ok && gen.Number().Uint64() >= merge {
+		gen.SetPoS()
+	}
+	g.setParentBeaconRoot(gen)
+	gen.SetExtra([]byte("hivechain fork " + spec.Name))
+
+	switch spec.Perturbation {
+	case perturbEmpty:
+	case perturbReplay:
+		if canon := g.blockchain.GetBlockByNumber(gen.Number().Uint64()); canon != nil {
+			for _, tx := range canon.Transactions() {
+				gen.AddTx(tx)
+			}
+		}
+	default:
+		g.addSideTransfers(spec, gen)
+	}
+	g.blockRequests[gen.Number().Uint64()] = gen.ConsensusLayerRequests()
+}
+
+// addSideTransfers adds value transfers to a side chain block.
+func (g *generator) addSideTransfers(spec *sideChainSpec, gen *core.BlockGen) {
+	count := max(spec.TxCount, 1)
+	sender := g.accounts[0]
+	for i := range count {
+		if gen.Gas() < params.TxGas {
+			return
+		}
+		recipient := g.accounts[(int(gen.Number().Uint64())+i)%len(g.accounts)].addr
+		value := big.NewInt(int64(i + 1))
+		nonce := gen.TxNonce(sender.addr)
+		var txdata types.TxData
+		if g.genesis.Config.IsLondon(gen.Number()) {
+			txdata = &types.DynamicFeeTx{
+				ChainID:   g.genesis.Config.ChainID,
+				Nonce:     nonce,
+				GasTipCap: big.NewInt(1),
+				GasFeeCap: new(big.Int).Add(gen.BaseFee(), big.NewInt(1)),
+				Gas:       params.TxGas,
+				To:        &recipient,
+				Value:     value,
+			}
+		} else {
+			txdata = &types.LegacyTx{
+				Nonce:    nonce,
+				GasPrice: big.NewInt(1),
+				Gas:      params.TxGas,
+				To:       &recipient,
+				Value:    value,
+			}
+		}
+		gen.AddTx(types.MustSignNewTx(sender.key, gen.Signer(), txdata))
+	}
+}
+
+// info computes the txinfo of the side chain.
+func (sc *sideChain) info(bc *core.BlockChain) *sideChainInfo {
+	info := &sideChainInfo{
+		Branch:        hexutil.Uint64(sc.spec.Branch),
+		Blocks:        make([]common.Hash, 0, len(sc.blocks)),
+		OnlyCanonical: []common.Hash{},
+		OnlySide:      []common.Hash{},
+	}
+	var canonTxs, sideTxs []common.Hash
+	for _, b := range sc.blocks {
+		info.Blocks = append(info.Blocks, b.Hash())
+		for _, tx := range b.Transactions() {
+			sideTxs = append(sideTxs, tx.Hash())
+		}
+		if canon := bc.GetBlockByNumber(b.NumberU64()); canon != nil {
+			for _, tx := range canon.Transactions() {
+				canonTxs = append(canonTxs, tx.Hash())
+			}
+		}
+	}
+	for _, h := range canonTxs {
+		if !slices.Contains(sideTxs, h) {
+			info.OnlyCanonical = append(info.OnlyCanonical, h)
+		}
+	}
+	for _, h := range sideTxs {
+		if !slices.Contains(canonTxs, h) {
+			info.OnlySide = append(info.OnlySide, h)
+		}
+	}
+	return info
+}
+
+// writeSideChains writes the side chain outputs.
+func (g *generator) writeSideChains() error {
+	for _, sc := range g.sideChains {
+		out, err := g.openOutputFile(fmt.Sprintf("chain-fork-%s.rlp", sc.spec.Name))
+		if err != nil {
+			return err
+		}
+		for _, b := range sc.blocks {
+			if err := b.EncodeRLP(out); err != nil {
+				out.Close()
+				return err
+			}
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+
+		// Write engine API requests for post-merge blocks.
+		merge, ok := g.mergeBlock()
+		if !ok {
+			continue
+		}
+		// The finalized block of the canonical chain may not be an ancestor of
+		// the side chain. In this case, the branch block is used.
+		finalized := g.blockchain.CurrentFinalBlock()
+		if finalized.Number.Uint64() > sc.spec.Branch {
+			finalized = g.blockchain.GetHeaderByNumber(sc.spec.Branch)
+		}
+		newpayload := make([]*rpcRequest, 0)
+		fcu := make([]*rpcRequest, 0)
+		for _, b := range sc.blocks {
+			if b.NumberU64() >= merge {
+				newpayload = append(newpayload, g.block2newpayload(b))
+				fcu = append(fcu, g.block2fcu(b, finalized))
+			}
+		}
+		if len(newpayload) == 0 {
+			continue
+		}
+		if err := g.writeJSON(fmt.Sprintf("newpayload-fork-%s.json", sc.spec.Name), newpayload); err != nil {
+			return err
+		}
+		if err := g.writeJSON(fmt.Sprintf("fcu-fork-%s.json", sc.spec.Name), fcu); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestSideChains(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		chainLength:  40,
+		txInterval:   1,
+		txCount:      4,
+		forkInterval: 1,
+		outputDir:    outdir,
+		outputs:      []string{"chain", "sidechains", "txinfo"},
+		sideChains: []sideChainSpec{
+			{Name: "pow", Branch: 3, Length: 4},
+			{Name: "transfers", Branch: 30, Length: 5, TxCount: 3},
+			{Name: "replay", Branch: 32, Length: 3, Perturbation: perturbReplay},
+			{Name: "empty", Branch: 35, Length: 6, Perturbation: perturbEmpty},
+		},
+	}
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var txinfo struct {
+		SideChains map[string]*sideChainInfo `json:"sidechains"`
+	}
+	content, err := os.ReadFile(filepath.Join(outdir, "txinfo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(content, &txinfo); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, spec := range cfg.sideChains {
+		blocks := readChainFile(t, filepath.Join(outdir, "chain-fork-"+spec.Name+".rlp"))
+		if len(blocks) != spec.Length {
+			t.Fatalf("%s: wrong number of blocks %d", spec.Name, len(blocks))
+		}
+		branch := g.blockchain.GetBlockByNumber(spec.Branch)
+		if blocks[0].ParentHash() != branch.Hash() {
+			t.Errorf("%s: first block does not build on branch block", spec.Name)
+		}
+		for _, b := range blocks {
+			if canon := g.blockchain.GetBlockByNumber(b.NumberU64()); canon != nil && canon.Hash() == b.Hash() {
+				t.Errorf("%s: block %d is canonical", spec.Name, b.NumberU64())
+			}
+		}
+
+		info := txinfo.SideChains[spec.Name]
+		if info == nil {
+			t.Fatalf("%s: missing txinfo", spec.Name)
+		}
+		switch spec.Perturbation {
+		case perturbReplay:
+			if len(info.OnlySide) != 0 || len(info.OnlyCanonical) != 0 {
+				t.Errorf("%s: transactions differ", spec.Name)
+			}
+		case perturbEmpty:
+			if len(info.OnlySide) != 0 || len(info.OnlyCanonical) == 0 {
+				t.Errorf("%s: wrong tx diff %+v", spec.Name, info)
+			}
+		default:
+			if len(info.OnlySide) == 0 {
+				t.Errorf("%s: no side chain transactions", spec.Name)
+			}
+		}
+
+		// Check engine API outputs.
+		_, err := os.Stat(filepath.Join(outdir, "newpayload-fork-"+spec.Name+".json"))
+		if spec.Name == "pow" && err == nil {
+			t.Errorf("%s: newpayload file written for pre-merge blocks", spec.Name)
+		} else if spec.Name != "pow" && err != nil {
+			t.Errorf("%s: %v", spec.Name, err)
+		}
+	}
+}
+
+func TestSideChainAcrossMerge(t *testing.T) {
+	cfg := generatorConfig{
+		chainLength:  20,
+		forkInterval: 1,
+		outputDir:    t.TempDir(),
+		sideChains:   []sideChainSpec{{Name: "bad", Branch: 5, Length: 10}},
+	}
+	cfg, _ = cfg.withDefaults()
+	g := newGenerator(cfg)
+	if err := g.run(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func readChainFile(t *testing.T, file string) (blocks []*types.Block) {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s := rlp.NewStream(bufio.NewReader(f), 0)
+	for {
+		var b types.Block
+		if err := s.Decode(&b); err == io.EOF {
+			return blocks
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, &b)
+	}
+}
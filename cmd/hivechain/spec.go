@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"gopkg.in/yaml.v3"
+)
+
+// chainSpec is the declarative configuration of the chain generator, loaded from the
+// file given by the -spec flag. Settings which are not present in the spec keep their
+// default value. Flags given on the command line override the spec.
+type chainSpec struct {
+	// Chain options. These correspond to the flags of the generate command.
+	Length            int    `json:"length"`
+	TxInterval        int    `json:"txInterval"`
+	TxCount           int    `json:"txCount"`
+	GasLimit          uint64 `json:"gasLimit"`
+	FinalizedDistance int    `json:"finalizedDistance"`
+
+	// Fork options. When Forks is set, ForkInterval, LastFork and PoS are ignored.
+	ForkInterval int            `json:"forkInterval"`
+	LastFork     string         `json:"lastFork"`
+	PoS          bool           `json:"pos"`
+	Forks        *forkSchedule  `json:"forks"`
+	Modifiers    []modifierSpec `json:"modifiers"`
+
+	// Fuzz mode. When enabled, blocks are filled with random transactions.
+	Fuzz bool  `json:"fuzz"`
+	Seed int64 `json:"seed"`
+
+	// Side chains branching off the canonical chain.
+	SideChains []sideChainSpec `json:"sidechains"`
+
+	// Genesis options.
+	Alloc     types.GenesisAlloc `json:"alloc"`     // added to the default genesis alloc
+	Accounts  []string           `json:"accounts"`  // private keys of extra accounts
+	GasLimits []gasLimitChange   `json:"gasLimits"` // gas limit changes over time
+
+	// Output options.
+	Outputs []string `json:"outputs"`
+}
+
+// forkSchedule assigns activation points to forks.
+//
+// Blocks contains fork activation block numbers. For timestamp-based forks, the
+// activation time is computed from the block number. Timestamps contains activation
+// times of timestamp-based (post-merge) forks.
+type forkSchedule struct {
+	Blocks     map[string]uint64 `json:"blocks"`
+	Timestamps map[string]uint64 `json:"timestamps"`
+}
+
+// modifierSpec enables a block modifier.
+type modifierSpec struct {
+	Name   string          `json:"name"`
+	From   uint64          `json:"from"`   // first block where modifier may run
+	To     uint64          `json:"to"`     // last block where modifier may run (0 = no limit)
+	Params json.RawMessage `json:"params"` // modifier-specific parameters
+}
+
+// gasLimitChange configures the target block gas limit starting at a certain block.
+// Note the gas limit can only change by a small amount between blocks, so it may take
+// a while for the target to be reached.
+type gasLimitChange struct {
+	Block    uint64 `json:"block"`
+	GasLimit uint64 `json:"gasLimit"`
+}
+
+// configurableModifier is implemented by block modifiers that accept parameters.
+type configurableModifier interface {
+	blockModifier
+	configure(params json.RawMessage) error
+}
+
+// strictUnmarshal decodes JSON, rejecting unknown object keys.
+func strictUnmarshal(input []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// sideChainNamePattern restricts side chain names. They are used in file names and
+// block extra data, which is limited to 32 bytes.
+var sideChainNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,16}$`)
+
+// loadChainSpec reads a chain spec file. The file can be YAML or JSON.
+func loadChainSpec(file string) (*chainSpec, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := parseChainSpec(content)
+	if err != nil {
+		return nil, fmt.Errorf("error in %s: %v", file, err)
+	}
+	return spec, nil
+}
+
+func parseChainSpec(content []byte) (*chainSpec, error) {
+	// The spec is decoded as YAML first, then converted to JSON. This way, JSON
+	// decoding rules apply to all values, e.g. for the hex-encoded fields of the
+	// genesis alloc. Since YAML is a superset of JSON, JSON input works as well.
+	var raw any
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	js, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var spec chainSpec
+	if err := strictUnmarshal(js, &spec); err != nil {
+		return nil, err
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func (spec *chainSpec) validate() error {
+	if spec.Forks != nil {
+		for fork := range spec.Forks.Blocks {
+			if !slices.Contains(allForkNames, fork) {
+				return fmt.Errorf("unknown fork %q", fork)
+			}
+		}
+		for fork := range spec.Forks.Timestamps {
+			if !slices.Contains(posForkNames, fork) {
+				return fmt.Errorf("fork %q is not timestamp-based", fork)
+			}
+			if _, ok := spec.Forks.Blocks[fork]; ok {
+				return fmt.Errorf("fork %q has both block and timestamp", fork)
+			}
+		}
+		merge, merged := spec.Forks.Blocks["merge"]
+		for _, fork := range posForkNames {
+			_, inBlocks := spec.Forks.Blocks[fork]
+			_, inTimes := spec.Forks.Timestamps[fork]
+			if (inBlocks || inTimes) && !merged {
+				return fmt.Errorf("fork %q requires merge", fork)
+			}
+			if t, ok := spec.Forks.Timestamps[fork]; ok && t < merge*blocktimeSec {
+				return fmt.Errorf("fork %q activates before merge", fork)
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for _, m := range spec.Modifiers {
+		new, ok := modRegistry[m.Name]
+		if !ok {
+			return fmt.Errorf("unknown modifier %q", m.Name)
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("duplicate modifier %q", m.Name)
+		}
+		seen[m.Name] = true
+		if m.To != 0 && m.To < m.From {
+			return fmt.Errorf("modifier %q: invalid block range %d..%d", m.Name, m.From, m.To)
+		}
+		if len(m.Params) > 0 {
+			cm, ok := new().(configurableModifier)
+			if !ok {
+				return fmt.Errorf("modifier %q does not accept parameters", m.Name)
+			}
+			if err := cm.configure(m.Params); err != nil {
+				return fmt.Errorf("modifier %q: %v", m.Name, err)
+			}
+		}
+	}
+	names := make(map[string]bool)
+	for _, sc := range spec.SideChains {
+		if !sideChainNamePattern.MatchString(sc.Name) {
+			return fmt.Errorf("invalid side chain name %q", sc.Name)
+		}
+		if names[sc.Name] {
+			return fmt.Errorf("duplicate side chain %q", sc.Name)
+		}
+		names[sc.Name] = true
+		if sc.Length < 1 {
+			return fmt.Errorf("side chain %q: length must be positive", sc.Name)
+		}
+		if sc.Perturbation != "" && !slices.Contains(perturbations, sc.Perturbation) {
+			return fmt.Errorf("side chain %q: unknown perturbation %q", sc.Name, sc.Perturbation)
+		}
+	}
+	for _, key := range spec.Accounts {
+		if _, err := crypto.HexToECDSA(strings.TrimPrefix(key, "0x")); err != nil {
+			return fmt.Errorf("invalid account key: %v", err)
+		}
+	}
+	for i, c := range spec.GasLimits {
+		if c.GasLimit < params.MinGasLimit {
+			return fmt.Errorf("gas limit %d at block %d too low", c.GasLimit, c.Block)
+		}
+		if i > 0 && c.Block <= spec.GasLimits[i-1].Block {
+			return errors.New("gasLimits must be sorted by block number")
+		}
+	}
+	for _, name := range spec.Outputs {
+		if outputFunctions[name] == nil {
+			return fmt.Errorf("unknown output %q", name)
+		}
+	}
+	return nil
+}
+
+// apply sets the configuration options in cfg.
+func (spec *chainSpec) apply(cfg *generatorConfig) {
+	if spec.Length != 0 {
+		cfg.chainLength = spec.Length
+	}
+	if spec.TxInterval != 0 {
+		cfg.txInterval = spec.TxInterval
+	}
+	if spec.TxCount != 0 {
+		cfg.txCount = spec.TxCount
+	}
+	if spec.GasLimit != 0 {
+		cfg.gasLimit = spec.GasLimit
+	}
+	if spec.FinalizedDistance != 0 {
+		cfg.finalizedDistance = spec.FinalizedDistance
+	}
+	if spec.ForkInterval != 0 {
+		cfg.forkInterval = spec.ForkInterval
+	}
+	if spec.LastFork != "" {
+		cfg.lastFork = spec.LastFork
+	}
+	if spec.PoS {
+		cfg.merged = true
+	}
+	if spec.Forks != nil {
+		cfg.forkSchedule = spec.Forks
+	}
+	if spec.Modifiers != nil {
+		cfg.modifiers = spec.Modifiers
+	}
+	if spec.Fuzz {
+		cfg.fuzz = true
+	}
+	if spec.Seed != 0 {
+		cfg.fuzzSeed = spec.Seed
+	}
+	cfg.sideChains = spec.SideChains
+	cfg.alloc = spec.Alloc
+	for _, key := range spec.Accounts {
+		k, _ := crypto.HexToECDSA(strings.TrimPrefix(key, "0x"))
+		cfg.extraAccounts = append(cfg.extraAccounts, genAccount{key: k, addr: crypto.PubkeyToAddress(k.PublicKey)})
+	}
+	cfg.gasLimitChanges = spec.GasLimits
+	if spec.Outputs != nil {
+		cfg.outputs = spec.Outputs
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testSpec = `
+length: 40
+txInterval: 1
+txCount: 5
+forks:
+  blocks:
+    homestead: 0
+    tangerinewhistle: 0
+    spuriousdragon: 0
+    byzantium: 0
+    constantinople: 0
+    petersburg: 0
+    istanbul: 0
+    muirglacier: 0
+    berlin: 0
+    london: 0
+    arrowglacier: 0
+    grayglacier: 0
+    merge: 5
+  timestamps:
+    shanghai: 100
+    cancun: 200
+modifiers:
+  - name: tx-transfer-eip1559
+    from: 10
+    to: 20
+  - name: withdrawals
+    params:
+      perBlock: 3
+      amount: 7
+alloc:
+  "0x0000000000000000000000000000000000001234":
+    balance: "0x10"
+    code: "0x6001"
+accounts:
+  - "0xb71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+gasLimits:
+  - block: 20
+    gasLimit: 90000000
+outputs: [genesis, chain]
+`
+
+func TestChainSpec(t *testing.T) {
+	spec, err := parseChainSpec([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := generatorConfig{outputDir: t.TempDir()}
+	spec.apply(&cfg)
+	cfg, err = cfg.withDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(cfg)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check fork schedule.
+	config := g.genesis.Config
+	if config.MergeNetsplitBlock.Uint64() != 5 {
+		t.Errorf("wrong merge block %v", config.MergeNetsplitBlock)
+	}
+	if *config.ShanghaiTime != 100 || *config.CancunTime != 200 {
+		t.Errorf("wrong fork times: shanghai %d, cancun %d", *config.ShanghaiTime, *config.CancunTime)
+	}
+	if config.PragueTime != nil {
+		t.Error("prague is enabled")
+	}
+
+	// Check genesis.
+	extra := common.HexToAddress("0x1234")
+	if acc, ok := g.genesis.Alloc[extra]; !ok || acc.Balance.Cmp(big.NewInt(16)) != 0 {
+		t.Errorf("wrong alloc for %v: %+v", extra, acc)
+	}
+	account := g.accounts[len(g.accounts)-1].addr
+	if acc := g.genesis.Alloc[account]; acc.Balance == nil || acc.Balance.Cmp(initialBalance) != 0 {
+		t.Errorf("extra account %v not funded", account)
+	}
+
+	// Check modifiers.
+	for _, block := range g.blockchain.GetBlocksFromHash(g.blockchain.CurrentBlock().Hash(), 40) {
+		num := block.NumberU64()
+		if len(block.Transactions()) > 0 && (num < 10 || num > 20) {
+			t.Errorf("block %d has transactions", num)
+		}
+		if len(block.Withdrawals()) > 3 {
+			t.Errorf("block %d has %d withdrawals", num, len(block.Withdrawals()))
+		}
+		for _, w := range block.Withdrawals() {
+			if w.Amount != 7 {
+				t.Errorf("block %d: wrong withdrawal amount %d", num, w.Amount)
+			}
+		}
+		switch {
+		case num < 20 && block.GasLimit() != defaultGasLimit:
+			t.Errorf("block %d: gas limit changed to %d", num, block.GasLimit())
+		case num >= 20 && block.GasLimit() >= defaultGasLimit:
+			t.Errorf("block %d: gas limit not lowered", num)
+		}
+	}
+}
+
+func TestChainSpecErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		err  string
+	}{
+		{spec: `length: 10
+foo: 1`, err: `json: unknown field "foo"`},
+		{spec: `forks: {blocks: {foo: 1}}`, err: `unknown fork "foo"`},
+		{spec: `forks: {timestamps: {london: 1}}`, err: `fork "london" is not timestamp-based`},
+		{spec: `forks: {timestamps: {shanghai: 1}}`, err: `fork "shanghai" requires merge`},
+		{spec: `modifiers: [{name: foo}]`, err: `unknown modifier "foo"`},
+		{spec: `modifiers: [{name: uncles, params: {x: 1}}]`, err: `modifier "uncles" does not accept parameters`},
+		{spec: `modifiers: [{name: withdrawals, params: {x: 1}}]`, err: `modifier "withdrawals": json: unknown field "x"`},
+		{spec: `outputs: [foo]`, err: `unknown output "foo"`},
+	}
+	for _, test := range tests {
+		_, err := parseChainSpec([]byte(test.spec))
+		if err == nil {
+			t.Errorf("spec %q: expected error", test.spec)
+		} else if err.Error() != test.err {
+			t.Errorf("spec %q: wrong error %q, want %q", test.spec, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// verifyCommand imports a chain.rlp file or era1 directory to check its validity.
+func verifyCommand(args []string) {
+	flag.CommandLine.Parse(args)
+	if flag.NArg() != 2 {
+		fatalf("Usage: hivechain verify <genesis.json> <chain.rlp | era1-dir>")
+	}
+	genesis, err := loadGenesis(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	chain, err := loadBlocks(flag.Arg(1))
+	if err != nil {
+		fatal(err)
+	}
+	bc, err := verifyChain(genesis, chain)
+	if err != nil {
+		fatal(err)
+	}
+	defer bc.Stop()
+	head := bc.CurrentBlock()
+	fmt.Printf("chain is valid, head block %d (%x)\n", head.Number, head.Hash())
+}
+
+// verifyChain imports the blocks into an in-memory blockchain. Proof-of-work seals
+// are not verified. If the chain starts with the genesis block, it is skipped.
+//
+// When a block does not link to the previous one, the blocks before it are imported
+// first. This way, an invalid block is reported even if its successors were generated
+// based on a different version of it.
+func verifyChain(genesis *core.Genesis, chain []*types.Block) (*core.BlockChain, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("chain is empty")
+	}
+	if chain[0].NumberU64() == 0 {
+		if h := genesis.ToBlock().Hash(); chain[0].Hash() != h {
+			return nil, fmt.Errorf("genesis block mismatch: chain has %x, genesis.json has %x", chain[0].Hash(), h)
+		}
+		chain = chain[1:]
+	}
+	var linkErr error
+	for i := 1; i < len(chain); i++ {
+		prev, b := chain[i-1], chain[i]
+		if b.ParentHash() != prev.Hash() || b.NumberU64() != prev.NumberU64()+1 {
+			linkErr = fmt.Errorf("chain validation error (block %d, %x): does not link to previous block %d (%x)", b.Number(), b.Hash(), prev.Number(), prev.Hash())
+			chain = chain[:i]
+			break
+		}
+	}
+	engine := beacon.New(ethash.NewFaker())
+	bc, err := importBlocks(genesis, engine, chain)
+	if err != nil {
+		return nil, err
+	}
+	if linkErr != nil {
+		bc.Stop()
+		return nil, linkErr
+	}
+	return bc, nil
+}
+
+// loadGenesis reads a genesis.json file.
+func loadGenesis(file string) (*core.Genesis, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var genesis core.Genesis
+	if err := json.Unmarshal(content, &genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file %s: %v", file, err)
+	}
+	return &genesis, nil
+}
+
+// loadBlocks reads blocks from a chain.rlp file or a directory of era1 archives.
+// Era1 archives are checked for consistency while loading.
+func loadBlocks(path string) ([]*types.Block, error) {
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return loadChain(path)
+	}
+	archives, err := readEra1Dir(path)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []*types.Block
+	for _, a := range archives {
+		blocks = append(blocks, a.blocks...)
+	}
+	return blocks, nil
+}
+
+// loadChain reads all blocks of a chain.rlp file.
+func loadChain(file string) ([]*types.Block, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var blocks []*types.Block
+	s := rlp.NewStream(bufio.NewReader(f), 0)
+	for i := 0; ; i++ {
+		var b types.Block
+		if err := s.Decode(&b); err == io.EOF {
+			return blocks, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: block %d: %v", file, i, err)
+		}
+		blocks = append(blocks, &b)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestVerify(t *testing.T) {
+	outdir := t.TempDir()
+	cfg := generatorConfig{
+		chainLength:  20,
+		txInterval:   1,
+		txCount:      2,
+		forkInterval: 1,
+		outputDir:    outdir,
+		outputs:      []string{"genesis", "chain"},
+	}
+	cfg, _ = cfg.withDefaults()
+	if err := newGenerator(cfg).run(); err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := loadGenesis(filepath.Join(outdir, "genesis.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := loadChain(filepath.Join(outdir, "chain.rlp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bc, err := verifyChain(genesis, chain)
+	if err != nil {
+		t.Fatal("valid chain rejected:", err)
+	}
+	bc.Stop()
+
+	// Chains starting with the genesis block are accepted.
+	withGenesis := append([]*types.Block{genesis.ToBlock()}, chain...)
+	bc, err = verifyChain(genesis, withGenesis)
+	if err != nil {
+		t.Fatal("chain with genesis block rejected:", err)
+	}
+	bc.Stop()
+
+	// Remove a block.
+	gap := slices.Delete(slices.Clone(chain), 10, 11)
+	if _, err := verifyChain(genesis, gap); err == nil || !strings.Contains(err.Error(), "block 12,") {
+		t.Fatalf("wrong error for chain with gap: %v", err)
+	}
+
+	// Modify a block.
+	bad := chain[12].Header()
+	bad.GasUsed++
+	chain[12] = chain[12].WithSeal(bad)
+	_, err = verifyChain(genesis, chain)
+	if err == nil {
+		t.Fatal("invalid chain accepted")
+	}
+	if !strings.Contains(err.Error(), "block 13,") {
+		t.Fatalf("wrong error: %v", err)
+	}
+}
@@ -434,9 +434,62 @@ function formatTestDetails(suiteData, row) {
         $(container).append('<b>Details:</b> Test has no log output.');
     }
 
+    // For failed tests, show the end of each client log.
+    if (!d.summaryResult.pass && testHasClients(d)) {
+        formatClientLogTails(suiteData, d, container);
+    }
+
     return container;
 }
 
+// formatClientLogTails loads the log excerpts of all clients in a test.
+// The .logTail field of each client contains offsets into the client log file.
+function formatClientLogTails(suiteData, testData, container) {
+    const clientLogMaxBytes = 2097152;
+
+    for (let instanceID in testData.clientInfo) {
+        let instanceInfo = testData.clientInfo[instanceID];
+        if (!instanceInfo.logTail) {
+            continue;
+        }
+        let section = document.createElement('div');
+        container.appendChild(section);
+        let spinner = $('<div><div class="spinner-grow text-secondary" role="status"></div>');
+        $(section).append(spinner);
+
+        let logfile = routes.resultsRoot + instanceInfo.logFile;
+        let loader = new testlog.Loader(logfile, instanceInfo.logTail);
+        loader.headAndTailLines(Infinity, clientLogMaxBytes).then(function (log) {
+            spinner.remove();
+            let url = routes.clientLog(suiteData.suiteID, suiteData.name, testData.testIndex, logfile);
+            formatClientLogTail(instanceInfo, url, log.head.concat(log.tail), section);
+        }).catch(function (error) {
+            console.error(error);
+            spinner.remove();
+            let p = document.createElement('p');
+            p.innerHTML = highlightErrorsInTestOutput(html.encode(error.toString()));
+            section.appendChild(p);
+        });
+    }
+}
+
+// formatClientLogTail renders the log excerpt of a single client.
+function formatClientLogTail(instanceInfo, url, lines, container) {
+    let p = document.createElement('p');
+    let link = html.makeLink(url, instanceInfo.name + ' (' + instanceInfo.id + ')');
+    link.classList.add('log-link');
+    p.innerHTML = '<b>Client log tail:</b> ' + link.outerHTML;
+    container.appendChild(p);
+
+    let output = document.createElement('div');
+    output.classList.add('test-output');
+    let el = document.createElement('code');
+    el.classList.add('output-prefix', 'output-suffix');
+    el.innerHTML = formatTestDetailLines(lines);
+    output.appendChild(el);
+    container.appendChild(output);
+}
+
 // formatTestLog formats the test output.
 // logData is an object like { head: "...", tail: "...", hiddenLines: 10 }.
 function formatTestLog(suiteData, testIndex, logData, container) {
@@ -88,6 +88,8 @@ Otherwise, it looks for files in the $HOME directory:
 			"If a very long chain is imported, this timeout may need to be quite large.\n"+
 			"A lower value means that hive won't wait as long in case the node crashes and\n"+
 			"never opens the RPC port.")
+		clientLogTail = flag.Int("client.logtail", 100, "The `number` of lines from the end of each client log that are shown\n"+
+			"alongside the test result. Set to zero to disable log excerpts.")
 	)
 
 	// Add the sim.buildarg flag multiple times to allow multiple build arguments.
@@ -204,6 +206,7 @@ Otherwise, it looks for files in the $HOME directory:
 		SimRandomSeed:      *simRandomSeed,
 		SimDurationLimit:   *simTimeLimit,
 		ClientStartTimeout: *clientTimeout,
+		ClientLogTailLines: *clientLogTail,
 	}
 	runner := libhive.NewRunner(inv, builder, cb)
 
@@ -16,11 +16,12 @@ type TestResult struct {
 
 // TestStartInfo contains metadata about a test which is supplied to the hive API.
 type TestStartInfo struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"display_name"`
-	Location    string `json:"location"`
-	Category    string `json:"category"`
-	Description string `json:"description"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name"`
+	Location    string   `json:"location"`
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 // ExecInfo is the result of running a command in a client container.
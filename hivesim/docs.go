@@ -58,6 +58,15 @@ func (tc *markdownTestCase) description() string {
 	return formatDescription(tc.Description)
 }
 
+// Returns the test case tags formatted as markdown code.
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = fmt.Sprintf("`%s`", tag)
+	}
+	return strings.Join(formatted, ", ")
+}
+
 // Returns the test case markdown representation.
 // Requires the simulation name and the suite name.
 // The depth parameter is used to determine the number of '#' to use for the test case title.
@@ -67,6 +76,11 @@ func (tc *markdownTestCase) toMarkdown(simName string, suiteName string, depth i
 	// Print test case display name
 	sb.WriteString(fmt.Sprintf("%s - %s\n\n", strings.Repeat("#", depth), tc.displayName()))
 
+	// Print tags
+	if len(tc.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("Tags: %s\n\n", formatTags(tc.Tags)))
+	}
+
 	// Print command-line to run
 	sb.WriteString(fmt.Sprintf("%s Run\n\n", strings.Repeat("#", depth+1)))
 	sb.WriteString("<details>\n")
@@ -146,6 +160,29 @@ func (s *markdownSuite) getCategories() []string {
 	return categories
 }
 
+// Returns the sorted list of the tags advertised by the suite and the number of
+// printed test cases with each tag.
+func (s *markdownSuite) getTags() ([]string, map[string]int) {
+	counts := make(map[string]int)
+	for _, tag := range s.Tags {
+		counts[tag] = 0
+	}
+	for _, tcID := range s.testIDs() {
+		tc := s.tests[tcID]
+		if tc.toBePrinted() {
+			for _, tag := range tc.Tags {
+				counts[tag]++
+			}
+		}
+	}
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags, counts
+}
+
 // Returns the markdown representation of the test suite.
 func (s *markdownSuite) toMarkdown(simName string) (string, error) {
 	headerBuilder := strings.Builder{}
@@ -161,6 +198,14 @@ func (s *markdownSuite) toMarkdown(simName string) (string, error) {
 	headerBuilder.WriteString(fmt.Sprintf("```bash\n%s\n```\n\n", s.commandLine(simName)))
 	headerBuilder.WriteString("</details>\n\n")
 
+	if tags, counts := s.getTags(); len(tags) > 0 {
+		headerBuilder.WriteString("## Tags\n\n")
+		for _, tag := range tags {
+			headerBuilder.WriteString(fmt.Sprintf("- `%s`: %d test cases\n", tag, counts[tag]))
+		}
+		headerBuilder.WriteString("\n")
+	}
+
 	categories := s.getCategories()
 
 	tcDepth := 3
@@ -3,6 +3,7 @@ package hivesim
 import (
 	"bytes"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/hive/internal/simapi"
@@ -93,3 +94,43 @@ func TestSuiteDocsGen(t *testing.T) {
 		}
 	}
 }
+
+func TestSuiteDocsTags(t *testing.T) {
+	suite := &markdownSuite{
+		TestRequest: simapi.TestRequest{
+			Name:        "suite",
+			Description: `This is a description of suite.`,
+			Tags:        []string{"sync", "reorg"},
+		},
+		tests: map[TestID]*markdownTestCase{
+			1: {
+				Name:        "test1",
+				Description: `This is a description of test1.`,
+				Tags:        []string{"reorg", "blob"},
+			},
+			2: {
+				Name:        "test2",
+				Description: `This is a description of test2.`,
+				Tags:        []string{"reorg"},
+			},
+			3: {
+				// Not printed, tags are not counted
+				Name: "test3",
+				Tags: []string{"reorg"},
+			},
+		},
+	}
+	markdown, err := suite.toMarkdown("sim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{
+		"## Tags\n\n- `blob`: 1 test cases\n- `reorg`: 2 test cases\n- `sync`: 0 test cases\n\n",
+		"### - test1\n\nTags: `reorg`, `blob`\n\n",
+		"### - test2\n\nTags: `reorg`\n\n",
+	} {
+		if !strings.Contains(markdown, exp) {
+			t.Fatalf("expected %q in markdown:\n%s", exp, markdown)
+		}
+	}
+}
@@ -1,15 +1,18 @@
 package hivesim
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"slices"
@@ -202,6 +205,7 @@ func (sim *Simulation) StartClientWithOptions(testSuite SuiteID, test TestID, cl
 
 	setup := &clientSetup{
 		files: make(map[string]func() (io.ReadCloser, error)),
+		dirs:  make(map[string]fs.FS),
 		config: simapi.NodeConfig{
 			Client:      clientType,
 			Environment: make(map[string]string),
@@ -210,6 +214,9 @@ func (sim *Simulation) StartClientWithOptions(testSuite SuiteID, test TestID, cl
 	for _, opt := range options {
 		opt.apply(setup)
 	}
+	for dir := range setup.dirs {
+		setup.config.Directories = append(setup.config.Directories, dir)
+	}
 
 	err := setup.postWithFiles(url, &resp)
 	if err != nil {
@@ -261,6 +268,144 @@ func (sim *Simulation) UnpauseClient(testSuite SuiteID, test TestID, nodeid stri
 	return err
 }
 
+// SignalClient sends a signal to the main process of a client container.
+// The signal is given by name, e.g. "SIGHUP" or "SIGUSR1".
+func (sim *Simulation) SignalClient(testSuite SuiteID, test TestID, nodeid string, signal string) error {
+	if sim.docs != nil {
+		return errors.New("SignalClient is not supported in docs mode")
+	}
+	url := fmt.Sprintf("%s/testsuite/%d/test/%d/node/%s/signal", sim.url, testSuite, test, nodeid)
+	return post(url, &simapi.SignalRequest{Signal: signal}, nil)
+}
+
+// ReadClientFile reads a file from the filesystem of a client container.
+func (sim *Simulation) ReadClientFile(testSuite SuiteID, test TestID, nodeid string, file string) ([]byte, error) {
+	if sim.docs != nil {
+		return nil, errors.New("ReadClientFile is not supported in docs mode")
+	}
+	resp, err := sim.getClientFile(testSuite, test, nodeid, file)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("content-type") == "application/x-tar" {
+		return nil, fmt.Errorf("%s is not a regular file", file)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// CopyClientDir copies the content of a directory in a client container into the local
+// directory dst. Only regular files and directories are copied.
+func (sim *Simulation) CopyClientDir(testSuite SuiteID, test TestID, nodeid string, dir string, dst string) error {
+	if sim.docs != nil {
+		return errors.New("CopyClientDir is not supported in docs mode")
+	}
+	resp, err := sim.getClientFile(testSuite, test, nodeid, dir)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("content-type") != "application/x-tar" {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	return extractTar(resp.Body, dst)
+}
+
+func (sim *Simulation) getClientFile(testSuite SuiteID, test TestID, nodeid string, file string) (*http.Response, error) {
+	u := fmt.Sprintf("%s/testsuite/%d/test/%d/node/%s/file?path=%s", sim.url, testSuite, test, nodeid, url.QueryEscape(file))
+	resp, err := http.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// extractTar unpacks regular files and directories of a tar archive into dst.
+// The archive entries are expected to be located in a common top-level directory,
+// which is stripped.
+func extractTar(r io.Reader, dst string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		_, name, _ := strings.Cut(strings.TrimSuffix(hdr.Name, "/"), "/")
+		if name == "" {
+			continue // top-level directory
+		}
+		name = filepath.FromSlash(name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid file name %q in archive", hdr.Name)
+		}
+		target := filepath.Join(dst, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, hdr.FileInfo().Mode().Perm()|0600)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RestartOptions configures a client restart.
+type RestartOptions struct {
+	// Kill terminates the client using SIGKILL. By default, the client receives
+	// SIGTERM and can shut down gracefully.
+	Kill bool
+
+	// Environment contains overrides of client environment variables. All variable
+	// names must start with HIVE_. When overrides are given, the client container is
+	// replaced by a new container with the same filesystem content, and thus gets a
+	// new container ID.
+	Environment map[string]string
+}
+
+// RestartClient stops the client and starts it again. The client data directory is
+// preserved. Returns the container ID and IP of the restarted client.
+func (sim *Simulation) RestartClient(testSuite SuiteID, test TestID, nodeid string, opts RestartOptions) (string, net.IP, error) {
+	if sim.docs != nil {
+		return "", nil, errors.New("RestartClient is not supported in docs mode")
+	}
+	var (
+		url  = fmt.Sprintf("%s/testsuite/%d/test/%d/node/%s/restart", sim.url, testSuite, test, nodeid)
+		req  = &simapi.RestartRequest{Kill: opts.Kill, Environment: opts.Environment}
+		resp simapi.StartNodeResponse
+	)
+	if err := post(url, req, &resp); err != nil {
+		return "", nil, err
+	}
+	ip := net.ParseIP(resp.IP)
+	if ip == nil {
+		return resp.ID, nil, fmt.Errorf("no IP address returned")
+	}
+	return resp.ID, ip, nil
+}
+
 // ClientEnodeURL returns the enode URL of a running client.
 func (sim *Simulation) ClientEnodeURL(testSuite SuiteID, test TestID, node string) (string, error) {
 	if sim.docs != nil {
@@ -416,6 +561,22 @@ func (setup *clientSetup) postWithFiles(url string, result interface{}) error {
 			}
 		}
 
+		// Upload directories as tar archives.
+		for dir, fsys := range setup.dirs {
+			fw, err := form.CreateFormFile(dir, filepath.Base(dir)+".tar")
+			if err != nil {
+				return err
+			}
+			tw := tar.NewWriter(fw)
+			if err := tw.AddFS(fsys); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: upload error for %s: %v\n", dir, err)
+				return err
+			}
+			if err := tw.Close(); err != nil {
+				return err
+			}
+		}
+
 		// Form must be closed or the request will be missing the terminating boundary.
 		if err := form.Close(); err != nil {
 			return err
@@ -487,21 +648,7 @@ func request(httpReq *http.Request, result interface{}) error {
 
 	switch {
 	case resp.StatusCode >= 400:
-		// It's an error response.
-		switch resp.Header.Get("content-type") {
-		case "application/json":
-			var errobj simapi.Error
-			if err := dec.Decode(&errobj); err != nil {
-				return fmt.Errorf("request failed (status %d) and can't decode error message: %v", resp.StatusCode, err)
-			}
-			return errors.New(errobj.Error)
-		default:
-			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-			if len(respBody) == 0 {
-				return fmt.Errorf("request failed (status %d)", resp.StatusCode)
-			}
-			return fmt.Errorf("request failed (status %d): %s", resp.StatusCode, respBody)
-		}
+		return responseError(resp)
 	case resp.StatusCode >= 200 && resp.StatusCode < 300:
 		// Request was successful.
 		if result != nil {
@@ -515,3 +662,21 @@ func request(httpReq *http.Request, result interface{}) error {
 		return fmt.Errorf("invalid response status code %d", resp.StatusCode)
 	}
 }
+
+// responseError decodes the error message of a failed API request.
+func responseError(resp *http.Response) error {
+	switch resp.Header.Get("content-type") {
+	case "application/json":
+		var errobj simapi.Error
+		if err := json.NewDecoder(resp.Body).Decode(&errobj); err != nil {
+			return fmt.Errorf("request failed (status %d) and can't decode error message: %v", resp.StatusCode, err)
+		}
+		return errors.New(errobj.Error)
+	default:
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if len(respBody) == 0 {
+			return fmt.Errorf("request failed (status %d)", resp.StatusCode)
+		}
+		return fmt.Errorf("request failed (status %d): %s", resp.StatusCode, respBody)
+	}
+}
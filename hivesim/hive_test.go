@@ -1,14 +1,19 @@
 package hivesim
 
 import (
+	"archive/tar"
 	"errors"
 	"io"
+	"io/fs"
 	"net"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/hive/internal/fakes"
@@ -241,6 +246,96 @@ func TestStartClientStartOptions(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("extra_args", func(t *testing.T) {
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1",
+			Params{"HIVE_EXTRA_ARGS": "--foo"},
+			WithExtraArgs("--bar=1"), WithExtraArgs("--baz"))
+		if err != nil {
+			t.Fatalf("failed to start client: %v", err)
+		}
+		if got := lastOptions.Env["HIVE_EXTRA_ARGS"]; got != "--foo --bar=1 --baz" {
+			t.Fatalf("wrong HIVE_EXTRA_ARGS, got: %q", got)
+		}
+
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1", WithExtraArgs("--a b"))
+		if err == nil {
+			t.Fatal("expected error for argument containing whitespace")
+		}
+	})
+
+	t.Run("tmpfs", func(t *testing.T) {
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1",
+			WithTmpfs("/datadir"), WithTmpfs("/datadir"))
+		if err != nil {
+			t.Fatalf("failed to start client: %v", err)
+		}
+		if !reflect.DeepEqual(lastOptions.Tmpfs, []string{"/datadir"}) {
+			t.Fatalf("wrong tmpfs mounts: %v", lastOptions.Tmpfs)
+		}
+
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1", WithTmpfs("datadir"))
+		if err == nil {
+			t.Fatal("expected error for relative tmpfs path")
+		}
+	})
+
+	t.Run("time_offset", func(t *testing.T) {
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1", WithTimeOffset(-2*time.Hour))
+		if err != nil {
+			t.Fatalf("failed to start client: %v", err)
+		}
+		if lastOptions.FakeTime != "-7200" {
+			t.Fatalf("wrong time offset %q", lastOptions.FakeTime)
+		}
+
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1", WithTimeOffset(-1500*time.Millisecond))
+		if err != nil {
+			t.Fatalf("failed to start client: %v", err)
+		}
+		if lastOptions.FakeTime != "-1.5" {
+			t.Fatalf("wrong time offset %q", lastOptions.FakeTime)
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"genesis.json":  {Data: []byte("{}")},
+			"keys/key1.txt": {Data: []byte("key1")},
+		}
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1",
+			WithDirectoryFS("/config", fsys))
+		if err != nil {
+			t.Fatalf("failed to start client: %v", err)
+		}
+		if _, ok := lastOptions.Files["/config"]; ok {
+			t.Fatal("directory archive was passed as file")
+		}
+		archive, ok := lastOptions.Directories["/config"]
+		if !ok {
+			t.Fatal("missing /config directory")
+		}
+		f, err := archive.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var names []string
+		tr := tar.NewReader(f)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal("invalid archive:", err)
+			}
+			names = append(names, header.Name)
+		}
+		want := []string{"genesis.json", "keys/", "keys/key1.txt"}
+		if !reflect.DeepEqual(names, want) {
+			t.Fatalf("wrong archive content %v, want %v", names, want)
+		}
+	})
 }
 
 // This checks running scripts in a client container.
@@ -400,6 +495,227 @@ func TestStartClientInitialNetworks(t *testing.T) {
 	}
 }
 
+// This test checks client restarts.
+func TestRestartClient(t *testing.T) {
+	var (
+		stopped   []string
+		killed    []bool
+		recreated []map[string]string
+		started   []libhive.ContainerOptions
+	)
+	tm, srv := newFakeAPI(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			started = append(started, opt)
+			return &libhive.ContainerInfo{}, nil
+		},
+		StopContainer: func(containerID string, kill bool) error {
+			stopped = append(stopped, containerID)
+			killed = append(killed, kill)
+			return nil
+		},
+		RecreateContainer: func(containerID string, env map[string]string) (string, error) {
+			recreated = append(recreated, env)
+			return "recreated", nil
+		},
+	})
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	testID, err := sim.StartTest(suiteID, TestStartInfo{Name: "test"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+	containerID, _, err := sim.StartClientWithOptions(suiteID, testID, "client-1", Params{"HIVE_CHECK_LIVE_PORT": "9000"})
+	if err != nil {
+		t.Fatal("can't start client:", err)
+	}
+
+	// Restart without environment changes keeps the container.
+	id, _, err := sim.RestartClient(suiteID, testID, containerID, RestartOptions{Kill: true})
+	if err != nil {
+		t.Fatal("restart failed:", err)
+	}
+	if id != containerID {
+		t.Fatalf("container ID changed: %s -> %s", containerID, id)
+	}
+	if len(recreated) != 0 {
+		t.Fatal("container was recreated")
+	}
+	last := started[len(started)-1]
+	if !last.AppendLog || last.LogFile != started[0].LogFile || last.CheckLive != 9000 {
+		t.Fatalf("wrong restart options: %+v", last)
+	}
+
+	// Restart with environment changes creates a new container.
+	env := map[string]string{"HIVE_FOO": "1", "OTHER": "2"}
+	id, _, err = sim.RestartClient(suiteID, testID, containerID, RestartOptions{Environment: env})
+	if err != nil {
+		t.Fatal("restart failed:", err)
+	}
+	if id != "recreated" {
+		t.Fatalf("wrong container ID %q after restart", id)
+	}
+	if !reflect.DeepEqual(recreated, []map[string]string{{"HIVE_FOO": "1"}}) {
+		t.Fatalf("wrong environment overrides: %v", recreated)
+	}
+	if !reflect.DeepEqual(stopped, []string{containerID, containerID}) {
+		t.Fatalf("wrong stopped containers: %v", stopped)
+	}
+	if !reflect.DeepEqual(killed, []bool{true, false}) {
+		t.Fatalf("wrong kill flags: %v", killed)
+	}
+
+	// The old ID is no longer valid.
+	if _, _, err := sim.RestartClient(suiteID, testID, containerID, RestartOptions{}); err == nil {
+		t.Fatal("expected error for restarting old container ID")
+	}
+	if _, err := tm.GetNodeInfo(libhive.TestSuiteID(suiteID), libhive.TestID(testID), "recreated"); err != nil {
+		t.Fatal("can't get info of restarted node:", err)
+	}
+}
+
+// This test checks sending signals to clients.
+func TestSignalClient(t *testing.T) {
+	var signals []string
+	tm, srv := newFakeAPI(&fakes.BackendHooks{
+		SignalContainer: func(containerID string, signal string) error {
+			signals = append(signals, containerID+" "+signal)
+			return nil
+		},
+	})
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	testID, err := sim.StartTest(suiteID, TestStartInfo{Name: "test"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+	containerID, _, err := sim.StartClientWithOptions(suiteID, testID, "client-1")
+	if err != nil {
+		t.Fatal("can't start client:", err)
+	}
+
+	if err := sim.SignalClient(suiteID, testID, containerID, "SIGHUP"); err != nil {
+		t.Fatal("signal failed:", err)
+	}
+	if err := sim.SignalClient(suiteID, testID, "unknown", "SIGHUP"); err == nil {
+		t.Fatal("expected error for unknown node")
+	}
+	if err := sim.SignalClient(suiteID, testID, containerID, ""); err == nil {
+		t.Fatal("expected error for empty signal")
+	}
+	want := []string{containerID + " SIGHUP"}
+	if !reflect.DeepEqual(signals, want) {
+		t.Fatalf("wrong signals %v, want %v", signals, want)
+	}
+}
+
+func TestClientFiles(t *testing.T) {
+	// The fake container filesystem.
+	files := fstest.MapFS{
+		"genesis.json":     {Data: []byte(`{"config":{}}`)},
+		"data/keys/key":    {Data: []byte("secret"), Mode: 0600},
+		"data/chain/block": {Data: []byte("block")},
+	}
+	tm, srv := newFakeAPI(&fakes.BackendHooks{
+		CopyFromContainer: func(containerID, path string, w io.Writer) error {
+			// Like docker, put the requested item into the archive under its base name.
+			name := strings.TrimPrefix(path, "/")
+			info, err := fs.Stat(files, name)
+			if err != nil {
+				return libhive.ErrFileNotFound
+			}
+			tw := tar.NewWriter(w)
+			if !info.IsDir() {
+				hdr, _ := tar.FileInfoHeader(info, "")
+				tw.WriteHeader(hdr)
+				tw.Write(files[name].Data)
+				return tw.Close()
+			}
+			sub, _ := fs.Sub(files, name)
+			tw.WriteHeader(&tar.Header{Name: name + "/", Typeflag: tar.TypeDir, Mode: 0755})
+			return fs.WalkDir(sub, ".", func(p string, d fs.DirEntry, err error) error {
+				if p == "." || err != nil {
+					return err
+				}
+				info, _ := d.Info()
+				hdr, _ := tar.FileInfoHeader(info, "")
+				hdr.Name = name + "/" + p
+				tw.WriteHeader(hdr)
+				if !d.IsDir() {
+					tw.Write(files[name+"/"+p].Data)
+				}
+				return nil
+			})
+		},
+	})
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	testID, err := sim.StartTest(suiteID, TestStartInfo{Name: "test"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+	containerID, _, err := sim.StartClientWithOptions(suiteID, testID, "client-1")
+	if err != nil {
+		t.Fatal("can't start client:", err)
+	}
+
+	// Read a single file.
+	content, err := sim.ReadClientFile(suiteID, testID, containerID, "/genesis.json")
+	if err != nil {
+		t.Fatal("ReadClientFile failed:", err)
+	}
+	if string(content) != `{"config":{}}` {
+		t.Fatalf("wrong file content %q", content)
+	}
+	if _, err := sim.ReadClientFile(suiteID, testID, containerID, "/data"); err == nil {
+		t.Fatal("expected error reading directory")
+	}
+	if _, err := sim.ReadClientFile(suiteID, testID, containerID, "/missing"); err == nil {
+		t.Fatal("expected error reading missing file")
+	}
+	if _, err := sim.ReadClientFile(suiteID, testID, containerID, "relative"); err == nil {
+		t.Fatal("expected error for relative path")
+	}
+
+	// Copy a directory.
+	dst := t.TempDir()
+	if err := sim.CopyClientDir(suiteID, testID, containerID, "/data", dst); err != nil {
+		t.Fatal("CopyClientDir failed:", err)
+	}
+	if err := sim.CopyClientDir(suiteID, testID, containerID, "/genesis.json", dst); err == nil {
+		t.Fatal("expected error copying regular file")
+	}
+	for name, want := range map[string]string{
+		"keys/key":    "secret",
+		"chain/block": "block",
+	} {
+		got, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("wrong content of %s: %q, want %q", name, got, want)
+		}
+	}
+}
+
 func newFakeAPI(hooks *fakes.BackendHooks) (*libhive.TestManager, *httptest.Server) {
 	defs := []*libhive.ClientDefinition{
 		{Name: "client-1", Image: "/ignored/in/api", Version: "client-1-version", Meta: libhive.ClientMetadata{Roles: []string{"eth1"}}},
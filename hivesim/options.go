@@ -1,8 +1,14 @@
 package hivesim
 
 import (
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ethereum/hive/internal/simapi"
 )
@@ -12,6 +18,8 @@ type clientSetup struct {
 	config simapi.NodeConfig
 	// destination path -> open data function
 	files map[string]func() (io.ReadCloser, error)
+	// destination path -> source file system
+	dirs map[string]fs.FS
 }
 
 // StartOption is a parameter for starting a client.
@@ -57,6 +65,66 @@ func WithDynamicFile(dstPath string, src func() (io.ReadCloser, error)) StartOpt
 	})
 }
 
+// WithDirectory adds a read-only directory to the client. The content of the
+// local directory srcPath is uploaded into the container at dstPath.
+func WithDirectory(dstPath, srcPath string) StartOption {
+	return WithDirectoryFS(dstPath, os.DirFS(srcPath))
+}
+
+// WithDirectoryFS adds a read-only directory to the client, sourced from the
+// given file system. The file system is transferred as a tar archive.
+func WithDirectoryFS(dstPath string, fsys fs.FS) StartOption {
+	return optionFunc(func(setup *clientSetup) {
+		setup.dirs[dstPath] = fsys
+	})
+}
+
+// WithExtraArgs adds command-line arguments for the client. The arguments are passed to
+// the client start script in the HIVE_EXTRA_ARGS environment variable, and must not
+// contain whitespace.
+func WithExtraArgs(args ...string) StartOption {
+	return optionFunc(func(setup *clientSetup) {
+		setup.config.Args = append(setup.config.Args, args...)
+	})
+}
+
+// WithTmpfs mounts a tmpfs filesystem at the given container directory. This is
+// useful for client data directories, to speed up tests that write a lot of data.
+func WithTmpfs(dir string) StartOption {
+	return optionFunc(func(setup *clientSetup) {
+		if !slices.Contains(setup.config.Tmpfs, dir) {
+			setup.config.Tmpfs = append(setup.config.Tmpfs, dir)
+		}
+	})
+}
+
+// WithTimeOffset shifts the clock of the client by the given duration. This is
+// implemented by injecting libfaketime into the client container. Offsets which are not
+// whole seconds are passed to libfaketime as fractional seconds, e.g. -1500ms is "-1.5".
+//
+// Note that libfaketime only affects programs which obtain the time through the C
+// library. In particular, it has no effect on programs written in Go. It also requires
+// a glibc-based client image.
+func WithTimeOffset(offset time.Duration) StartOption {
+	return optionFunc(func(setup *clientSetup) {
+		setup.config.TimeOffset = faketimeOffset(offset)
+	})
+}
+
+// faketimeOffset formats a duration as a relative libfaketime offset in seconds.
+func faketimeOffset(offset time.Duration) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	s := sign + strconv.FormatInt(int64(offset/time.Second), 10)
+	if frac := offset % time.Second; frac != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", int64(frac)), "0")
+	}
+	return s
+}
+
 // Bundle combines start options, e.g. to bundle files together as option.
 func Bundle(option ...StartOption) StartOption {
 	return optionFunc(func(setup *clientSetup) {
@@ -1,13 +1,16 @@
 package hivesim
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"os"
 	"runtime"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/hive/internal/simapi"
@@ -15,11 +18,12 @@ import (
 
 // Suite is the description of a test suite.
 type Suite struct {
-	Name        string // Name is the unique identifier for the suite [Mandatory]
-	DisplayName string // Display name for the suite (Name will be used if unset) [Optional]
-	Location    string // Documentation output location for the test suite [Optional]
-	Category    string // Category of the test suite [Optional]
-	Description string // Description of the test suite (if empty, suite won't appear in documentation) [Optional]
+	Name        string   // Name is the unique identifier for the suite [Mandatory]
+	DisplayName string   // Display name for the suite (Name will be used if unset) [Optional]
+	Location    string   // Documentation output location for the test suite [Optional]
+	Category    string   // Category of the test suite [Optional]
+	Description string   // Description of the test suite (if empty, suite won't appear in documentation) [Optional]
+	Tags        []string // Tags available to select the tests of the suite, listed in the documentation [Optional]
 	Tests       []AnyTest
 }
 
@@ -30,6 +34,7 @@ func (s *Suite) request() *simapi.TestRequest {
 		Location:    s.Location,
 		Category:    s.Category,
 		Description: s.Description,
+		Tags:        s.Tags,
 	}
 }
 
@@ -108,10 +113,11 @@ func MustRunSuite(host *Simulation, suite Suite) {
 type TestSpec struct {
 	// These fields are displayed in the UI. Be sure to add
 	// a meaningful description here.
-	Name        string // Name is the unique identifier for the test [Mandatory]
-	DisplayName string // Display name for the test (Name will be used if unset) [Optional]
-	Description string // Description of the test (if empty, test won't appear in documentation) [Optional]
-	Category    string // Category of the test [Optional]
+	Name        string   // Name is the unique identifier for the test [Mandatory]
+	DisplayName string   // Display name for the test (Name will be used if unset) [Optional]
+	Description string   // Description of the test (if empty, test won't appear in documentation) [Optional]
+	Category    string   // Category of the test [Optional]
+	Tags        []string // Tags of the test, listed in the documentation [Optional]
 
 	// If AlwaysRun is true, the test will run even if Name does not match the test
 	// pattern. This option is useful for tests that launch a client instance and
@@ -132,10 +138,11 @@ type TestSpec struct {
 type ClientTestSpec struct {
 	// These fields are displayed in the UI. Be sure to add
 	// a meaningful description here.
-	Name        string // Name is the unique identifier for the test [Mandatory]
-	DisplayName string // Display name for the test (Name will be used if unset) [Optional]
-	Description string // Description of the test (if empty, test won't appear in documentation) [Optional]
-	Category    string // Category of the test [Optional]
+	Name        string   // Name is the unique identifier for the test [Mandatory]
+	DisplayName string   // Display name for the test (Name will be used if unset) [Optional]
+	Description string   // Description of the test (if empty, test won't appear in documentation) [Optional]
+	Category    string   // Category of the test [Optional]
+	Tags        []string // Tags of the test, listed in the documentation [Optional]
 
 	// If AlwaysRun is true, the test will run even if Name does not match the test
 	// pattern. This option is useful for tests that launch a client instance and
@@ -215,6 +222,81 @@ func (c *Client) Unpause() error {
 	return c.test.Sim.UnpauseClient(c.test.SuiteID, c.test.TestID, c.Container)
 }
 
+// Signal sends a signal to the client process, e.g. "SIGHUP".
+func (c *Client) Signal(signal string) error {
+	return c.test.Sim.SignalClient(c.test.SuiteID, c.test.TestID, c.Container, signal)
+}
+
+// ReadFile reads a file from the client container.
+func (c *Client) ReadFile(path string) ([]byte, error) {
+	return c.test.Sim.ReadClientFile(c.test.SuiteID, c.test.TestID, c.Container, path)
+}
+
+// CopyDir copies the content of a directory in the client container into the local
+// directory dst.
+func (c *Client) CopyDir(dir string, dst string) error {
+	return c.test.Sim.CopyClientDir(c.test.SuiteID, c.test.TestID, c.Container, dir, dst)
+}
+
+// AttachFile reads a file from the client container and adds its content to the
+// test output. Text files larger than 64 KiB are truncated. Binary files are added as
+// a hex dump of their first 4 KiB.
+func (c *Client) AttachFile(path string) error {
+	content, err := c.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	c.test.Logf("file %s of client %s (%s), %d bytes:\n%s", path, c.Type, c.Container, len(content), formatAttachment(content))
+	return nil
+}
+
+const (
+	maxAttachedText   = 64 * 1024
+	maxAttachedBinary = 4 * 1024
+)
+
+// formatAttachment formats file content for the test output.
+func formatAttachment(content []byte) string {
+	if utf8.Valid(content) && !bytes.ContainsRune(content, 0) {
+		if len(content) <= maxAttachedText {
+			return string(content)
+		}
+		// Cut at a rune boundary.
+		end := maxAttachedText
+		for end > 0 && !utf8.RuneStart(content[end]) {
+			end--
+		}
+		return fmt.Sprintf("%s\n[truncated, %d more bytes]", content[:end], len(content)-end)
+	}
+	if len(content) <= maxAttachedBinary {
+		return hex.Dump(content)
+	}
+	return fmt.Sprintf("%s[binary, %d more bytes]", hex.Dump(content[:maxAttachedBinary]), len(content)-maxAttachedBinary)
+}
+
+// Restart stops the client container and starts it again, preserving the client data.
+// Note that the container ID and IP address of the client may change.
+func (c *Client) Restart(opts RestartOptions) error {
+	container, ip, err := c.test.Sim.RestartClient(c.test.SuiteID, c.test.TestID, c.Container, opts)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Drop the RPC clients, since the connection is lost in any case.
+	if c.rpc != nil {
+		c.rpc.Close()
+		c.rpc = nil
+	}
+	if c.enginerpc != nil {
+		c.enginerpc.Close()
+		c.enginerpc = nil
+	}
+	if err != nil {
+		return err
+	}
+	c.Container = container
+	c.IP = ip
+	return nil
+}
+
 // T is a running test. This is a lot like testing.T, but has some additional methods for
 // launching clients.
 //
@@ -248,6 +330,7 @@ func (t *T) RunClient(clientType string, spec ClientTestSpec) {
 		displayName: spec.DisplayName,
 		category:    spec.Category,
 		desc:        spec.Description,
+		tags:        spec.Tags,
 		alwaysRun:   spec.AlwaysRun,
 	}
 	runTest(t.Sim, test, func(t *T) {
@@ -340,6 +423,7 @@ type testSpec struct {
 	displayName string
 	category    string
 	desc        string
+	tags        []string
 	alwaysRun   bool
 }
 
@@ -349,6 +433,7 @@ func (spec testSpec) request() TestStartInfo {
 		DisplayName: spec.displayName,
 		Category:    spec.category,
 		Description: spec.desc,
+		Tags:        spec.tags,
 	}
 }
 
@@ -418,6 +503,7 @@ func (spec ClientTestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Sui
 			displayName: spec.DisplayName,
 			category:    spec.Category,
 			desc:        spec.Description,
+			tags:        spec.Tags,
 			alwaysRun:   spec.AlwaysRun,
 		}
 		err := runTest(host, test, func(t *T) {
@@ -450,6 +536,7 @@ func (spec TestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Suite) er
 		displayName: spec.DisplayName,
 		category:    spec.Category,
 		desc:        spec.Description,
+		tags:        spec.Tags,
 		alwaysRun:   spec.AlwaysRun,
 	}
 	return runTest(host, test, spec.Run)
@@ -1,10 +1,14 @@
 package hivesim
 
 import (
+	"bytes"
+	"encoding/hex"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/hive/internal/libhive"
@@ -154,3 +158,25 @@ func TestSkipping(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatAttachment(t *testing.T) {
+	if got := formatAttachment([]byte("log line\n")); got != "log line\n" {
+		t.Errorf("wrong text attachment %q", got)
+	}
+
+	long := strings.Repeat("ä", maxAttachedText)
+	got := formatAttachment([]byte(long))
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "[truncated, 65536 more bytes]") {
+		t.Errorf("wrong truncated attachment: ...%q", got[len(got)-40:])
+	}
+
+	binary := []byte{0x00, 0xff, 'a', 'b'}
+	if got := formatAttachment(binary); got != hex.Dump(binary) {
+		t.Errorf("wrong binary attachment %q", got)
+	}
+	large := bytes.Repeat([]byte{0xff}, maxAttachedBinary+10)
+	got = formatAttachment(large)
+	if !strings.HasPrefix(got, hex.Dump(large[:maxAttachedBinary])) || !strings.HasSuffix(got, "[binary, 10 more bytes]") {
+		t.Errorf("wrong large binary attachment: ...%q", got[len(got)-40:])
+	}
+}
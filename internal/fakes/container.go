@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"sync"
@@ -14,12 +15,16 @@ import (
 
 // BackendHooks can be used to override the behavior of the fake backend.
 type BackendHooks struct {
-	CreateContainer  func(image string, opt libhive.ContainerOptions) (string, error)
-	StartContainer   func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error)
-	DeleteContainer  func(containerID string) error
-	PauseContainer   func(containerID string) error
-	UnpauseContainer func(containerID string) error
-	RunProgram       func(containerID string, cmd []string) (*libhive.ExecInfo, error)
+	CreateContainer   func(image string, opt libhive.ContainerOptions) (string, error)
+	StartContainer    func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error)
+	DeleteContainer   func(containerID string) error
+	PauseContainer    func(containerID string) error
+	UnpauseContainer  func(containerID string) error
+	SignalContainer   func(containerID string, signal string) error
+	StopContainer     func(containerID string, kill bool) error
+	RecreateContainer func(containerID string, env map[string]string) (string, error)
+	RunProgram        func(containerID string, cmd []string) (*libhive.ExecInfo, error)
+	CopyFromContainer func(containerID, path string, w io.Writer) error
 
 	NetworkNameToID     func(string) (string, error)
 	CreateNetwork       func(string) (string, error)
@@ -170,6 +175,43 @@ func (b *fakeBackend) UnpauseContainer(containerID string) error {
 	return nil
 }
 
+func (b *fakeBackend) SignalContainer(containerID string, signal string) error {
+	if b.hooks.SignalContainer != nil {
+		return b.hooks.SignalContainer(containerID, signal)
+	}
+	return nil
+}
+
+func (b *fakeBackend) StopContainer(containerID string, kill bool) error {
+	if b.hooks.StopContainer != nil {
+		return b.hooks.StopContainer(containerID, kill)
+	}
+	return nil
+}
+
+func (b *fakeBackend) RecreateContainer(ctx context.Context, containerID string, env map[string]string) (string, error) {
+	var id string
+	var err error
+	if b.hooks.RecreateContainer != nil {
+		id, err = b.hooks.RecreateContainer(containerID, env)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		id = fmt.Sprintf("%0.8x", atomic.AddUint64(&b.clientCounter, 1))
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	image, ok := b.cimg[containerID]
+	if !ok {
+		return "", fmt.Errorf("container %s does not exist", containerID)
+	}
+	delete(b.cimg, containerID)
+	b.cimg[id] = image
+	return id, nil
+}
+
 func (b *fakeBackend) RunProgram(ctx context.Context, containerID string, cmd []string) (*libhive.ExecInfo, error) {
 	if b.hooks.RunProgram != nil {
 		return b.hooks.RunProgram(containerID, cmd)
@@ -177,6 +219,13 @@ func (b *fakeBackend) RunProgram(ctx context.Context, containerID string, cmd []
 	return &libhive.ExecInfo{Stdout: "std output", Stderr: "std err", ExitCode: 0}, nil
 }
 
+func (b *fakeBackend) CopyFromContainer(ctx context.Context, containerID, path string, w io.Writer) error {
+	if b.hooks.CopyFromContainer != nil {
+		return b.hooks.CopyFromContainer(containerID, path, w)
+	}
+	return libhive.ErrFileNotFound
+}
+
 func (b *fakeBackend) NetworkNameToID(name string) (string, error) {
 	if b.hooks.NetworkNameToID != nil {
 		return b.hooks.NetworkNameToID(name)
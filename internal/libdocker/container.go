@@ -10,8 +10,11 @@ import (
 	"log/slog"
 	"mime/multipart"
 	"net"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,10 +33,22 @@ type ContainerBackend struct {
 	// Hive instance information for labeling
 	hiveInstanceID string
 	hiveVersion    string
+
+	// This tracks the images created by RecreateContainer.
+	// They are removed when the container is deleted.
+	imagesMu sync.Mutex
+	images   map[string][]string
+
+	// This is the libfaketime shared library, loaded by Build.
+	faketimeLib []byte
 }
 
+// containerStopTimeout is the time (in seconds) that docker waits for a container
+// to exit after sending SIGTERM in StopContainer.
+const containerStopTimeout = 30
+
 func NewContainerBackend(c *docker.Client, cfg *Config) *ContainerBackend {
-	b := &ContainerBackend{client: c, config: cfg, logger: cfg.Logger}
+	b := &ContainerBackend{client: c, config: cfg, logger: cfg.Logger, images: make(map[string][]string)}
 	if b.logger == nil {
 		b.logger = slog.Default()
 	}
@@ -87,12 +102,33 @@ func (b *ContainerBackend) RunProgram(ctx context.Context, containerID string, c
 	}, nil
 }
 
+// CopyFromContainer downloads a tar archive of a file or directory in a container.
+func (b *ContainerBackend) CopyFromContainer(ctx context.Context, containerID, path string, w io.Writer) error {
+	b.logger.Debug("downloading from container", "container", containerID[:8], "path", path)
+	err := b.client.DownloadFromContainer(containerID, docker.DownloadFromContainerOptions{
+		Context:      ctx,
+		Path:         path,
+		OutputStream: w,
+	})
+	var dockerErr *docker.Error
+	if errors.As(err, &dockerErr) && dockerErr.Status == http.StatusNotFound {
+		return libhive.ErrFileNotFound
+	}
+	return err
+}
+
 // CreateContainer creates a docker container.
 func (b *ContainerBackend) CreateContainer(ctx context.Context, imageName string, opt libhive.ContainerOptions) (string, error) {
+	if opt.FakeTime != "" && b.faketimeLib == nil {
+		return "", errors.New("can't apply time offset: libfaketime is not available")
+	}
 	vars := []string{}
 	for key, val := range opt.Env {
 		vars = append(vars, key+"="+val)
 	}
+	if opt.FakeTime != "" {
+		vars = append(vars, faketimeEnv(opt.FakeTime)...)
+	}
 	createOpts := docker.CreateContainerOptions{
 		Context: ctx,
 		Name:    opt.Name,
@@ -103,6 +139,13 @@ func (b *ContainerBackend) CreateContainer(ctx context.Context, imageName string
 		},
 	}
 
+	if len(opt.Tmpfs) > 0 {
+		createOpts.HostConfig = &docker.HostConfig{Tmpfs: make(map[string]string)}
+		for _, dir := range opt.Tmpfs {
+			createOpts.HostConfig.Tmpfs[dir] = ""
+		}
+	}
+
 	if opt.Input != nil {
 		// Pre-announce that stdin will be attached. The stdin attachment
 		// will fail silently if this is not set.
@@ -123,11 +166,18 @@ func (b *ContainerBackend) CreateContainer(ctx context.Context, imageName string
 	logger := b.logger.With("image", imageName, "container", c.ID[:8])
 
 	// Now upload files.
-	if err := b.uploadFiles(ctx, c.ID, opt.Files); err != nil {
+	if err := b.uploadFiles(ctx, c.ID, opt.Files, opt.Directories); err != nil {
 		logger.Error("container file upload failed", "err", err)
 		b.DeleteContainer(c.ID)
 		return "", err
 	}
+	if opt.FakeTime != "" {
+		if err := b.uploadFaketime(ctx, c.ID); err != nil {
+			logger.Error("libfaketime upload failed", "err", err)
+			b.DeleteContainer(c.ID)
+			return "", err
+		}
+	}
 	logger.Debug("container created")
 	return c.ID, err
 }
@@ -215,10 +265,130 @@ func (b *ContainerBackend) DeleteContainer(containerID string) error {
 	err := b.client.RemoveContainer(docker.RemoveContainerOptions{ID: containerID, Force: true})
 	if err != nil {
 		b.logger.Error("can't remove container", "container", containerID[:8], "err", err)
+		return err
+	}
+	b.removeContainerImages(containerID)
+	return nil
+}
+
+// removeContainerImages deletes the images created for a recreated container.
+func (b *ContainerBackend) removeContainerImages(containerID string) {
+	b.imagesMu.Lock()
+	images := b.images[containerID]
+	delete(b.images, containerID)
+	b.imagesMu.Unlock()
+	b.removeImages(images)
+}
+
+// StopContainer stops the given container without removing it.
+func (b *ContainerBackend) StopContainer(containerID string, kill bool) error {
+	b.logger.Debug("stopping container", "container", containerID[:8], "kill", kill)
+	var err error
+	if kill {
+		err = b.client.KillContainer(docker.KillContainerOptions{ID: containerID, Signal: docker.SIGKILL})
+	} else {
+		err = b.client.StopContainer(containerID, containerStopTimeout)
+	}
+	var notRunning *docker.ContainerNotRunning
+	if errors.As(err, &notRunning) {
+		// Container has already exited.
+		return nil
+	}
+	if err != nil {
+		b.logger.Error("can't stop container", "container", containerID[:8], "err", err)
 	}
 	return err
 }
 
+// RecreateContainer creates a new container from the filesystem of a stopped container.
+// The old container is removed.
+func (b *ContainerBackend) RecreateContainer(ctx context.Context, containerID string, env map[string]string) (string, error) {
+	logger := b.logger.With("container", containerID[:8])
+	old, err := b.client.InspectContainerWithOptions(docker.InspectContainerOptions{Context: ctx, ID: containerID})
+	if err != nil {
+		return "", err
+	}
+	if old.State.Running {
+		return "", errors.New("container is still running")
+	}
+
+	// Snapshot the container filesystem. The image also carries over the
+	// container configuration, i.e. entry point, environment and labels.
+	image, err := b.client.CommitContainer(docker.CommitContainerOptions{
+		Context:   ctx,
+		Container: containerID,
+		Message:   "hive client restart",
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't commit container: %v", err)
+	}
+	b.imagesMu.Lock()
+	images := append(b.images[containerID], image.ID)
+	b.imagesMu.Unlock()
+
+	// Collect volumes, tmpfs mounts and networks of the old container.
+	hostConfig := &docker.HostConfig{Tmpfs: old.HostConfig.Tmpfs}
+	for _, m := range old.Mounts {
+		if m.Name != "" {
+			hostConfig.Binds = append(hostConfig.Binds, m.Name+":"+m.Destination)
+		}
+	}
+	var networks []string
+	for name, endpoint := range old.NetworkSettings.Networks {
+		if name != "bridge" {
+			networks = append(networks, endpoint.NetworkID)
+		}
+	}
+
+	// Remove the old container. This must happen before creating the new one
+	// because the new container gets the same name.
+	err = b.client.RemoveContainer(docker.RemoveContainerOptions{Context: ctx, ID: containerID, Force: true})
+	if err != nil {
+		b.removeImages(images)
+		return "", fmt.Errorf("can't remove container: %v", err)
+	}
+	b.imagesMu.Lock()
+	delete(b.images, containerID)
+	b.imagesMu.Unlock()
+
+	vars := []string{}
+	for key, val := range env {
+		vars = append(vars, key+"="+val)
+	}
+	c, err := b.client.CreateContainer(docker.CreateContainerOptions{
+		Context:    ctx,
+		Name:       strings.TrimPrefix(old.Name, "/"),
+		Config:     &docker.Config{Image: image.ID, Env: vars},
+		HostConfig: hostConfig,
+	})
+	if err != nil {
+		b.removeImages(images)
+		return "", fmt.Errorf("can't create container: %v", err)
+	}
+	b.imagesMu.Lock()
+	b.images[c.ID] = images
+	b.imagesMu.Unlock()
+
+	for _, network := range networks {
+		if err := b.ConnectContainer(c.ID, network); err != nil {
+			b.DeleteContainer(c.ID)
+			return "", fmt.Errorf("can't connect container to network %s: %v", network, err)
+		}
+	}
+	logger.Debug("container recreated", "new", c.ID[:8])
+	return c.ID, nil
+}
+
+// removeImages deletes images created by RecreateContainer. Images are removed
+// newest-first because each one is based on the previous one.
+func (b *ContainerBackend) removeImages(images []string) {
+	for i := len(images) - 1; i >= 0; i-- {
+		if err := b.client.RemoveImage(images[i]); err != nil {
+			b.logger.Error("can't remove image", "image", images[i], "err", err)
+		}
+	}
+}
+
 // PauseContainer pauses the given container.
 func (b *ContainerBackend) PauseContainer(containerID string) error {
 	b.logger.Debug("pausing container", "container", containerID[:8])
@@ -239,6 +409,33 @@ func (b *ContainerBackend) UnpauseContainer(containerID string) error {
 	return err
 }
 
+// signals contains the signals supported by SignalContainer.
+var signals = map[string]docker.Signal{
+	"SIGHUP":  docker.SIGHUP,
+	"SIGINT":  docker.SIGINT,
+	"SIGQUIT": docker.SIGQUIT,
+	"SIGKILL": docker.SIGKILL,
+	"SIGUSR1": docker.SIGUSR1,
+	"SIGUSR2": docker.SIGUSR2,
+	"SIGTERM": docker.SIGTERM,
+	"SIGCONT": docker.SIGCONT,
+	"SIGSTOP": docker.SIGSTOP,
+}
+
+// SignalContainer sends a signal to the main process of the given container.
+func (b *ContainerBackend) SignalContainer(containerID string, signal string) error {
+	sig, ok := signals[signal]
+	if !ok {
+		return fmt.Errorf("unsupported signal %q", signal)
+	}
+	b.logger.Debug("sending signal to container", "container", containerID[:8], "signal", signal)
+	err := b.client.KillContainer(docker.KillContainerOptions{ID: containerID, Signal: sig})
+	if err != nil {
+		b.logger.Error("can't signal container", "container", containerID[:8], "signal", signal, "err", err)
+	}
+	return err
+}
+
 // CreateNetwork creates a docker network.
 func (b *ContainerBackend) CreateNetwork(name string) (string, error) {
 	network, err := b.client.CreateNetwork(docker.CreateNetworkOptions{
@@ -311,9 +508,9 @@ func (b *ContainerBackend) DisconnectContainer(containerID, networkID string) er
 	})
 }
 
-// uploadFiles uploads the given files into a docker container.
-func (b *ContainerBackend) uploadFiles(ctx context.Context, id string, files map[string]*multipart.FileHeader) error {
-	if len(files) == 0 {
+// uploadFiles uploads the given files and directory archives into a docker container.
+func (b *ContainerBackend) uploadFiles(ctx context.Context, id string, files, dirs map[string]*multipart.FileHeader) error {
+	if len(files) == 0 && len(dirs) == 0 {
 		return nil
 	}
 
@@ -344,6 +541,17 @@ func (b *ContainerBackend) uploadFiles(ctx context.Context, id string, files map
 				return copyErr
 			}
 		}
+		for dir, fileHeader := range dirs {
+			file, err := fileHeader.Open()
+			if err != nil {
+				return err
+			}
+			copyErr := copyReadOnlyArchive(tw, dir, file)
+			file.Close()
+			if copyErr != nil {
+				return fmt.Errorf("invalid archive for %s: %v", dir, copyErr)
+			}
+		}
 		return tw.Close()
 	}()
 
@@ -363,6 +571,42 @@ func (b *ContainerBackend) uploadFiles(ctx context.Context, id string, files map
 	return err
 }
 
+// copyReadOnlyArchive copies the entries of the tar archive r into tw, placing them
+// below dir. The permissions of all entries are changed to read-only.
+func copyReadOnlyArchive(tw *tar.Writer, dir string, r io.Reader) error {
+	dir = path.Clean(dir)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Join(dir, header.Name)
+		if name != dir && !strings.HasPrefix(name, dir+"/") {
+			return fmt.Errorf("entry %q is outside of the directory", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			header.Mode = 0555
+		case tar.TypeReg:
+			header.Mode = 0444 | (header.Mode & 0111)
+		case tar.TypeSymlink:
+		default:
+			return fmt.Errorf("entry %q has unsupported type %c", header.Name, header.Typeflag)
+		}
+		header.Name = name
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
 // runContainer attaches to the output streams of an existing container, then
 // starts executing the container and returns the CloseWaiter to allow the caller
 // to wait for termination.
@@ -395,7 +639,11 @@ func (b *ContainerBackend) runContainer(ctx context.Context, logger *slog.Logger
 		if err := os.MkdirAll(filepath.Dir(opts.LogFile), 0755); err != nil {
 			return nil, err
 		}
-		log, err := os.OpenFile(opts.LogFile, os.O_WRONLY|os.O_CREATE|os.O_SYNC|os.O_TRUNC, 0644)
+		flags := os.O_WRONLY | os.O_CREATE | os.O_SYNC | os.O_TRUNC
+		if opts.AppendLog {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_SYNC | os.O_APPEND
+		}
+		log, err := os.OpenFile(opts.LogFile, flags, 0644)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,73 @@
+package libdocker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"io/fs"
+
+	"github.com/ethereum/hive/internal/libhive"
+	docker "github.com/fsouza/go-dockerclient"
+)
+
+const (
+	faketimeTag = "hive/faketime"
+
+	// faketimeLibPath is the location of libfaketime in client containers.
+	faketimeLibPath = "/hive-faketime/libfaketime.so.1"
+)
+
+//go:embed faketime/Dockerfile
+var faketimeSource embed.FS
+
+// buildFaketime builds the libfaketime helper image and loads the library from it.
+// Failure to build the image is not fatal, it just disables the time offset feature.
+func (cb *ContainerBackend) buildFaketime(ctx context.Context, b libhive.Builder) {
+	fsys, _ := fs.Sub(faketimeSource, "faketime")
+	if err := b.BuildImage(ctx, faketimeTag, fsys); err != nil {
+		cb.logger.Warn("libfaketime image build failed, client time offsets are unavailable", "err", err)
+		return
+	}
+	lib, err := b.ReadFile(ctx, faketimeTag, "/libfaketime.so.1")
+	if err != nil {
+		cb.logger.Warn("can't load libfaketime, client time offsets are unavailable", "err", err)
+		return
+	}
+	cb.faketimeLib = lib
+}
+
+// faketimeEnv returns the environment variables which enable libfaketime.
+func faketimeEnv(offset string) []string {
+	return []string{
+		"LD_PRELOAD=" + faketimeLibPath,
+		"FAKETIME=" + offset,
+		// Keep the offset stable when the client spawns child processes.
+		"FAKETIME_DONT_RESET=1",
+	}
+}
+
+// uploadFaketime places libfaketime into the given container.
+func (cb *ContainerBackend) uploadFaketime(ctx context.Context, id string) error {
+	if cb.faketimeLib == nil {
+		return errors.New("libfaketime is not available")
+	}
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	header := &tar.Header{Name: faketimeLibPath, Mode: 0755, Size: int64(len(cb.faketimeLib))}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tw.Write(cb.faketimeLib); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return cb.client.UploadToContainer(id, docker.UploadToContainerOptions{
+		Context:     ctx,
+		InputStream: &archive,
+		Path:        "/",
+	})
+}
@@ -14,9 +14,13 @@ import (
 
 const hiveproxyTag = "hive/hiveproxy"
 
-// Build builds the hiveproxy image.
+// Build builds the hiveproxy and libfaketime images.
 func (cb *ContainerBackend) Build(ctx context.Context, b libhive.Builder) error {
-	return b.BuildImage(ctx, hiveproxyTag, hiveproxy.Source)
+	if err := b.BuildImage(ctx, hiveproxyTag, hiveproxy.Source); err != nil {
+		return err
+	}
+	cb.buildFaketime(ctx, b)
+	return nil
 }
 
 // ServeAPI starts the API server.
@@ -1,6 +1,7 @@
 package libhive
 
 import (
+	"archive/tar"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,9 +12,12 @@ import (
 	"net/http"
 	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/ethereum/hive/internal/simapi"
 	"github.com/gorilla/mux"
@@ -36,10 +40,13 @@ func newSimulationAPI(b ContainerBackend, env SimEnv, tm *TestManager, hive Hive
 	router.HandleFunc("/clients", api.getClientTypes).Methods("GET")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/exec", api.execInClient).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}", api.getNodeStatus).Methods("GET")
+	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/file", api.getClientFile).Methods("GET")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node", api.startClient).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}", api.stopClient).Methods("DELETE")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/pause", api.pauseClient).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/pause", api.unpauseClient).Methods("DELETE")
+	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/restart", api.restartClient).Methods("POST")
+	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/signal", api.signalClient).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}/test", api.startTest).Methods("POST")
 	// post because the delete http verb does not always support a message body
 	router.HandleFunc("/testsuite/{suite}/test/{test}", api.endTest).Methods("POST")
@@ -211,14 +218,40 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the container paths of directories and tmpfs mounts.
+	if err := checkClientMounts(&clientConfig); err != nil {
+		slog.Error("API: "+err.Error(), "client", clientDef.Name)
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	if clientConfig.TimeOffset != "" && !timeOffsetPattern.MatchString(clientConfig.TimeOffset) {
+		err := fmt.Errorf("invalid time offset '%s' in client start request", clientConfig.TimeOffset)
+		slog.Error("API: "+err.Error(), "client", clientDef.Name)
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+
 	files := make(map[string]*multipart.FileHeader)
+	dirs := make(map[string]*multipart.FileHeader)
 	for key, fheaders := range r.MultipartForm.File {
 		if len(fheaders) > 0 {
 			// Note: the PARAMETER NAME (not the 'filename') is used as the destination
 			// file path in the container. This is because RFC 7578 says that directory
 			// components should be ignored in the filename supplied by the form, and
 			// package multipart strips the directory info away at parse time.
-			files[key] = fheaders[0]
+			if slices.Contains(clientConfig.Directories, key) {
+				dirs[key] = fheaders[0]
+			} else {
+				files[key] = fheaders[0]
+			}
+		}
+	}
+	for _, dir := range clientConfig.Directories {
+		if dirs[dir] == nil {
+			err := fmt.Errorf("missing archive for directory %s in node request", dir)
+			slog.Error("API: "+err.Error(), "client", clientDef.Name)
+			serveError(w, err, http.StatusBadRequest)
+			return
 		}
 	}
 
@@ -237,6 +270,12 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	if env["HIVE_LOGLEVEL"] == "" {
 		env["HIVE_LOGLEVEL"] = strconv.Itoa(api.env.SimLogLevel)
 	}
+	// Pass extra client arguments to the start script.
+	if err := setExtraArgs(env, clientConfig.Args); err != nil {
+		slog.Error("API: "+err.Error(), "client", clientDef.Name)
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
 
 	// Set up the timeout.
 	timeout := api.env.ClientStartTimeout
@@ -258,7 +297,15 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	containerName := GenerateClientContainerName(clientDef.Name, suiteID, testID)
 
 	// Create the client container.
-	options := ContainerOptions{Env: env, Files: files, Labels: labels, Name: containerName}
+	options := ContainerOptions{
+		Env:         env,
+		Files:       files,
+		Directories: dirs,
+		Tmpfs:       clientConfig.Tmpfs,
+		FakeTime:    clientConfig.TimeOffset,
+		Labels:      labels,
+		Name:        containerName,
+	}
 	containerID, err := api.backend.CreateContainer(ctx, clientDef.Image, options)
 	if err != nil {
 		slog.Error("API: client container create failed", "client", clientDef.Name, "error", err)
@@ -282,15 +329,11 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// by default: check the eth1 port
-	options.CheckLive = 8545
-	if portStr := env["HIVE_CHECK_LIVE_PORT"]; portStr != "" {
-		v, err := strconv.ParseUint(portStr, 10, 16)
-		if err != nil {
-			slog.Error("API: could not parse check-live port", "error", err)
-			serveError(w, err, http.StatusBadRequest)
-			return
-		}
-		options.CheckLive = uint16(v)
+	options.CheckLive, err = checkLivePort(env, 8545)
+	if err != nil {
+		slog.Error("API: could not parse check-live port", "error", err)
+		serveError(w, err, http.StatusBadRequest)
+		return
 	}
 
 	// Start it!
@@ -302,6 +345,8 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 			Name:           clientDef.Name,
 			InstantiatedAt: time.Now(),
 			LogFile:        logPath,
+			logFilePath:    logFilePath,
+			checkLive:      options.CheckLive,
 			wait:           info.Wait,
 		}
 
@@ -361,6 +406,59 @@ func (api *simAPI) checkClientNetworks(req *simapi.NodeConfig, suiteID TestSuite
 	return req.Networks, nil
 }
 
+// timeOffsetPattern matches relative libfaketime offsets.
+var timeOffsetPattern = regexp.MustCompile(`^[+-][0-9]+(\.[0-9]+)?[smhdy]?$`)
+
+// checkLivePort returns the port configured by HIVE_CHECK_LIVE_PORT, or def if the
+// variable is not set.
+func checkLivePort(env map[string]string, def uint16) (uint16, error) {
+	portStr := env["HIVE_CHECK_LIVE_PORT"]
+	if portStr == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(v), nil
+}
+
+// checkClientMounts validates the directory and tmpfs paths of a client start request.
+func checkClientMounts(req *simapi.NodeConfig) error {
+	for _, dir := range req.Directories {
+		if !path.IsAbs(dir) || path.Clean(dir) == "/" {
+			return fmt.Errorf("invalid directory path '%s' in client start request", dir)
+		}
+	}
+	for _, dir := range req.Tmpfs {
+		if !path.IsAbs(dir) || path.Clean(dir) == "/" {
+			return fmt.Errorf("invalid tmpfs path '%s' in client start request", dir)
+		}
+	}
+	return nil
+}
+
+// setExtraArgs adds client command-line arguments to the HIVE_EXTRA_ARGS
+// environment variable. Client start scripts append the content of this
+// variable to the client command line, relying on word splitting, so the
+// arguments cannot contain whitespace.
+func setExtraArgs(env map[string]string, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsFunc(arg, unicode.IsSpace) {
+			return fmt.Errorf("invalid client argument %q in client start request", arg)
+		}
+	}
+	extra := strings.Join(args, " ")
+	if prev := env["HIVE_EXTRA_ARGS"]; prev != "" {
+		extra = prev + " " + extra
+	}
+	env["HIVE_EXTRA_ARGS"] = extra
+	return nil
+}
+
 // stopClient terminates a client container.
 func (api *simAPI) stopClient(w http.ResponseWriter, r *http.Request) {
 	_, testID, err := api.requestSuiteAndTest(r)
@@ -381,6 +479,80 @@ func (api *simAPI) stopClient(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// restartClient stops a client container and starts it again.
+func (api *simAPI) restartClient(w http.ResponseWriter, r *http.Request) {
+	suiteID, testID, err := api.requestSuiteAndTest(r)
+	if err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	node := mux.Vars(r)["node"]
+
+	var req simapi.RestartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		serveError(w, fmt.Errorf("invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	env := req.Environment
+	for k := range env {
+		if !strings.HasPrefix(k, hiveEnvvarPrefix) {
+			delete(env, k)
+		}
+	}
+
+	timeout := api.env.ClientStartTimeout
+	if timeout == 0 {
+		timeout = defaultStartTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	info, err := api.tm.RestartNode(ctx, testID, node, RestartOptions{Kill: req.Kill, Env: env})
+	switch {
+	case err == ErrNoSuchNode:
+		serveError(w, err, http.StatusNotFound)
+	case err == ErrNodeNotRunning:
+		serveError(w, err, http.StatusBadRequest)
+	case err != nil:
+		slog.Error("API: could not restart client", "suite", suiteID, "test", testID, "node", node, "error", err)
+		serveError(w, err, http.StatusInternalServerError)
+	default:
+		slog.Info("API: client "+info.Name+" restarted", "suite", suiteID, "test", testID, "container", info.ID)
+		serveJSON(w, &simapi.StartNodeResponse{ID: info.ID, IP: info.IP})
+	}
+}
+
+// signalClient sends a signal to a client container.
+func (api *simAPI) signalClient(w http.ResponseWriter, r *http.Request) {
+	_, testID, err := api.requestSuiteAndTest(r)
+	if err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	node := mux.Vars(r)["node"]
+
+	var req simapi.SignalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		serveError(w, fmt.Errorf("invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Signal == "" {
+		serveError(w, errors.New("missing signal"), http.StatusBadRequest)
+		return
+	}
+
+	err = api.tm.SignalNode(testID, node, req.Signal)
+	switch {
+	case err == ErrNoSuchNode:
+		serveError(w, err, http.StatusNotFound)
+	case err != nil:
+		serveError(w, err, http.StatusInternalServerError)
+	default:
+		slog.Info("API: signal sent to client", "node", node, "signal", req.Signal)
+		serveOK(w)
+	}
+}
+
 // pauseClient pauses a client container.
 func (api *simAPI) pauseClient(w http.ResponseWriter, r *http.Request) {
 	_, testID, err := api.requestSuiteAndTest(r)
@@ -471,6 +643,74 @@ func (api *simAPI) execInClient(w http.ResponseWriter, r *http.Request) {
 	serveJSON(w, &info)
 }
 
+// getClientFile copies a file or directory out of a client container. Regular files
+// are served as-is. Directories (and other file types) are served as a tar archive.
+func (api *simAPI) getClientFile(w http.ResponseWriter, r *http.Request) {
+	suiteID, testID, err := api.requestSuiteAndTest(r)
+	if err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	node := mux.Vars(r)["node"]
+	nodeInfo, err := api.tm.GetNodeInfo(suiteID, testID, node)
+	if err != nil {
+		slog.Error("API: can't find node", "node", node, "error", err)
+		serveError(w, err, http.StatusNotFound)
+		return
+	}
+	file := r.URL.Query().Get("path")
+	if !path.IsAbs(file) {
+		serveError(w, fmt.Errorf("invalid path %q: must be absolute", file), http.StatusBadRequest)
+		return
+	}
+
+	// Stream the archive from the backend. The first header is read before
+	// responding, so a missing file can be reported with the right status.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(api.backend.CopyFromContainer(ctx, nodeInfo.ID, file, pw))
+	}()
+	tr := tar.NewReader(pr)
+	hdr, err := tr.Next()
+	switch {
+	case errors.Is(err, ErrFileNotFound):
+		serveError(w, err, http.StatusNotFound)
+		return
+	case err != nil:
+		slog.Error("API: can't copy from client", "node", node, "path", file, "error", err)
+		serveError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	if hdr.Typeflag == tar.TypeReg {
+		w.Header().Set("content-type", "application/octet-stream")
+		w.Header().Set("content-length", strconv.FormatInt(hdr.Size, 10))
+		w.WriteHeader(http.StatusOK)
+		io.Copy(w, tr)
+		return
+	}
+	w.Header().Set("content-type", "application/x-tar")
+	w.WriteHeader(http.StatusOK)
+	tw := tar.NewWriter(w)
+	for ; err == nil; hdr, err = tr.Next() {
+		if err = tw.WriteHeader(hdr); err != nil {
+			break
+		}
+		if _, err = io.Copy(tw, tr); err != nil {
+			break
+		}
+	}
+	if err != io.EOF {
+		slog.Error("API: client file copy failed", "node", node, "path", file, "error", err)
+		return
+	}
+	tw.Close()
+}
+
 // parseExecRequest decodes and validates a client script exec request.
 func parseExecRequest(r io.Reader) ([]string, error) {
 	var request simapi.ExecRequest
@@ -172,7 +172,14 @@ type ClientInfo struct {
 	InstantiatedAt time.Time `json:"instantiatedAt"`
 	LogFile        string    `json:"logFile"` //Absolute path to the logfile.
 
-	wait func()
+	// LogTail contains the offsets of the last lines of the client log file,
+	// as of the end of the test. This is used to show an excerpt of the log
+	// alongside the test result.
+	LogTail *TestLogOffsets `json:"logTail,omitempty"`
+
+	logFilePath string // platform path of the log file
+	checkLive   uint16 // port checked when (re)starting
+	wait        func()
 }
 
 // HiveInstance contains information about hive itself.
@@ -32,6 +32,24 @@ type ContainerBackend interface {
 	PauseContainer(containerID string) error
 	UnpauseContainer(containerID string) error
 
+	// SignalContainer sends a signal to the main process of a container.
+	// The signal is given by name, e.g. "SIGHUP".
+	SignalContainer(containerID string, signal string) error
+
+	// StopContainer stops a container without removing it. If kill is true, the
+	// container is terminated using SIGKILL. Otherwise, it receives SIGTERM and is
+	// killed after a timeout.
+	StopContainer(containerID string, kill bool) error
+
+	// RecreateContainer replaces a stopped container with a new container that has
+	// the same filesystem content, volumes and networks. The given variables are added
+	// to the environment of the new container. It returns the new container ID.
+	RecreateContainer(ctx context.Context, containerID string, env map[string]string) (string, error)
+
+	// CopyFromContainer writes a tar archive of the given file or directory in the
+	// container to w. It returns ErrFileNotFound if the path does not exist.
+	CopyFromContainer(ctx context.Context, containerID, path string, w io.Writer) error
+
 	// RunProgram runs a command in the given container and returns its outputs and exit code.
 	RunProgram(ctx context.Context, containerID string, cmdline []string) (*ExecInfo, error)
 
@@ -53,11 +71,25 @@ type APIServer interface {
 // This error is returned by NetworkNameToID if a docker network is not present.
 var ErrNetworkNotFound = fmt.Errorf("network not found")
 
+// This error is returned by CopyFromContainer if the requested path does not exist.
+var ErrFileNotFound = fmt.Errorf("file not found")
+
 // ContainerOptions contains the launch parameters for docker containers.
 type ContainerOptions struct {
 	Env   map[string]string
 	Files map[string]*multipart.FileHeader
 
+	// Directories are tar archives which are extracted into the container
+	// at the given path. All extracted files are made read-only.
+	Directories map[string]*multipart.FileHeader
+
+	// Tmpfs lists the container directories that are backed by tmpfs.
+	Tmpfs []string
+
+	// FakeTime shifts the clock of the container using libfaketime. The value is
+	// a relative offset in FAKETIME syntax, e.g. "+3600" or "-2d".
+	FakeTime string
+
 	// This requests checking for the given TCP port to be opened by the container.
 	CheckLive uint16
 
@@ -67,6 +99,10 @@ type ContainerOptions struct {
 	LogFile string
 	Output  io.WriteCloser
 
+	// AppendLog makes the container output append to LogFile instead of truncating it.
+	// This is used when restarting containers.
+	AppendLog bool
+
 	// Input: if set, container stdin draws from the given reader.
 	Input io.ReadCloser
 
@@ -1,6 +1,7 @@
 package libhive
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
@@ -24,6 +25,7 @@ var (
 	ErrNoSummaryResult          = errors.New("test case must be ended with a summary result")
 	ErrDBUpdateFailed           = errors.New("could not update results set")
 	ErrTestSuiteLimited         = errors.New("testsuite test count is limited")
+	ErrNodeNotRunning           = errors.New("node is not running")
 )
 
 // SimEnv contains the simulation parameters.
@@ -48,6 +50,10 @@ type SimEnv struct {
 	// This configures the amount of time the simulation waits
 	// for the client to open port 8545 after launching the container.
 	ClientStartTimeout time.Duration
+
+	// This is the number of lines from the end of each client log that are
+	// referenced in the test result. Zero disables log excerpts.
+	ClientLogTailLines int
 }
 
 // SimResult summarizes the results of a simulation run.
@@ -542,6 +548,22 @@ func (manager *TestManager) EndTest(suiteID TestSuiteID, testID TestID, result *
 		}
 	}
 
+	// Record the client log excerpts. This must happen after stopping the
+	// clients to ensure all output has been written.
+	if manager.config.ClientLogTailLines > 0 {
+		for _, v := range testCase.ClientInfo {
+			if v.logFilePath == "" {
+				continue
+			}
+			offsets, err := logTailOffsets(v.logFilePath, manager.config.ClientLogTailLines)
+			if err != nil {
+				slog.Error("could not read client log tail", "client", v.Name, "container", v.ID, "err", err)
+				continue
+			}
+			v.LogTail = offsets
+		}
+	}
+
 	// Delete from running, if it's still there.
 	delete(manager.runningTestCases, testID)
 	return nil
@@ -571,6 +593,48 @@ func (manager *TestManager) writeTestDetails(suite *TestSuite, testCase *TestCas
 	return &offsets
 }
 
+// logTailOffsets returns the offsets of the last n lines of the given file.
+// A trailing newline at the end of the file does not count as a line.
+func logTailOffsets(file string, n int) (*TestLogOffsets, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 64 * 1024
+	var (
+		size  = stat.Size()
+		begin = size
+		lines = 0
+		buf   = make([]byte, chunkSize)
+	)
+	for pos := size; pos > 0 && lines <= n; {
+		start := max(pos-chunkSize, 0)
+		chunk := buf[:pos-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return nil, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' || start+int64(i) == size-1 {
+				continue
+			}
+			lines++
+			if lines == n {
+				begin = start + int64(i) + 1
+				return &TestLogOffsets{Begin: begin, End: size}, nil
+			}
+		}
+		pos = start
+	}
+	// The file has fewer than n lines.
+	return &TestLogOffsets{Begin: 0, End: size}, nil
+}
+
 // RegisterNode is used by test suite hosts to register the creation of a node in the context of a test
 func (manager *TestManager) RegisterNode(testID TestID, nodeID string, nodeInfo *ClientInfo) error {
 	manager.testCaseMutex.Lock()
@@ -612,6 +676,118 @@ func (manager *TestManager) StopNode(testID TestID, nodeID string) error {
 	return nil
 }
 
+// RestartOptions configures a client restart.
+type RestartOptions struct {
+	Kill bool              // terminate using SIGKILL instead of SIGTERM
+	Env  map[string]string // environment variable overrides
+}
+
+// RestartNode stops a client container and starts it again. The data of the client is
+// preserved. If environment overrides are given, the container is replaced by a new
+// container with the same filesystem content. The returned ClientInfo has the ID and
+// IP of the restarted container.
+func (manager *TestManager) RestartNode(ctx context.Context, testID TestID, nodeID string, opts RestartOptions) (*ClientInfo, error) {
+	manager.testCaseMutex.Lock()
+	testCase, ok := manager.runningTestCases[testID]
+	if !ok {
+		manager.testCaseMutex.Unlock()
+		return nil, ErrNoSuchNode
+	}
+	nodeInfo, ok := testCase.ClientInfo[nodeID]
+	if !ok {
+		manager.testCaseMutex.Unlock()
+		return nil, ErrNoSuchNode
+	}
+	if nodeInfo.wait == nil {
+		manager.testCaseMutex.Unlock()
+		return nil, ErrNodeNotRunning
+	}
+	// Take over the wait function while restarting. The lock is not held during the
+	// restart because waiting for the client to come up can take a long time.
+	wait := nodeInfo.wait
+	nodeInfo.wait = nil
+	manager.testCaseMutex.Unlock()
+
+	info, err := manager.restartContainer(ctx, nodeInfo, wait, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	manager.testCaseMutex.Lock()
+	defer manager.testCaseMutex.Unlock()
+	if _, ok := manager.runningTestCases[testID]; !ok {
+		// The test has ended while restarting.
+		manager.backend.DeleteContainer(info.ID)
+		info.Wait()
+		return nil, ErrNoSuchTestCase
+	}
+	if info.ID != nodeID {
+		delete(testCase.ClientInfo, nodeID)
+		testCase.ClientInfo[info.ID] = nodeInfo
+	}
+	nodeInfo.ID = info.ID
+	nodeInfo.IP = info.IP
+	nodeInfo.wait = info.Wait
+	return nodeInfo, nil
+}
+
+func (manager *TestManager) restartContainer(ctx context.Context, nodeInfo *ClientInfo, wait func(), opts RestartOptions) (*ContainerInfo, error) {
+	// Stop the container and wait for the log file to be closed.
+	if err := manager.backend.StopContainer(nodeInfo.ID, opts.Kill); err != nil {
+		manager.backend.DeleteContainer(nodeInfo.ID)
+		wait()
+		return nil, fmt.Errorf("unable to stop client: %v", err)
+	}
+	wait()
+
+	containerID := nodeInfo.ID
+	options := ContainerOptions{
+		LogFile:   nodeInfo.logFilePath,
+		AppendLog: true,
+		CheckLive: nodeInfo.checkLive,
+	}
+	if len(opts.Env) > 0 {
+		port, err := checkLivePort(opts.Env, nodeInfo.checkLive)
+		if err != nil {
+			manager.backend.DeleteContainer(containerID)
+			return nil, err
+		}
+		options.CheckLive = port
+		containerID, err = manager.backend.RecreateContainer(ctx, containerID, opts.Env)
+		if err != nil {
+			manager.backend.DeleteContainer(nodeInfo.ID)
+			return nil, fmt.Errorf("unable to recreate client: %v", err)
+		}
+	}
+	info, err := manager.backend.StartContainer(ctx, containerID, options)
+	if err != nil {
+		if info != nil && info.Wait != nil {
+			info.Wait()
+		}
+		return nil, fmt.Errorf("client did not restart: %v", err)
+	}
+	return info, nil
+}
+
+// SignalNode sends a signal to a client container.
+func (manager *TestManager) SignalNode(testID TestID, nodeID string, signal string) error {
+	manager.testCaseMutex.Lock()
+	defer manager.testCaseMutex.Unlock()
+
+	testCase, ok := manager.runningTestCases[testID]
+	if !ok {
+		return ErrNoSuchNode
+	}
+	nodeInfo, ok := testCase.ClientInfo[nodeID]
+	if !ok {
+		return ErrNoSuchNode
+	}
+	if err := manager.backend.SignalContainer(nodeInfo.ID, signal); err != nil {
+		return fmt.Errorf("unable to signal client: %v", err)
+	}
+	return nil
+}
+
 // PauseNode pauses a client container.
 func (manager *TestManager) PauseNode(testID TestID, nodeID string) error {
 	manager.testCaseMutex.Lock()
@@ -0,0 +1,47 @@
+package libhive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogTailOffsets(t *testing.T) {
+	tests := []struct {
+		content string
+		n       int
+		want    string
+	}{
+		{content: "", n: 3, want: ""},
+		{content: "a\nb\n", n: 3, want: "a\nb\n"},
+		{content: "a\nb\nc\nd\n", n: 2, want: "c\nd\n"},
+		{content: "a\nb\nc\nd", n: 2, want: "c\nd"},
+		{content: "a\nb\nc\nd\n", n: 4, want: "a\nb\nc\nd\n"},
+		{content: strings.Repeat("line\n", 100000), n: 1, want: "line\n"},
+		{content: strings.Repeat("x", 70000) + "\nlast\n", n: 2, want: strings.Repeat("x", 70000) + "\nlast\n"},
+	}
+
+	dir := t.TempDir()
+	for i, test := range tests {
+		file := filepath.Join(dir, "client.log")
+		if err := os.WriteFile(file, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		offsets, err := logTailOffsets(file, test.n)
+		if err != nil {
+			t.Fatalf("test %d: error: %v", i, err)
+		}
+		got := test.content[offsets.Begin:offsets.End]
+		if got != test.want {
+			t.Errorf("test %d: wrong tail %q, want %q", i, truncate(got), truncate(test.want))
+		}
+	}
+}
+
+func truncate(s string) string {
+	if len(s) > 20 {
+		return s[:20] + "..."
+	}
+	return s
+}
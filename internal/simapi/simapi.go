@@ -2,11 +2,12 @@
 package simapi
 
 type TestRequest struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"display_name"`
-	Location    string `json:"location"`
-	Category    string `json:"category"`
-	Description string `json:"description"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name"`
+	Location    string   `json:"location"`
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 // NodeConfig contains the launch parameters for a client container.
@@ -14,6 +15,21 @@ type NodeConfig struct {
 	Client      string            `json:"client"`
 	Networks    []string          `json:"networks"`
 	Environment map[string]string `json:"environment"`
+
+	// Args are extra command-line arguments for the client. They are passed to
+	// the client start script in the HIVE_EXTRA_ARGS environment variable.
+	Args []string `json:"args,omitempty"`
+
+	// Directories lists the destination paths of read-only directories. The content
+	// of each directory is uploaded as a tar archive in the file part of the same name.
+	Directories []string `json:"directories,omitempty"`
+
+	// Tmpfs lists directories in the container that should be backed by tmpfs.
+	Tmpfs []string `json:"tmpfs,omitempty"`
+
+	// TimeOffset shifts the clock of the client using libfaketime.
+	// The value is a relative offset in FAKETIME syntax, e.g. "+3600" or "-2d".
+	TimeOffset string `json:"timeOffset,omitempty"`
 }
 
 // StartNodeResponse is returned by the client startup endpoint.
@@ -28,6 +44,17 @@ type NodeResponse struct {
 	Name string `json:"name"`
 }
 
+// RestartRequest contains the parameters of a client restart.
+type RestartRequest struct {
+	Kill        bool              `json:"kill"`
+	Environment map[string]string `json:"environment,omitempty"`
+}
+
+// SignalRequest contains the signal to be sent to a client.
+type SignalRequest struct {
+	Signal string `json:"signal"` // signal name, e.g. "SIGHUP"
+}
+
 type ExecRequest struct {
 	Command []string `json:"command"`
 }
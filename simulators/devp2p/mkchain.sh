@@ -0,0 +1,7 @@
+#!/bin/sh
+
+wd="$(pwd)"
+mkdir -p "$wd/chain"
+cd ../..
+go build ./cmd/hivechain
+./hivechain generate -spec "$wd/chain.yaml" -outdir "$wd/chain"
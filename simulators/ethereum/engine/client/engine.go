@@ -38,16 +38,22 @@ type Engine interface {
 	GetPayloadV1(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, error)
 	GetPayloadV2(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, error)
 	GetPayloadV3(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error)
+	GetPayloadV4(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error)
+	GetPayloadV5(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error)
 	GetPayload(ctx context.Context, version int, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error)
 
 	NewPayload(ctx context.Context, version int, payload *typ.ExecutableData) (api.PayloadStatusV1, error)
 	NewPayloadV1(ctx context.Context, payload *typ.ExecutableData) (api.PayloadStatusV1, error)
 	NewPayloadV2(ctx context.Context, payload *typ.ExecutableData) (api.PayloadStatusV1, error)
 	NewPayloadV3(ctx context.Context, payload *typ.ExecutableData) (api.PayloadStatusV1, error)
+	NewPayloadV4(ctx context.Context, payload *typ.ExecutableData) (api.PayloadStatusV1, error)
 
 	GetPayloadBodiesByRangeV1(ctx context.Context, start uint64, count uint64) ([]*typ.ExecutionPayloadBodyV1, error)
 	GetPayloadBodiesByHashV1(ctx context.Context, hashes []common.Hash) ([]*typ.ExecutionPayloadBodyV1, error)
 
+	GetBlobsV1(ctx context.Context, versionedHashes []common.Hash) ([]*typ.BlobAndProofV1, error)
+	GetBlobsV2(ctx context.Context, versionedHashes []common.Hash) ([]*typ.BlobAndProofV2, error)
+
 	LatestForkchoiceSent() (fcState *api.ForkchoiceStateV1, pAttributes *typ.PayloadAttributes)
 	LatestNewPayloadSent() (payload *typ.ExecutableData)
 
@@ -90,5 +96,5 @@ var (
 	Finalized                      = big.NewInt(-3)
 	Safe                           = big.NewInt(-4)
 	LatestForkchoiceUpdatedVersion = 3
-	LatestNewPayloadVersion        = 3
+	LatestNewPayloadVersion        = 4
 )
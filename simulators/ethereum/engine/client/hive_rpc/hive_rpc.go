@@ -97,7 +97,7 @@ func (s HiveRPCEngineStarter) StartClient(T *hivesim.T, testContext context.Cont
 	ec := NewHiveRPCEngineClient(c, enginePort, ethPort, jwtSecret, &helper.LoggingRoundTrip{
 		Logger:   T,
 		ID:       c.Container,
-		Inner:    http.DefaultTransport,
+		Inner:    SchemaCheckTransport(T, c.Container, http.DefaultTransport),
 		LogLevel: hiveLogLevel,
 	})
 	return ec, nil
@@ -339,6 +339,9 @@ func (ec *HiveRPCEngineClient) GetPayload(ctx context.Context, version int, payl
 		blockValue = response.BlockValue
 		blobsBundle = response.BlobsBundle
 		shouldOverrideBuilder = response.ShouldOverrideBuilder
+		if version >= 4 {
+			executableData.ExecutionRequests = response.ExecutionRequests
+		}
 	} else {
 		err = ec.c.CallContext(ctx, &executableData, rpcString, payloadId)
 	}
@@ -360,6 +363,14 @@ func (ec *HiveRPCEngineClient) GetPayloadV3(ctx context.Context, payloadId *api.
 	return ec.GetPayload(ctx, 3, payloadId)
 }
 
+func (ec *HiveRPCEngineClient) GetPayloadV4(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
+	return ec.GetPayload(ctx, 4, payloadId)
+}
+
+func (ec *HiveRPCEngineClient) GetPayloadV5(ctx context.Context, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
+	return ec.GetPayload(ctx, 5, payloadId)
+}
+
 // Get Payload Bodies API Calls
 func (ec *HiveRPCEngineClient) GetPayloadBodiesByRangeV1(ctx context.Context, start uint64, count uint64) ([]*typ.ExecutionPayloadBodyV1, error) {
 	ec.PrepareDefaultAuthCallToken()
@@ -386,11 +397,37 @@ func (ec *HiveRPCEngineClient) GetBlobsBundleV1(ctx context.Context, payloadId *
 	return &result, err
 }
 
+// Get Blobs API Calls
+func (ec *HiveRPCEngineClient) GetBlobsV1(ctx context.Context, versionedHashes []common.Hash) ([]*typ.BlobAndProofV1, error) {
+	ec.PrepareDefaultAuthCallToken()
+
+	var result []*typ.BlobAndProofV1
+	err := ec.c.CallContext(ctx, &result, "engine_getBlobsV1", versionedHashes)
+	return result, err
+}
+
+func (ec *HiveRPCEngineClient) GetBlobsV2(ctx context.Context, versionedHashes []common.Hash) ([]*typ.BlobAndProofV2, error) {
+	ec.PrepareDefaultAuthCallToken()
+
+	var result []*typ.BlobAndProofV2
+	err := ec.c.CallContext(ctx, &result, "engine_getBlobsV2", versionedHashes)
+	return result, err
+}
+
 // New Payload API Call Methods
 func (ec *HiveRPCEngineClient) NewPayload(ctx context.Context, version int, payload *typ.ExecutableData) (result api.PayloadStatusV1, err error) {
 	ec.PrepareDefaultAuthCallToken()
 
-	if version >= 3 {
+	if version >= 4 {
+		var requests []hexutil.Bytes
+		if payload.ExecutionRequests != nil {
+			requests = make([]hexutil.Bytes, len(payload.ExecutionRequests))
+			for i, r := range payload.ExecutionRequests {
+				requests[i] = r
+			}
+		}
+		err = ec.c.CallContext(ctx, &result, fmt.Sprintf("engine_newPayloadV%d", version), payload, payload.VersionedHashes, payload.ParentBeaconBlockRoot, requests)
+	} else if version >= 3 {
 		err = ec.c.CallContext(ctx, &result, fmt.Sprintf("engine_newPayloadV%d", version), payload, payload.VersionedHashes, payload.ParentBeaconBlockRoot)
 	} else {
 		err = ec.c.CallContext(ctx, &result, fmt.Sprintf("engine_newPayloadV%d", version), payload)
@@ -414,6 +451,11 @@ func (ec *HiveRPCEngineClient) NewPayloadV3(ctx context.Context, payload *typ.Ex
 	return ec.NewPayload(ctx, 3, payload)
 }
 
+func (ec *HiveRPCEngineClient) NewPayloadV4(ctx context.Context, payload *typ.ExecutableData) (api.PayloadStatusV1, error) {
+	ec.latestPayloadSent = payload
+	return ec.NewPayload(ctx, 4, payload)
+}
+
 // Exchange Transition Configuration API Call Methods
 
 func (ec *HiveRPCEngineClient) ExchangeTransitionConfigurationV1(ctx context.Context, tConf *api.TransitionConfigurationV1) (api.TransitionConfigurationV1, error) {
@@ -0,0 +1,531 @@
+package hive_rpc
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OpenRPC document of the ethereum/execution-apis specification, assembled from the
+// sources of release v1.0.0-beta.7 (commit 5aebdfdd45cadeb723be4bd45b4611b71c8b1c85).
+//
+//go:generate go run ./specgen -version v1.0.0-beta.7 -out schema/openrpc.json
+//go:embed schema/openrpc.json
+var openRPCSpec []byte
+
+// Environment variable that enables the schema conformance checks of the
+// JSON-RPC responses:
+// - "warn": violations are logged as warnings
+// - "fail": violations fail the test
+const SchemaCheckEnvVar = "HIVE_ENGINE_SCHEMA_CHECK"
+
+const (
+	SchemaCheckOff  = "off"
+	SchemaCheckWarn = "warn"
+	SchemaCheckFail = "fail"
+)
+
+// Schema types can be a single string or a list of strings.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Subset of the JSON schema keywords used by the execution-apis specification.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Title                string                 `json:"title"`
+	Type                 schemaTypes            `json:"type"`
+	Pattern              string                 `json:"pattern"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Enum                 []interface{}          `json:"enum"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *additionalProperties  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	OneOf                []*jsonSchema          `json:"oneOf"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+
+	pattern *regexp.Regexp
+}
+
+// The additionalProperties keyword can be a boolean or a schema of the values.
+type additionalProperties struct {
+	allowed bool
+	schema  *jsonSchema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	return json.Unmarshal(data, &a.schema)
+}
+
+type openRPCDocument struct {
+	Methods []struct {
+		Name   string `json:"name"`
+		Result struct {
+			Schema *jsonSchema `json:"schema"`
+		} `json:"result"`
+	} `json:"methods"`
+	Components struct {
+		Schemas map[string]*jsonSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Validates JSON-RPC results against the result schemas of an OpenRPC document.
+type SchemaValidator struct {
+	methods map[string]*jsonSchema
+	schemas map[string]*jsonSchema
+}
+
+const componentsRefPrefix = "#/components/schemas/"
+
+// Creates a validator from an OpenRPC document.
+func NewSchemaValidator(openRPC []byte) (*SchemaValidator, error) {
+	var doc openRPCDocument
+	if err := json.Unmarshal(openRPC, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse OpenRPC document: %v", err)
+	}
+	v := &SchemaValidator{
+		methods: make(map[string]*jsonSchema),
+		schemas: doc.Components.Schemas,
+	}
+	for _, method := range doc.Methods {
+		if method.Result.Schema != nil {
+			v.methods[method.Name] = method.Result.Schema
+		}
+	}
+	// Compile the patterns and verify the references beforehand
+	var prepare func(s *jsonSchema, location string) error
+	prepare = func(s *jsonSchema, location string) error {
+		if s == nil {
+			return nil
+		}
+		if s.Ref != "" {
+			if _, err := v.resolve(s); err != nil {
+				return fmt.Errorf("%s: %v", location, err)
+			}
+		}
+		if s.Pattern != "" {
+			pattern, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				return fmt.Errorf("%s: invalid pattern: %v", location, err)
+			}
+			s.pattern = pattern
+		}
+		for key, property := range s.Properties {
+			if err := prepare(property, location+"."+key); err != nil {
+				return err
+			}
+		}
+		if err := prepare(s.Items, location+"[]"); err != nil {
+			return err
+		}
+		if s.AdditionalProperties != nil {
+			if err := prepare(s.AdditionalProperties.schema, location+".*"); err != nil {
+				return err
+			}
+		}
+		for _, list := range [][]*jsonSchema{s.OneOf, s.AnyOf, s.AllOf} {
+			for i, sub := range list {
+				if err := prepare(sub, fmt.Sprintf("%s/%d", location, i)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for name, s := range v.schemas {
+		if err := prepare(s, componentsRefPrefix+name); err != nil {
+			return nil, err
+		}
+	}
+	for name, s := range v.methods {
+		if err := prepare(s, name); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+var (
+	defaultSchemaValidator     *SchemaValidator
+	defaultSchemaValidatorOnce sync.Once
+)
+
+// Returns the validator of the OpenRPC document vendored into the simulator.
+func DefaultSchemaValidator() *SchemaValidator {
+	defaultSchemaValidatorOnce.Do(func() {
+		v, err := NewSchemaValidator(openRPCSpec)
+		if err != nil {
+			panic(err)
+		}
+		defaultSchemaValidator = v
+	})
+	return defaultSchemaValidator
+}
+
+func (v *SchemaValidator) resolve(s *jsonSchema) (*jsonSchema, error) {
+	for s.Ref != "" {
+		if !strings.HasPrefix(s.Ref, componentsRefPrefix) {
+			return nil, fmt.Errorf("unsupported reference %s", s.Ref)
+		}
+		resolved, ok := v.schemas[strings.TrimPrefix(s.Ref, componentsRefPrefix)]
+		if !ok {
+			return nil, fmt.Errorf("unknown reference %s", s.Ref)
+		}
+		s = resolved
+	}
+	return s, nil
+}
+
+// Returns whether the validator contains the result schema of the method.
+func (v *SchemaValidator) HasMethod(method string) bool {
+	_, ok := v.methods[method]
+	return ok
+}
+
+// Validates the result of a method call, and returns the list of violations,
+// each one prefixed by the JSON path of the offending value.
+// Methods not included in the OpenRPC document are not validated.
+func (v *SchemaValidator) ValidateResult(method string, result json.RawMessage) []string {
+	s, ok := v.methods[method]
+	if !ok {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(result))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return []string{fmt.Sprintf(".result: invalid JSON: %v", err)}
+	}
+	return v.validate(s, value, ".result")
+}
+
+func buildPath(path, key string) string {
+	if path != "." {
+		return path + "." + key
+	}
+	return "." + key
+}
+
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func (s *jsonSchema) acceptsType(valueType string) bool {
+	if len(s.Type) == 0 {
+		return true
+	}
+	for _, t := range s.Type {
+		if t == valueType || (t == "number" && valueType == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *jsonSchema) description() string {
+	if s.Title != "" {
+		return s.Title
+	}
+	if s.Ref != "" {
+		return s.Ref
+	}
+	return strings.Join(s.Type, "|")
+}
+
+// Trims long values, such as blobs, to keep the violation messages readable.
+func trimValue(value string) string {
+	const maxLength = 66
+	if len(value) > maxLength {
+		return value[:maxLength] + "..."
+	}
+	return value
+}
+
+func (v *SchemaValidator) validate(s *jsonSchema, value interface{}, path string) []string {
+	resolved, err := v.resolve(s)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+	s = resolved
+
+	var errors []string
+	for _, sub := range s.AllOf {
+		errors = append(errors, v.validate(sub, value, path)...)
+	}
+	if len(s.OneOf) > 0 {
+		errors = append(errors, v.validateAlternatives(s.OneOf, value, path, true)...)
+	}
+	if len(s.AnyOf) > 0 {
+		errors = append(errors, v.validateAlternatives(s.AnyOf, value, path, false)...)
+	}
+
+	valueType := jsonType(value)
+	if !s.acceptsType(valueType) {
+		return append(errors, fmt.Sprintf("%s: type mismatch (expected %s, got %s)", path, strings.Join(s.Type, "|"), valueType))
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors = append(errors, fmt.Sprintf("%s: value %v not in %v", path, value, s.Enum))
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			errors = append(errors, fmt.Sprintf("%s: value %q does not match %s (%s)", path, trimValue(value), s.description(), s.Pattern))
+		}
+		if s.MinLength != nil && len(value) < *s.MinLength {
+			errors = append(errors, fmt.Sprintf("%s: length %d shorter than %d (%s)", path, len(value), *s.MinLength, s.description()))
+		}
+		if s.MaxLength != nil && len(value) > *s.MaxLength {
+			errors = append(errors, fmt.Sprintf("%s: length %d longer than %d (%s)", path, len(value), *s.MaxLength, s.description()))
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			errors = append(errors, fmt.Sprintf("%s: %d items, expected at least %d", path, len(value), *s.MinItems))
+		}
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			errors = append(errors, fmt.Sprintf("%s: %d items, expected at most %d", path, len(value), *s.MaxItems))
+		}
+		if s.Items != nil {
+			for i, item := range value {
+				errors = append(errors, v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range s.Required {
+			if _, ok := value[key]; !ok {
+				errors = append(errors, fmt.Sprintf("%s: missing key", buildPath(path, key)))
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			property, ok := s.Properties[key]
+			if !ok {
+				switch {
+				case s.AdditionalProperties == nil:
+				case s.AdditionalProperties.schema != nil:
+					errors = append(errors, v.validate(s.AdditionalProperties.schema, value[key], buildPath(path, key))...)
+				case !s.AdditionalProperties.allowed:
+					errors = append(errors, fmt.Sprintf("%s: unexpected key in response", buildPath(path, key)))
+				}
+				continue
+			}
+			errors = append(errors, v.validate(property, value[key], buildPath(path, key))...)
+		}
+	}
+	return errors
+}
+
+// Validates a value against a list of alternative schemas. If the value does
+// not match, the violations of the alternative accepting the type of the value
+// with the fewest violations are returned, which gives a more precise location
+// than just reporting that no alternative matched.
+func (v *SchemaValidator) validateAlternatives(alternatives []*jsonSchema, value interface{}, path string, exactlyOne bool) []string {
+	var (
+		matches   int
+		closest   []string
+		names     = make([]string, len(alternatives))
+		valueType = jsonType(value)
+	)
+	for i, alternative := range alternatives {
+		errors := v.validate(alternative, value, path)
+		if len(errors) == 0 {
+			matches++
+		}
+		names[i] = alternative.description()
+		if resolved, err := v.resolve(alternative); err == nil {
+			names[i] = resolved.description()
+			if resolved.acceptsType(valueType) && (closest == nil || len(errors) < len(closest)) {
+				closest = errors
+			}
+		}
+	}
+	switch {
+	case matches == 0 && len(closest) > 0:
+		return closest
+	case matches == 0:
+		return []string{fmt.Sprintf("%s: type %s does not match any of [%s]", path, valueType, strings.Join(names, ", "))}
+	case matches > 1 && exactlyOne:
+		return []string{fmt.Sprintf("%s: value matches %d of [%s], expected exactly one", path, matches, strings.Join(names, ", "))}
+	}
+	return nil
+}
+
+// Interface to report the schema violations, implemented by hivesim.T.
+type SchemaCheckLogger interface {
+	Logf(format string, values ...interface{})
+	Errorf(format string, values ...interface{})
+}
+
+// SchemaCheckRoundTrip validates every JSON-RPC response against the result
+// schema of the method called, and reports the violations to the test.
+type SchemaCheckRoundTrip struct {
+	Validator *SchemaValidator
+	Logger    SchemaCheckLogger
+	ID        string
+	Inner     http.RoundTripper
+	// Fail the test on violations instead of logging warnings
+	Fail bool
+}
+
+type jsonrpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+type jsonrpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// Decodes a single JSON-RPC message or a batch.
+func decodeJSONRPCBatch[T any](data []byte) ([]T, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []T
+		err := json.Unmarshal(data, &batch)
+		return batch, err
+	}
+	var single T
+	if err := json.Unmarshal(data, &single); err != nil {
+		return nil, err
+	}
+	return []T{single}, nil
+}
+
+func (rt *SchemaCheckRoundTrip) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBytes, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	reqCopy := *req
+	reqCopy.Body = io.NopCloser(bytes.NewReader(reqBytes))
+
+	resp, err := rt.Inner.RoundTrip(&reqCopy)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	respCopy := *resp
+	respCopy.Body = io.NopCloser(bytes.NewReader(respBytes))
+
+	requests, err := decodeJSONRPCBatch[jsonrpcRequest](reqBytes)
+	if err != nil {
+		return &respCopy, nil
+	}
+	responses, err := decodeJSONRPCBatch[jsonrpcResponse](respBytes)
+	if err != nil {
+		// Not a JSON-RPC response, the RPC client reports the error
+		return &respCopy, nil
+	}
+	methods := make(map[string]string, len(requests))
+	for _, request := range requests {
+		methods[string(request.ID)] = request.Method
+	}
+	for _, response := range responses {
+		method, ok := methods[string(response.ID)]
+		if !ok || !rt.Validator.HasMethod(method) || (len(response.Error) > 0 && string(response.Error) != "null") {
+			continue
+		}
+		var violations []string
+		if response.Result == nil {
+			violations = []string{".result: missing key"}
+		} else {
+			violations = rt.Validator.ValidateResult(method, response.Result)
+		}
+		for _, violation := range violations {
+			if rt.Fail {
+				rt.Logger.Errorf("FAIL: (%s) %s response does not conform to the execution-apis schema: %s", rt.ID, method, violation)
+			} else {
+				rt.Logger.Logf("WARN: (%s) %s response does not conform to the execution-apis schema: %s", rt.ID, method, violation)
+			}
+		}
+	}
+	return &respCopy, nil
+}
+
+// Wraps the transport with the schema conformance checks if enabled by the
+// HIVE_ENGINE_SCHEMA_CHECK environment variable, otherwise returns the
+// transport unchanged.
+func SchemaCheckTransport(logger SchemaCheckLogger, id string, inner http.RoundTripper) http.RoundTripper {
+	mode := strings.ToLower(os.Getenv(SchemaCheckEnvVar))
+	switch mode {
+	case "", SchemaCheckOff:
+		return inner
+	case SchemaCheckWarn, SchemaCheckFail:
+		return &SchemaCheckRoundTrip{
+			Validator: DefaultSchemaValidator(),
+			Logger:    logger,
+			ID:        id,
+			Inner:     inner,
+			Fail:      mode == SchemaCheckFail,
+		}
+	}
+	logger.Logf("WARN: unknown %s value %q, schema checks disabled", SchemaCheckEnvVar, mode)
+	return inner
+}
@@ -0,0 +1,242 @@
+package hive_rpc
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testHash    = "0x3b8fb240d288781d4aac94d3fd16809ee413bc99294a085798a589dae51ddd4a"
+	testAddress = "0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b"
+)
+
+func testPayloadV3(omit string, extra string) string {
+	fields := [][2]string{
+		{"parentHash", `"` + testHash + `"`},
+		{"feeRecipient", `"` + testAddress + `"`},
+		{"stateRoot", `"` + testHash + `"`},
+		{"receiptsRoot", `"` + testHash + `"`},
+		{"logsBloom", `"0x` + strings.Repeat("00", 256) + `"`},
+		{"prevRandao", `"` + testHash + `"`},
+		{"blockNumber", `"0x1"`},
+		{"gasLimit", `"0x1c9c380"`},
+		{"gasUsed", `"0x0"`},
+		{"timestamp", `"0x64"`},
+		{"extraData", `"0x"`},
+		{"baseFeePerGas", `"0x7"`},
+		{"blockHash", `"` + testHash + `"`},
+		{"transactions", `["0x02f8"]`},
+		{"withdrawals", `[{"index":"0x0","validatorIndex":"0x1","address":"` + testAddress + `","amount":"0x64"}]`},
+		{"blobGasUsed", `"0x0"`},
+		{"excessBlobGas", `"0x0"`},
+	}
+	parts := make([]string, 0, len(fields)+1)
+	for _, field := range fields {
+		if field[0] != omit {
+			parts = append(parts, fmt.Sprintf("%q:%s", field[0], field[1]))
+		}
+	}
+	if extra != "" {
+		parts = append(parts, extra)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func TestSchemaValidator(t *testing.T) {
+	v := DefaultSchemaValidator()
+	tests := []struct {
+		method string
+		result string
+		want   []string
+	}{
+		{
+			method: "engine_newPayloadV3",
+			result: `{"status":"VALID","latestValidHash":"` + testHash + `"}`,
+		},
+		{
+			method: "engine_newPayloadV3",
+			result: `{"status":"SYNCING"}`,
+		},
+		{
+			method: "engine_newPayloadV3",
+			result: `{"status":"valid","latestValidHash":"` + strings.ToUpper(testHash) + `","validationError":null}`,
+			want: []string{
+				`.result.latestValidHash: value "0X3B8FB240D288781D4AAC94D3FD16809EE413BC99294A085798A589DAE51DDD4A" does not match The hash of the most recent valid block (^0x[0-9a-f]{64}$)`,
+				`.result.status: value valid not in [VALID INVALID SYNCING ACCEPTED]`,
+				`.result.validationError: type mismatch (expected string, got null)`,
+			},
+		},
+		{
+			method: "engine_forkchoiceUpdatedV3",
+			result: `{"payloadStatus":{"status":"VALID","latestValidHash":"` + testHash + `"},"payloadId":"0x0000000000000001"}`,
+		},
+		{
+			method: "engine_forkchoiceUpdatedV3",
+			result: `{"payloadStatus":{"latestValidHash":"` + testHash + `"},"payloadId":"0x01"}`,
+			want: []string{
+				`.result.payloadId: value "0x01" does not match Payload id (^0x[0-9a-f]{16}$)`,
+				`.result.payloadStatus.status: missing key`,
+			},
+		},
+		{
+			method: "engine_getPayloadV3",
+			result: `{"executionPayload":` + testPayloadV3("", "") + `,"blockValue":"0x0","blobsBundle":{"commitments":[],"proofs":[],"blobs":[]},"shouldOverrideBuilder":false}`,
+		},
+		{
+			method: "engine_getPayloadV3",
+			result: `{"executionPayload":` + testPayloadV3("blobGasUsed", `"gasLimit":"0x01c9c380"`) + `,"blockValue":"0x00","blobsBundle":{"commitments":[],"proofs":[],"blobs":[]},"shouldOverrideBuilder":false}`,
+			want: []string{
+				`.result.blockValue: value "0x00" does not match Expected fee value (^0x(0|[1-9a-f][0-9a-f]{0,63})$)`,
+				`.result.executionPayload.blobGasUsed: missing key`,
+				`.result.executionPayload.gasLimit: value "0x01c9c380" does not match Gas limit (^0x(0|[1-9a-f][0-9a-f]{0,15})$)`,
+			},
+		},
+		{
+			method: "engine_getPayloadV2",
+			result: `{"executionPayload":` + testPayloadV3("withdrawals", "") + `,"blockValue":"0x0"}`,
+		},
+		{
+			method: "engine_getPayloadV2",
+			result: `{"executionPayload":` + testPayloadV3("blockHash", "") + `,"blockValue":"0x0"}`,
+			want: []string{
+				`.result.executionPayload.blockHash: missing key`,
+			},
+		},
+		{
+			method: "engine_getBlobsV2",
+			result: `[]`,
+		},
+		{
+			method: "engine_getBlobsV1",
+			result: `[{"blob":"0x00","proof":"0x00"}]`,
+			want: []string{
+				`.result[0].proof: value "0x00" does not match proof (^0x[0-9a-f]{96}$)`,
+			},
+		},
+		{
+			method: "engine_exchangeCapabilities",
+			result: `["engine_newPayloadV1",1]`,
+			want: []string{
+				`.result[1]: type mismatch (expected string, got integer)`,
+			},
+		},
+		{
+			method: "eth_blockNumber",
+			result: `"0x0a"`,
+			want: []string{
+				`.result: value "0x0a" does not match hex encoded unsigned integer (^0x(0|[1-9a-f][0-9a-f]*)$)`,
+			},
+		},
+		{
+			method: "eth_getBlockByNumber",
+			result: `null`,
+		},
+		{
+			method: "eth_getBlockByNumber",
+			result: `{"transactions":["0x01"]}`,
+			want: []string{
+				`.result.hash: missing key`,
+				`.result.parentHash: missing key`,
+				`.result.sha3Uncles: missing key`,
+				`.result.miner: missing key`,
+				`.result.stateRoot: missing key`,
+				`.result.transactionsRoot: missing key`,
+				`.result.receiptsRoot: missing key`,
+				`.result.logsBloom: missing key`,
+				`.result.number: missing key`,
+				`.result.gasLimit: missing key`,
+				`.result.gasUsed: missing key`,
+				`.result.timestamp: missing key`,
+				`.result.extraData: missing key`,
+				`.result.mixHash: missing key`,
+				`.result.nonce: missing key`,
+				`.result.size: missing key`,
+				`.result.uncles: missing key`,
+				`.result.transactions[0]: value "0x01" does not match 32 byte hex value (^0x[0-9a-f]{64}$)`,
+			},
+		},
+		{
+			// Methods without schema are not validated
+			method: "engine_unknownV1",
+			result: `1`,
+		},
+	}
+	for i, test := range tests {
+		got := v.ValidateResult(test.method, []byte(test.result))
+		if len(got) == 0 && len(test.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("test %d (%s): wrong violations\ngot:  %q\nwant: %q", i, test.method, got, test.want)
+		}
+	}
+}
+
+type testSchemaLogger struct {
+	logs   []string
+	errors []string
+}
+
+func (l *testSchemaLogger) Logf(format string, values ...interface{}) {
+	l.logs = append(l.logs, fmt.Sprintf(format, values...))
+}
+
+func (l *testSchemaLogger) Errorf(format string, values ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(format, values...))
+}
+
+func TestSchemaCheckRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `[
+			{"jsonrpc":"2.0","id":1,"result":"0x01"},
+			{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"error"}},
+			{"jsonrpc":"2.0","id":3,"result":"0x1"}
+		]`)
+	}))
+	defer server.Close()
+
+	for _, fail := range []bool{false, true} {
+		logger := &testSchemaLogger{}
+		client := &http.Client{Transport: &SchemaCheckRoundTrip{
+			Validator: DefaultSchemaValidator(),
+			Logger:    logger,
+			ID:        "client",
+			Inner:     http.DefaultTransport,
+			Fail:      fail,
+		}}
+		body := `[
+			{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]},
+			{"jsonrpc":"2.0","id":2,"method":"eth_chainId","params":[]},
+			{"jsonrpc":"2.0","id":3,"method":"eth_chainId","params":[]}
+		]`
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The response must be passed on unchanged
+		respBytes, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(respBytes), `"result":"0x01"`) {
+			t.Fatalf("unexpected response body: %s", respBytes)
+		}
+
+		reported, other := logger.logs, logger.errors
+		if fail {
+			reported, other = logger.errors, logger.logs
+		}
+		if len(reported) != 1 || len(other) != 0 {
+			t.Fatalf("fail=%v: wrong reports, logs: %q, errors: %q", fail, logger.logs, logger.errors)
+		}
+		if !strings.Contains(reported[0], `eth_chainId response does not conform to the execution-apis schema: .result: value "0x01"`) {
+			t.Fatalf("fail=%v: wrong report: %s", fail, reported[0])
+		}
+	}
+}
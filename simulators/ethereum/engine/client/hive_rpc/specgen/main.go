@@ -0,0 +1,344 @@
+// specgen assembles the OpenRPC document of the ethereum/execution-apis specification
+// from the YAML sources of a release, with all references resolved, the same way as
+// 'make build' of the execution-apis repository creates openrpc.json.
+//
+// The sources are fetched from the Go module proxy, unless -src points to a local
+// checkout of the execution-apis repository.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const module = "github.com/ethereum/execution-apis"
+
+// Source directories of the specification, as passed to specgen by the Makefile of
+// the execution-apis repository.
+var (
+	schemaDirs     = []string{"src/schemas", "src/engine/openrpc/schemas"}
+	methodDirs     = []string{"src/eth", "src/debug", "src/txpool", "src/engine/openrpc/methods", "src/testing"}
+	errorGroupDirs = []string{"src/error-groups"}
+)
+
+func main() {
+	var (
+		version = flag.String("version", "", "Release of the specification")
+		src     = flag.String("src", "", "Local checkout of the execution-apis repository (optional)")
+		out     = flag.String("out", "openrpc.json", "Output file")
+	)
+	flag.Parse()
+	if *version == "" {
+		fatalf("-version is required")
+	}
+	dir := *src
+	if dir == "" {
+		var err error
+		if dir, err = download(*version); err != nil {
+			fatalf("can't download %s@%s: %v", module, *version, err)
+		}
+	}
+	doc, err := build(dir, *version)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := os.WriteFile(*out, append(content, '\n'), 0644); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "specgen: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+// download fetches the sources of a release into the module cache, and returns
+// their location.
+func download(version string) (string, error) {
+	cmd := exec.Command("go", "mod", "download", "-json", module+"@"+version)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	var info struct {
+		Dir   string
+		Error string
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return "", err
+	}
+	if info.Error != "" {
+		return "", fmt.Errorf("%s", info.Error)
+	}
+	return info.Dir, nil
+}
+
+// build creates the OpenRPC document from the sources in dir.
+func build(dir, version string) (map[string]interface{}, error) {
+	schemas := make(map[string]interface{})
+	for _, d := range schemaDirs {
+		if err := readMaps(filepath.Join(dir, d), schemas); err != nil {
+			return nil, err
+		}
+	}
+	errorGroups := make(map[string]interface{})
+	for _, d := range errorGroupDirs {
+		if err := readMaps(filepath.Join(dir, d), errorGroups); err != nil {
+			return nil, err
+		}
+	}
+	var methods []interface{}
+	for _, d := range methodDirs {
+		files, err := yamlFiles(filepath.Join(dir, d))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			var list []interface{}
+			if err := readYAML(file, &list); err != nil {
+				return nil, err
+			}
+			methods = append(methods, list...)
+		}
+	}
+
+	r := &resolver{components: map[string]interface{}{
+		"schemas":      schemas,
+		"error-groups": errorGroups,
+	}}
+	for i, method := range methods {
+		m, ok := method.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid method definition %v", method)
+		}
+		if err := expandErrorGroups(r, m); err != nil {
+			return nil, fmt.Errorf("%v: %v", m["name"], err)
+		}
+		resolved, err := r.deref(m, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", m["name"], err)
+		}
+		methods[i] = resolved
+	}
+	resolvedSchemas := make(map[string]interface{}, len(schemas))
+	for name, s := range schemas {
+		resolved, err := r.deref(s, []string{"#/components/schemas/" + name})
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %v", name, err)
+		}
+		resolvedSchemas[name] = resolved
+	}
+
+	return map[string]interface{}{
+		"openrpc": "1.2.4",
+		"info": map[string]interface{}{
+			"title":       "Ethereum JSON-RPC Specification",
+			"description": "A specification of the standard interface for Ethereum clients.",
+			"license": map[string]interface{}{
+				"name": "CC0-1.0",
+				"url":  "https://creativecommons.org/publicdomain/zero/1.0/legalcode",
+			},
+			"version": strings.TrimPrefix(version, "v"),
+		},
+		"methods": methods,
+		"components": map[string]interface{}{
+			"schemas": resolvedSchemas,
+		},
+	}, nil
+}
+
+// yamlFiles returns the YAML files of a directory in lexical order.
+func yamlFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no YAML files in %s", dir)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readMaps adds the definitions of all YAML files of a directory to m.
+func readMaps(dir string, m map[string]interface{}) error {
+	files, err := yamlFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		var defs map[string]interface{}
+		if err := readYAML(file, &defs); err != nil {
+			return err
+		}
+		for name, def := range defs {
+			if _, ok := m[name]; ok {
+				return fmt.Errorf("%s: duplicate definition of %s", file, name)
+			}
+			m[name] = def
+		}
+	}
+	return nil
+}
+
+func readYAML(file string, v interface{}) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+// expandErrorGroups replaces the error-groups references of a method by the errors
+// of the groups, which are appended to the errors defined by the method itself.
+func expandErrorGroups(r *resolver, method map[string]interface{}) error {
+	groups, ok := method["error-groups"].([]interface{})
+	if !ok {
+		return nil
+	}
+	errors, _ := method["errors"].([]interface{})
+	for _, group := range groups {
+		ref, _ := group.(map[string]interface{})["$ref"].(string)
+		target, err := r.lookup(ref)
+		if err != nil {
+			return err
+		}
+		groupErrors, _ := target.(map[string]interface{})["errors"].([]interface{})
+		errors = append(errors, groupErrors...)
+	}
+	method["errors"] = errors
+	delete(method, "error-groups")
+	return nil
+}
+
+// resolver replaces JSON references to the components of the specification by the
+// referenced definitions.
+type resolver struct {
+	components map[string]interface{}
+}
+
+const componentsPrefix = "#/components/"
+
+// lookup returns the definition a reference points to.
+func (r *resolver) lookup(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, componentsPrefix) {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+	var v interface{} = r.components
+	for _, key := range strings.Split(strings.TrimPrefix(ref, componentsPrefix), "/") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unknown reference %q", ref)
+		}
+		if v, ok = m[key]; !ok {
+			return nil, fmt.Errorf("unknown reference %q", ref)
+		}
+	}
+	return v, nil
+}
+
+// deref returns a copy of v with all references resolved. Other keys next to a
+// reference are merged into the referenced definition. References which are part of
+// a cycle are kept, they point to the schemas of the components.
+func (r *resolver) deref(v interface{}, stack []string) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		if ref, ok := v["$ref"].(string); ok {
+			for _, s := range stack {
+				if s == ref {
+					return copyValue(v), nil
+				}
+			}
+			target, err := r.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			resolved, err := r.deref(target, append(stack, ref))
+			if err != nil {
+				return nil, err
+			}
+			if m, ok := resolved.(map[string]interface{}); ok {
+				out = m
+			}
+		}
+		for key, value := range v {
+			if key == "$ref" {
+				continue
+			}
+			resolved, err := r.deref(value, stack)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = merge(out[key], resolved)
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			resolved, err := r.deref(v[i], stack)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// merge combines two definitions. Objects are merged key by key, any other value of
+// b replaces a.
+func merge(a, b interface{}) interface{} {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		return b
+	}
+	out := make(map[string]interface{}, len(am)+len(bm))
+	for key, value := range am {
+		out[key] = value
+	}
+	for key, value := range bm {
+		out[key] = merge(out[key], value)
+	}
+	return out
+}
+
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			out[key] = copyValue(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = copyValue(v[i])
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -12,6 +12,7 @@ import (
 	beacon "github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -418,6 +419,8 @@ func (n *GethNode) NewPayload(ctx context.Context, version int, pl *typ.Executab
 		return n.NewPayloadV2(ctx, pl)
 	case 3:
 		return n.NewPayloadV3(ctx, pl)
+	case 4:
+		return n.NewPayloadV4(ctx, pl)
 	}
 	return beacon.PayloadStatusV1{}, fmt.Errorf("unknown version %d", version)
 }
@@ -458,6 +461,27 @@ func (n *GethNode) NewPayloadV3(ctx context.Context, pl *typ.ExecutableData) (be
 	return resp, err
 }
 
+func (n *GethNode) NewPayloadV4(ctx context.Context, pl *typ.ExecutableData) (beacon.PayloadStatusV1, error) {
+	n.latestPayloadSent = pl
+	ed, err := typ.ToBeaconExecutableData(pl)
+	if err != nil {
+		return beacon.PayloadStatusV1{}, err
+	}
+	if pl.VersionedHashes == nil {
+		return beacon.PayloadStatusV1{}, fmt.Errorf("versioned hashes are nil")
+	}
+	var requests []hexutil.Bytes
+	if pl.ExecutionRequests != nil {
+		requests = make([]hexutil.Bytes, len(pl.ExecutionRequests))
+		for i, r := range pl.ExecutionRequests {
+			requests[i] = r
+		}
+	}
+	resp, err := n.api.NewPayloadV4(ed, *pl.VersionedHashes, pl.ParentBeaconBlockRoot, requests)
+	n.latestPayloadStatusReponse = &resp
+	return resp, err
+}
+
 func (n *GethNode) ForkchoiceUpdated(ctx context.Context, version int, fcs *beacon.ForkchoiceStateV1, payload *typ.PayloadAttributes) (beacon.ForkChoiceResponse, error) {
 	switch version {
 	case 1:
@@ -538,6 +562,40 @@ func (n *GethNode) GetPayloadV3(ctx context.Context, payloadId *beacon.PayloadID
 	return ed, p.BlockValue, blobsBundle, &p.Override, err
 }
 
+func (n *GethNode) GetPayloadV4(ctx context.Context, payloadId *beacon.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
+	p, err := n.api.GetPayloadV4(*payloadId)
+	if p == nil || err != nil {
+		return typ.ExecutableData{}, nil, nil, nil, err
+	}
+	ed, err := typ.FromBeaconExecutableData(p.ExecutionPayload)
+	if err != nil {
+		return typ.ExecutableData{}, nil, nil, nil, err
+	}
+	ed.ExecutionRequests = p.Requests
+	blobsBundle := &typ.BlobsBundle{}
+	if err = blobsBundle.FromBeaconBlobsBundle(p.BlobsBundle); err != nil {
+		return typ.ExecutableData{}, nil, nil, nil, err
+	}
+	return ed, p.BlockValue, blobsBundle, &p.Override, nil
+}
+
+func (n *GethNode) GetPayloadV5(ctx context.Context, payloadId *beacon.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
+	p, err := n.api.GetPayloadV5(*payloadId)
+	if p == nil || err != nil {
+		return typ.ExecutableData{}, nil, nil, nil, err
+	}
+	ed, err := typ.FromBeaconExecutableData(p.ExecutionPayload)
+	if err != nil {
+		return typ.ExecutableData{}, nil, nil, nil, err
+	}
+	ed.ExecutionRequests = p.Requests
+	blobsBundle := &typ.BlobsBundle{}
+	if err = blobsBundle.FromBeaconBlobsBundle(p.BlobsBundle); err != nil {
+		return typ.ExecutableData{}, nil, nil, nil, err
+	}
+	return ed, p.BlockValue, blobsBundle, &p.Override, nil
+}
+
 func (n *GethNode) GetPayload(ctx context.Context, version int, payloadId *beacon.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
 
 	switch version {
@@ -549,6 +607,10 @@ func (n *GethNode) GetPayload(ctx context.Context, version int, payloadId *beaco
 		return ed, value, nil, nil, err
 	case 3:
 		return n.GetPayloadV3(ctx, payloadId)
+	case 4:
+		return n.GetPayloadV4(ctx, payloadId)
+	case 5:
+		return n.GetPayloadV5(ctx, payloadId)
 	default:
 		return typ.ExecutableData{}, nil, nil, nil, fmt.Errorf("unknown version %d", version)
 	}
@@ -562,6 +624,42 @@ func (n *GethNode) GetPayloadBodiesByHashV1(ctx context.Context, hashes []common
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (n *GethNode) GetBlobsV1(ctx context.Context, versionedHashes []common.Hash) ([]*typ.BlobAndProofV1, error) {
+	blobs, err := n.api.GetBlobsV1(versionedHashes)
+	if blobs == nil || err != nil {
+		return nil, err
+	}
+	result := make([]*typ.BlobAndProofV1, len(blobs))
+	for i, blob := range blobs {
+		if blob == nil {
+			continue
+		}
+		result[i] = &typ.BlobAndProofV1{}
+		if err := result[i].FromBeaconBlobAndProof(blob); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (n *GethNode) GetBlobsV2(ctx context.Context, versionedHashes []common.Hash) ([]*typ.BlobAndProofV2, error) {
+	blobs, err := n.api.GetBlobsV2(versionedHashes)
+	if blobs == nil || err != nil {
+		return nil, err
+	}
+	result := make([]*typ.BlobAndProofV2, len(blobs))
+	for i, blob := range blobs {
+		if blob == nil {
+			continue
+		}
+		result[i] = &typ.BlobAndProofV2{}
+		if err := result[i].FromBeaconBlobAndProof(blob); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 func (n *GethNode) GetBlobsBundleV1(ctx context.Context, payloadId *beacon.PayloadID) (*typ.BlobsBundle, error) {
 	return nil, fmt.Errorf("not implemented")
 }
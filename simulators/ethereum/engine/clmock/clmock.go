@@ -70,6 +70,9 @@ type CLMocker struct {
 	EngineClients []client.EngineClient
 	// Lock required so no client is offboarded during block production.
 	EngineClientsLock sync.Mutex
+	// Fail when a client rejects a payload built by a different client, used when
+	// the engine clients are of different types
+	CrossClientValidation bool
 	// Number of required slots before a block which was set as Head moves to `safe` and `finalized` respectively
 	SlotsToSafe      *big.Int
 	SlotsToFinalized *big.Int
@@ -482,6 +485,18 @@ func (cl *CLMocker) GetNextPayload() {
 		}
 		cl.LatestPayloadBuilt.ParentBeaconBlockRoot = cl.LatestPayloadAttributes.BeaconRoot
 	}
+	if cl.IsPrague(cl.LatestPayloadBuilt.Timestamp) {
+		// The execution requests are broadcast along with the payload
+		if cl.LatestPayloadBuilt.ExecutionRequests == nil {
+			cl.Fatalf("CLMocker: No execution requests on prague")
+		}
+	}
+	if cl.IsOsaka(cl.LatestPayloadBuilt.Timestamp) {
+		// The blob bundle contains the cell proofs of each blob
+		if len(cl.LatestBlobBundle.Proofs) != len(cl.LatestBlobBundle.Blobs)*typ.CellsPerExtBlob {
+			cl.Fatalf("CLMocker: Incorrect cell proof count on osaka: %d proofs for %d blobs", len(cl.LatestBlobBundle.Proofs), len(cl.LatestBlobBundle.Blobs))
+		}
+	}
 	cl.LatestPayloadBuilt.PayloadAttributes = cl.LatestPayloadAttributes
 }
 
@@ -514,6 +529,12 @@ func (cl *CLMocker) broadcastNextNewPayload() {
 				if resp.ExecutePayloadResponse.LatestValidHash != nil && *resp.ExecutePayloadResponse.LatestValidHash != (common.Hash{}) {
 					cl.Fatalf("CLMocker: NewPayload returned ACCEPTED status with incorrect LatestValidHash==%v", resp.ExecutePayloadResponse.LatestValidHash)
 				}
+			} else if resp.ExecutePayloadResponse.Status == api.INVALID && cl.CrossClientValidation && resp.Container != cl.NextBlockProducer.ID() {
+				validationError := "<nil>"
+				if resp.ExecutePayloadResponse.ValidationError != nil {
+					validationError = *resp.ExecutePayloadResponse.ValidationError
+				}
+				cl.Fatalf("CLMocker: Payload %d (%v) built by %s was rejected by %s: %s", cl.LatestPayloadBuilt.Number, cl.LatestPayloadBuilt.BlockHash, cl.NextBlockProducer.ID(), resp.Container, validationError)
 			} else {
 				cl.Logf("CLMocker: BroadcastNewPayload Response (%v): %v\n", resp.Container, resp.ExecutePayloadResponse)
 			}
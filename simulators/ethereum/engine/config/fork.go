@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 type Fork string
@@ -14,6 +15,8 @@ const (
 	Paris    Fork = "Paris"
 	Shanghai Fork = "Shanghai"
 	Cancun   Fork = "Cancun"
+	Prague   Fork = "Prague"
+	Osaka    Fork = "Osaka"
 )
 
 func (f Fork) PreviousFork() Fork {
@@ -24,6 +27,10 @@ func (f Fork) PreviousFork() Fork {
 		return Paris
 	case Cancun:
 		return Shanghai
+	case Prague:
+		return Cancun
+	case Osaka:
+		return Prague
 	default:
 		return NA
 	}
@@ -32,6 +39,11 @@ func (f Fork) PreviousFork() Fork {
 type ForkConfig struct {
 	ShanghaiTimestamp *big.Int
 	CancunTimestamp   *big.Int
+	PragueTimestamp   *big.Int
+	OsakaTimestamp    *big.Int
+
+	// Blob schedule of the Osaka fork, the fork default is used if nil
+	OsakaBlobConfig *params.BlobConfig
 }
 
 func (f *ForkConfig) IsShanghai(blockTimestamp uint64) bool {
@@ -42,6 +54,14 @@ func (f *ForkConfig) IsCancun(blockTimestamp uint64) bool {
 	return f.CancunTimestamp != nil && new(big.Int).SetUint64(blockTimestamp).Cmp(f.CancunTimestamp) >= 0
 }
 
+func (f *ForkConfig) IsPrague(blockTimestamp uint64) bool {
+	return f.PragueTimestamp != nil && new(big.Int).SetUint64(blockTimestamp).Cmp(f.PragueTimestamp) >= 0
+}
+
+func (f *ForkConfig) IsOsaka(blockTimestamp uint64) bool {
+	return f.OsakaTimestamp != nil && new(big.Int).SetUint64(blockTimestamp).Cmp(f.OsakaTimestamp) >= 0
+}
+
 func (f *ForkConfig) ForkchoiceUpdatedVersion(headTimestamp uint64, payloadAttributesTimestamp *uint64) int {
 	// If the payload attributes timestamp is nil, use the head timestamp
 	// to calculate the FcU version.
@@ -59,7 +79,9 @@ func (f *ForkConfig) ForkchoiceUpdatedVersion(headTimestamp uint64, payloadAttri
 }
 
 func (f *ForkConfig) NewPayloadVersion(timestamp uint64) int {
-	if f.IsCancun(timestamp) {
+	if f.IsPrague(timestamp) {
+		return 4
+	} else if f.IsCancun(timestamp) {
 		return 3
 	} else if f.IsShanghai(timestamp) {
 		return 2
@@ -68,7 +90,11 @@ func (f *ForkConfig) NewPayloadVersion(timestamp uint64) int {
 }
 
 func (f *ForkConfig) GetPayloadVersion(timestamp uint64) int {
-	if f.IsCancun(timestamp) {
+	if f.IsOsaka(timestamp) {
+		return 5
+	} else if f.IsPrague(timestamp) {
+		return 4
+	} else if f.IsCancun(timestamp) {
 		return 3
 	} else if f.IsShanghai(timestamp) {
 		return 2
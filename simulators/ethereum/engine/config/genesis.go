@@ -6,6 +6,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/hive/simulators/ethereum/engine/config/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/osaka"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
 )
 
 func (f *ForkConfig) ConfigGenesis(genesis *core.Genesis) error {
@@ -20,5 +22,15 @@ func (f *ForkConfig) ConfigGenesis(genesis *core.Genesis) error {
 			return fmt.Errorf("failed to configure cancun fork: %v", err)
 		}
 	}
+	if f.PragueTimestamp != nil {
+		if err := prague.ConfigGenesis(genesis, f.PragueTimestamp.Uint64()); err != nil {
+			return fmt.Errorf("failed to configure prague fork: %v", err)
+		}
+	}
+	if f.OsakaTimestamp != nil {
+		if err := osaka.ConfigGenesis(genesis, f.OsakaTimestamp.Uint64(), f.OsakaBlobConfig); err != nil {
+			return fmt.Errorf("failed to configure osaka fork: %v", err)
+		}
+	}
 	return nil
 }
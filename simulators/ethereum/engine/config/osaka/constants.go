@@ -0,0 +1,22 @@
+package osaka
+
+import (
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// EIP 7594
+	CELLS_PER_EXT_BLOB = 128
+	MAX_BLOBS_PER_TX   = uint64(params.BlobTxMaxBlobs)
+
+	// EIP 7825
+	MAX_TX_GAS = params.MaxTxGas
+
+	// EIP 7918
+	BLOB_BASE_COST = uint64(params.BlobBaseCost)
+
+	// Default blob schedule
+	TARGET_BLOBS_PER_BLOCK        = uint64(6)
+	MAX_BLOBS_PER_BLOCK           = uint64(9)
+	BLOB_GASPRICE_UPDATE_FRACTION = uint64(5007716)
+)
@@ -0,0 +1,37 @@
+package osaka
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// DefaultBlobConfig returns the blob schedule entry of the Osaka fork.
+func DefaultBlobConfig() *params.BlobConfig {
+	return &params.BlobConfig{
+		Target:         int(TARGET_BLOBS_PER_BLOCK),
+		Max:            int(MAX_BLOBS_PER_BLOCK),
+		UpdateFraction: BLOB_GASPRICE_UPDATE_FRACTION,
+	}
+}
+
+// ConfigGenesis configures the genesis block for the Osaka fork.
+// If blobConfig is nil, the default blob schedule of the fork is used.
+func ConfigGenesis(genesis *core.Genesis, forkTimestamp uint64, blobConfig *params.BlobConfig) error {
+	if genesis.Config.PragueTime == nil {
+		return fmt.Errorf("osaka fork requires prague fork")
+	}
+	genesis.Config.OsakaTime = &forkTimestamp
+	if *genesis.Config.PragueTime > forkTimestamp {
+		return fmt.Errorf("osaka fork must be after prague fork")
+	}
+	if genesis.Config.BlobScheduleConfig == nil {
+		return fmt.Errorf("osaka fork requires a blob schedule")
+	}
+	if blobConfig == nil {
+		blobConfig = DefaultBlobConfig()
+	}
+	genesis.Config.BlobScheduleConfig.Osaka = blobConfig
+	return nil
+}
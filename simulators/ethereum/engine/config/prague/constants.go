@@ -0,0 +1,35 @@
+package prague
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// EIP 7685
+	DEPOSIT_REQUEST_TYPE       = byte(0x00)
+	WITHDRAWAL_REQUEST_TYPE    = byte(0x01)
+	CONSOLIDATION_REQUEST_TYPE = byte(0x02)
+
+	// EIP 6110
+	DEPOSIT_CONTRACT_ADDRESS = common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+	DEPOSIT_EVENT_TOPIC      = common.HexToHash("0x649bbc62d0e31342afea4e5cd82d4049e7e1ee912fc0889aa790803be39038c5")
+	DEPOSIT_REQUEST_SIZE     = 192
+
+	// EIP 7002
+	WITHDRAWAL_REQUEST_ADDRESS = params.WithdrawalQueueAddress
+	WITHDRAWAL_REQUEST_SIZE    = 76
+
+	// EIP 7251
+	CONSOLIDATION_REQUEST_ADDRESS = params.ConsolidationQueueAddress
+	CONSOLIDATION_REQUEST_SIZE    = 116
+
+	// EIP 2935
+	HISTORY_STORAGE_ADDRESS = params.HistoryStorageAddress
+
+	// EIP 7702
+	DELEGATION_PREFIX = []byte{0xef, 0x01, 0x00}
+
+	// Test constants
+	DELEGATE_TARGET_ADDRESS = common.HexToAddress("0x0000000000000000000000000000000000007702")
+)
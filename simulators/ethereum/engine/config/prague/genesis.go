@@ -0,0 +1,92 @@
+package prague
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ConfigGenesis configures the genesis block for the Prague fork.
+func ConfigGenesis(genesis *core.Genesis, forkTimestamp uint64) error {
+	if genesis.Config.CancunTime == nil {
+		return fmt.Errorf("prague fork requires cancun fork")
+	}
+	genesis.Config.PragueTime = &forkTimestamp
+	if *genesis.Config.CancunTime > forkTimestamp {
+		return fmt.Errorf("prague fork must be after cancun fork")
+	}
+	if genesis.Config.BlobScheduleConfig == nil {
+		return fmt.Errorf("prague fork requires a blob schedule")
+	}
+	genesis.Config.BlobScheduleConfig.Prague = &params.BlobConfig{
+		Target:         6,
+		Max:            9,
+		UpdateFraction: 5007716,
+	}
+	genesis.Config.DepositContractAddress = DEPOSIT_CONTRACT_ADDRESS
+
+	// Add the system contracts of EIP-2935, EIP-7002 and EIP-7251.
+	for addr, code := range map[common.Address][]byte{
+		HISTORY_STORAGE_ADDRESS:       params.HistoryStorageCode,
+		WITHDRAWAL_REQUEST_ADDRESS:    params.WithdrawalQueueCode,
+		CONSOLIDATION_REQUEST_ADDRESS: params.ConsolidationQueueCode,
+	} {
+		genesis.Alloc[addr] = core.GenesisAccount{
+			Balance: common.Big0,
+			Nonce:   1,
+			Code:    code,
+		}
+	}
+
+	// The deposit contract is replaced by a contract which emits the call data as a
+	// DepositEvent log, so that tests can create deposits without BLS signatures.
+	genesis.Alloc[DEPOSIT_CONTRACT_ADDRESS] = core.GenesisAccount{
+		Balance: common.Big0,
+		Nonce:   1,
+		Code:    depositContractCode(),
+	}
+
+	return nil
+}
+
+// depositContractCode returns the bytecode of the deposit log emitter:
+//
+//	CALLDATACOPY(0, 0, CALLDATASIZE)
+//	LOG1(0, CALLDATASIZE, DEPOSIT_EVENT_TOPIC)
+func depositContractCode() []byte {
+	code := []byte{
+		0x36, // CALLDATASIZE
+		0x5F, // PUSH0
+		0x5F, // PUSH0
+		0x37, // CALLDATACOPY
+		0x7F, // PUSH32
+	}
+	code = append(code, DEPOSIT_EVENT_TOPIC[:]...)
+	code = append(code,
+		0x36, // CALLDATASIZE
+		0x5F, // PUSH0
+		0xA1, // LOG1
+		0x00, // STOP
+	)
+	return code
+}
+
+// Configure specific test genesis accounts related to Prague functionality.
+func ConfigTestAccounts(genesis *core.Genesis) error {
+	// Add the delegation target used by EIP-7702 tests. When called through a
+	// delegated account, it stores the caller in slot zero of the account.
+	if _, ok := genesis.Alloc[DELEGATE_TARGET_ADDRESS]; ok {
+		return fmt.Errorf("reused address %s during genesis configuration for prague", DELEGATE_TARGET_ADDRESS.Hex())
+	}
+	genesis.Alloc[DELEGATE_TARGET_ADDRESS] = core.GenesisAccount{
+		Balance: common.Big0,
+		Code: []byte{
+			0x33, // CALLER
+			0x5F, // PUSH0
+			0x55, // SSTORE
+		},
+	}
+	return nil
+}
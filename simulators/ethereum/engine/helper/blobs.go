@@ -16,6 +16,8 @@ import (
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
 	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
 	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
 )
@@ -101,6 +103,9 @@ type BlobTransactionCreator struct {
 	BlobCount  uint64
 	Value      *big.Int
 	Data       []byte
+	// Fork config used to determine the blob wrapper version: transactions
+	// created on Osaka contain cell proofs.
+	ForkConfig *config.ForkConfig
 }
 
 var uint256BlsModulus = new(uint256.Int).SetBytes32(gokzg4844.BlsModulus[:])
@@ -257,11 +262,73 @@ func (blobId BlobID) GetVersionedHash(commitmentVersion byte) (common.Hash, erro
 	return versionedHash, nil
 }
 
+// VerifyBlobProof verifies the EIP-4844 KZG proof of a blob.
+func VerifyBlobProof(blob *typ.Blob, commitment *typ.KZGCommitment, proof *typ.KZGProof) error {
+	ctx_4844 := CryptoCtx()
+	return ctx_4844.VerifyBlobKZGProof((*gokzg4844.Blob)(blob), gokzg4844.KZGCommitment(*commitment), gokzg4844.KZGProof(*proof))
+}
+
+// GenerateCellProofs computes the EIP-7594 cell proofs of the blob.
+func (blobId BlobID) GenerateCellProofs() ([]typ.KZGProof, error) {
+	blob := typ.Blob{}
+	if err := blobId.FillBlob(&blob); err != nil {
+		return nil, errors.Wrap(err, "GenerateCellProofs: Filling Blob")
+	}
+	return ComputeCellProofs(&blob)
+}
+
+// ComputeCellProofs computes the EIP-7594 cell proofs of a blob.
+func ComputeCellProofs(blob *typ.Blob) ([]typ.KZGProof, error) {
+	proofs, err := kzg4844.ComputeCellProofs((*kzg4844.Blob)(blob))
+	if err != nil {
+		return nil, errors.Wrap(err, "ComputeCellProofs")
+	}
+	cellProofs := make([]typ.KZGProof, len(proofs))
+	for i, proof := range proofs {
+		cellProofs[i] = typ.KZGProof(proof)
+	}
+	return cellProofs, nil
+}
+
+// VerifyCellProofs verifies the EIP-7594 cell proofs of a list of blobs, where
+// proofs contains the typ.CellsPerExtBlob proofs of each blob in order.
+func VerifyCellProofs(blobs []typ.Blob, commitments []typ.KZGCommitment, proofs []typ.KZGProof) error {
+	var (
+		kzgBlobs       = make([]kzg4844.Blob, len(blobs))
+		kzgCommitments = make([]kzg4844.Commitment, len(commitments))
+		kzgProofs      = make([]kzg4844.Proof, len(proofs))
+	)
+	for i := range blobs {
+		kzgBlobs[i] = kzg4844.Blob(blobs[i])
+	}
+	for i := range commitments {
+		kzgCommitments[i] = kzg4844.Commitment(commitments[i])
+	}
+	for i := range proofs {
+		kzgProofs[i] = kzg4844.Proof(proofs[i])
+	}
+	return kzg4844.VerifyCellProofs(kzgBlobs, kzgCommitments, kzgProofs)
+}
+
 func BlobDataGenerator(startBlobId BlobID, blobCount uint64) ([]common.Hash, *typ.BlobTxWrapData, error) {
+	return BlobDataGeneratorWithVersion(startBlobId, blobCount, typ.BlobTxWrapperVersion0)
+}
+
+// BlobDataGeneratorWithVersion generates the blob data of a transaction, including
+// a single proof per blob on version 0, and the cell proofs of each blob on version 1.
+func BlobDataGeneratorWithVersion(startBlobId BlobID, blobCount uint64, version byte) ([]common.Hash, *typ.BlobTxWrapData, error) {
 	blobData := typ.BlobTxWrapData{
+		Version:     version,
 		Blobs:       make(typ.Blobs, blobCount),
 		Commitments: make([]typ.KZGCommitment, blobCount),
-		Proofs:      make(typ.KZGProofs, blobCount),
+	}
+	switch version {
+	case typ.BlobTxWrapperVersion0:
+		blobData.Proofs = make(typ.KZGProofs, blobCount)
+	case typ.BlobTxWrapperVersion1:
+		blobData.Proofs = make(typ.KZGProofs, 0, blobCount*uint64(typ.CellsPerExtBlob))
+	default:
+		return nil, nil, fmt.Errorf("unknown blob wrapper version %d", version)
 	}
 	for i := uint64(0); i < blobCount; i++ {
 		if blob, kzgCommitment, kzgProof, err := (startBlobId + BlobID(i)).GenerateBlob(); err != nil {
@@ -269,7 +336,15 @@ func BlobDataGenerator(startBlobId BlobID, blobCount uint64) ([]common.Hash, *ty
 		} else {
 			blobData.Blobs[i] = *blob
 			blobData.Commitments[i] = *kzgCommitment
-			blobData.Proofs[i] = *kzgProof
+			if version == typ.BlobTxWrapperVersion0 {
+				blobData.Proofs[i] = *kzgProof
+				continue
+			}
+			cellProofs, err := ComputeCellProofs(blob)
+			if err != nil {
+				return nil, nil, err
+			}
+			blobData.Proofs = append(blobData.Proofs, cellProofs...)
 		}
 	}
 	var hashes []common.Hash
@@ -279,9 +354,18 @@ func BlobDataGenerator(startBlobId BlobID, blobCount uint64) ([]common.Hash, *ty
 	return hashes, &blobData, nil
 }
 
+// BlobWrapperVersion returns the blob transaction wrapper version expected by the
+// clients at the given timestamp.
+func BlobWrapperVersion(forkConfig *config.ForkConfig, timestamp uint64) byte {
+	if forkConfig != nil && forkConfig.IsOsaka(timestamp) {
+		return typ.BlobTxWrapperVersion1
+	}
+	return typ.BlobTxWrapperVersion0
+}
+
 func (tc *BlobTransactionCreator) MakeTransaction(sender SenderAccount, nonce uint64, blockTimestamp uint64) (typ.Transaction, error) {
 	// Need tx wrap data that will pass blob verification
-	hashes, blobData, err := BlobDataGenerator(tc.BlobID, tc.BlobCount)
+	hashes, blobData, err := BlobDataGeneratorWithVersion(tc.BlobID, tc.BlobCount, BlobWrapperVersion(tc.ForkConfig, blockTimestamp))
 	if err != nil {
 		return nil, err
 	}
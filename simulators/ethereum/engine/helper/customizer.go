@@ -317,6 +317,8 @@ type CustomPayloadData struct {
 	ParentBeaconRoot          *common.Hash
 	RemoveParentBeaconRoot    bool
 	VersionedHashesCustomizer VersionedHashesCustomizer
+	ExecutionRequests         *[][]byte
+	RemoveExecutionRequests   bool
 }
 
 var _ PayloadCustomizer = (*CustomPayloadData)(nil)
@@ -426,6 +428,16 @@ func (customData *CustomPayloadData) CustomizePayload(randSource *rand.Rand, bas
 	} else if basePayload.ParentBeaconBlockRoot != nil {
 		customPayloadHeader.ParentBeaconRoot = basePayload.ParentBeaconBlockRoot
 	}
+	requests := basePayload.ExecutionRequests
+	if customData.RemoveExecutionRequests {
+		requests = nil
+	} else if customData.ExecutionRequests != nil {
+		requests = *customData.ExecutionRequests
+	}
+	if requests != nil {
+		h := types.CalcRequestsHash(requests)
+		customPayloadHeader.RequestsHash = &h
+	}
 
 	// Return the new payload
 	result := &typ.ExecutableData{
@@ -448,6 +460,7 @@ func (customData *CustomPayloadData) CustomizePayload(randSource *rand.Rand, bas
 
 		// Metadata
 		ParentBeaconBlockRoot: customPayloadHeader.ParentBeaconRoot,
+		ExecutionRequests:     requests,
 		PayloadAttributes:     basePayload.PayloadAttributes,
 	}
 
@@ -585,6 +598,11 @@ func (customData *CustomPayloadData) String() string {
 	if customData.Withdrawals != nil {
 		customFieldsList = append(customFieldsList, fmt.Sprintf("Withdrawals=%v", customData.Withdrawals))
 	}
+	if customData.RemoveExecutionRequests {
+		customFieldsList = append(customFieldsList, "ExecutionRequests=nil")
+	} else if customData.ExecutionRequests != nil {
+		customFieldsList = append(customFieldsList, fmt.Sprintf("ExecutionRequests=%x", *customData.ExecutionRequests))
+	}
 	return strings.Join(customFieldsList, ", ")
 }
 
@@ -701,6 +719,19 @@ func GenerateInvalidPayload(randSource *rand.Rand, basePayload *typ.ExecutableDa
 		customPayloadMod = &CustomPayloadData{
 			VersionedHashesCustomizer: &ExtraVersionedHash{},
 		}
+	case InvalidExecutionRequests:
+		if len(basePayload.ExecutionRequests) == 0 {
+			return nil, fmt.Errorf("no execution requests available for modification")
+		}
+		modExecutionRequests := make([][]byte, len(basePayload.ExecutionRequests))
+		for i, r := range basePayload.ExecutionRequests {
+			modExecutionRequests[i] = common.CopyBytes(r)
+		}
+		req := modExecutionRequests[0]
+		req[len(req)-1] = byte(255 - req[len(req)-1])
+		customPayloadMod = &CustomPayloadData{
+			ExecutionRequests: &modExecutionRequests,
+		}
 	case InvalidWithdrawals:
 		// These options are not supported yet.
 		// TODO: Implement
@@ -1,9 +1,15 @@
 package helper_test
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
 	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
 )
 
 func TestBlobGeneration(t *testing.T) {
@@ -52,3 +58,44 @@ func TestPrecomputedKZGCommitments(t *testing.T) {
 		}
 	}
 }
+
+func TestCellProofBlobTransaction(t *testing.T) {
+	blobTxCreator := &helper.BlobTransactionCreator{
+		To:         &common.Address{},
+		GasLimit:   100000,
+		BlobID:     1,
+		BlobCount:  2,
+		ForkConfig: &config.ForkConfig{OsakaTimestamp: big.NewInt(0)},
+	}
+	tx, err := blobTxCreator.MakeTransaction(globals.TestAccounts[0], 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobTx := tx.(*typ.TransactionWithBlobData)
+	if err := helper.VerifyCellProofs(blobTx.BlobData.Blobs, blobTx.BlobData.Commitments, blobTx.BlobData.Proofs); err != nil {
+		t.Fatal(err)
+	}
+
+	// The network encoding of the transaction must contain the cell proofs
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded types.Transaction
+	if err := decoded.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := decoded.BlobTxSidecar()
+	if sidecar == nil {
+		t.Fatal("missing sidecar")
+	}
+	if sidecar.Version != types.BlobSidecarVersion1 {
+		t.Fatalf("wrong sidecar version: %d", sidecar.Version)
+	}
+	if len(sidecar.Proofs) != 2*typ.CellsPerExtBlob {
+		t.Fatalf("wrong cell proof count: %d", len(sidecar.Proofs))
+	}
+	if err := sidecar.ValidateBlobCommitmentHashes(decoded.BlobHashes()); err != nil {
+		t.Fatal(err)
+	}
+}
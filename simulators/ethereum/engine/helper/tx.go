@@ -372,19 +372,22 @@ func (tc *BaseTransactionCreator) MakeTransaction(sender SenderAccount, nonce ui
 		}
 
 		// Need tx wrap data that will pass blob verification
-		hashes, blobData, err := BlobDataGenerator(tc.BlobID, blobCount)
+		hashes, blobData, err := BlobDataGeneratorWithVersion(tc.BlobID, blobCount, BlobWrapperVersion(tc.ForkConfig, blockTimestamp))
 		tc.BlobID += BlobID(blobCount)
 		if err != nil {
 			return nil, err
 		}
 		sidecar := &types.BlobTxSidecar{}
 		if blobData != nil {
+			sidecar.Version = blobData.Version
 			sidecar.Blobs = make([]kzg4844.Blob, len(blobData.Blobs))
 			sidecar.Commitments = make([]kzg4844.Commitment, len(blobData.Commitments))
 			sidecar.Proofs = make([]kzg4844.Proof, len(blobData.Proofs))
 			for i := range blobData.Blobs {
 				sidecar.Blobs[i] = kzg4844.Blob(blobData.Blobs[i])
 				sidecar.Commitments[i] = kzg4844.Commitment(blobData.Commitments[i])
+			}
+			for i := range blobData.Proofs {
 				sidecar.Proofs[i] = kzg4844.Proof(blobData.Proofs[i])
 			}
 		}
@@ -474,6 +477,45 @@ func (tc *BigInitcodeTransactionCreator) MakeTransaction(sender SenderAccount, n
 	return tc.BaseTransactionCreator.MakeTransaction(sender, nonce, blockTimestamp)
 }
 
+// Create an EIP-7702 set-code tx with the specified authorizations
+type SetCodeTransactionCreator struct {
+	BaseTransactionCreator
+	AuthorizationList []types.SetCodeAuthorization
+}
+
+func (tc *SetCodeTransactionCreator) MakeTransaction(sender SenderAccount, nonce uint64, blockTimestamp uint64) (typ.Transaction, error) {
+	if tc.Recipient == nil {
+		return nil, errors.New("nil to address for set-code transaction")
+	}
+	var (
+		gasFeeCap = uint256.MustFromBig(globals.GasPrice)
+		gasTipCap = uint256.MustFromBig(globals.GasTipPrice)
+		value     = new(uint256.Int)
+	)
+	if tc.GasFee != nil {
+		gasFeeCap = uint256.MustFromBig(tc.GasFee)
+	}
+	if tc.GasTip != nil {
+		gasTipCap = uint256.MustFromBig(tc.GasTip)
+	}
+	if tc.Amount != nil {
+		value = uint256.MustFromBig(tc.Amount)
+	}
+	tx := types.NewTx(&types.SetCodeTx{
+		ChainID:    uint256.MustFromBig(globals.ChainID),
+		Nonce:      nonce,
+		GasTipCap:  gasTipCap,
+		GasFeeCap:  gasFeeCap,
+		Gas:        tc.GasLimit,
+		To:         *tc.Recipient,
+		Value:      value,
+		Data:       tc.Payload,
+		AccessList: tc.AccessList,
+		AuthList:   tc.AuthorizationList,
+	})
+	return types.SignTx(tx, types.NewPragueSigner(globals.ChainID), sender.GetKey())
+}
+
 // Determines if the error we got from sending the raw tx is because the client
 // already knew the tx (might happen if we produced a re-org where the tx was
 // unwind back into the txpool)
@@ -5,9 +5,11 @@ import (
 	"math/rand"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/hive/hivesim"
 	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
 	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
@@ -15,6 +17,13 @@ import (
 	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
 	suite_engine "github.com/ethereum/hive/simulators/ethereum/engine/suites/engine"
 	suite_excap "github.com/ethereum/hive/simulators/ethereum/engine/suites/exchange_capabilities"
+	suite_fuzz "github.com/ethereum/hive/simulators/ethereum/engine/suites/fuzz"
+	suite_getblobs "github.com/ethereum/hive/simulators/ethereum/engine/suites/getblobs"
+	suite_multiclient "github.com/ethereum/hive/simulators/ethereum/engine/suites/multiclient"
+	suite_osaka "github.com/ethereum/hive/simulators/ethereum/engine/suites/osaka"
+	suite_perf "github.com/ethereum/hive/simulators/ethereum/engine/suites/perf"
+	suite_prague "github.com/ethereum/hive/simulators/ethereum/engine/suites/prague"
+	suite_sync "github.com/ethereum/hive/simulators/ethereum/engine/suites/sync"
 	suite_withdrawals "github.com/ethereum/hive/simulators/ethereum/engine/suites/withdrawals"
 	"github.com/ethereum/hive/simulators/ethereum/engine/test"
 )
@@ -46,39 +55,130 @@ var (
 		Description: `
 	Test Engine API on Cancun.`[1:],
 	}
+	prague = hivesim.Suite{
+		Name: "engine-prague",
+		Description: `
+	Test Engine API on Prague.`[1:],
+	}
+	osaka = hivesim.Suite{
+		Name: "engine-osaka",
+		Description: `
+	Test Engine API on Osaka.`[1:],
+	}
+	getblobs = hivesim.Suite{
+		Name: "engine-getblobs",
+		Description: `
+	Test Engine API getBlobs methods with blobs from the transaction pool.`[1:],
+	}
+	fuzz = hivesim.Suite{
+		Name: "engine-fuzz",
+		Description: `
+	Test Engine API with randomly mutated invalid payloads.`[1:],
+	}
+	multiclient = hivesim.Suite{
+		Name: "engine-multiclient",
+		Description: `
+	Test Engine API block production and re-orgs using one CL mocker to drive pairs of
+	different clients, where each client imports the payloads built by the other.`[1:],
+	}
+	syncing = hivesim.Suite{
+		Name: "engine-sync",
+		Description: `
+	Test Engine API optimistic sync and backfill, with the chain served by an in-process
+	geth peer.`[1:],
+	}
+	perf = hivesim.Suite{
+		Name: "engine-perf",
+		Description: `
+	Measure the latency of the Engine API calls made to build and import payloads.`[1:],
+	}
 )
 
 func main() {
+	engine.Tags = test.SpecTags(suite_engine.Tests)
 	engine.Add(hivesim.TestSpec{
 		Name:        "engine test loader",
 		Description: "",
 		Run:         makeRunner(suite_engine.Tests, "full"),
 		AlwaysRun:   true,
 	})
+	auth.Tags = test.SpecTags(suite_auth.Tests)
 	auth.Add(hivesim.TestSpec{
 		Name:        "engine-auth test loader",
 		Description: "",
 		Run:         makeRunner(suite_auth.Tests, "full"),
 		AlwaysRun:   true,
 	})
+	excap.Tags = test.SpecTags(suite_excap.Tests)
 	excap.Add(hivesim.TestSpec{
 		Name:        "engine-excap test loader",
 		Description: "",
 		Run:         makeRunner(suite_excap.Tests, "full"),
 		AlwaysRun:   true,
 	})
+	withdrawals.Tags = test.SpecTags(suite_withdrawals.Tests)
 	withdrawals.Add(hivesim.TestSpec{
 		Name:        "engine-withdrawals test loader",
 		Description: "",
 		Run:         makeRunner(suite_withdrawals.Tests, "full"),
 		AlwaysRun:   true,
 	})
+	cancun.Tags = test.SpecTags(suite_cancun.Tests)
 	cancun.Add(hivesim.TestSpec{
 		Name:        "engine-cancun test loader",
 		Description: "",
 		Run:         makeRunner(suite_cancun.Tests, "full"),
 		AlwaysRun:   true,
 	})
+	prague.Tags = test.SpecTags(suite_prague.Tests)
+	prague.Add(hivesim.TestSpec{
+		Name:        "engine-prague test loader",
+		Description: "",
+		Run:         makeRunner(suite_prague.Tests, "full"),
+		AlwaysRun:   true,
+	})
+	osaka.Tags = test.SpecTags(suite_osaka.Tests)
+	osaka.Add(hivesim.TestSpec{
+		Name:        "engine-osaka test loader",
+		Description: "",
+		Run:         makeRunner(suite_osaka.Tests, "full"),
+		AlwaysRun:   true,
+	})
+	getblobs.Tags = test.SpecTags(suite_getblobs.Tests)
+	getblobs.Add(hivesim.TestSpec{
+		Name:        "engine-getblobs test loader",
+		Description: "",
+		Run:         makeRunner(suite_getblobs.Tests, "full"),
+		AlwaysRun:   true,
+	})
+	fuzz.Tags = test.SpecTags(suite_fuzz.Tests)
+	fuzz.Add(hivesim.TestSpec{
+		Name:        "engine-fuzz test loader",
+		Description: "",
+		Run:         makeRunner(suite_fuzz.Tests, "full"),
+		AlwaysRun:   true,
+	})
+	multiclient.Tags = test.SpecTags(suite_multiclient.Tests)
+	multiclient.Add(hivesim.TestSpec{
+		Name:        "engine-multiclient test loader",
+		Description: "",
+		Run:         makeClientSetRunner(suite_multiclient.Tests, "full", 2),
+		AlwaysRun:   true,
+	})
+	syncing.Tags = test.SpecTags(suite_sync.Tests)
+	syncing.Add(hivesim.TestSpec{
+		Name:        "engine-sync test loader",
+		Description: "",
+		Run:         makeRunner(suite_sync.Tests, "full"),
+		AlwaysRun:   true,
+	})
+	perf.Tags = test.SpecTags(suite_perf.Tests)
+	perf.Add(hivesim.TestSpec{
+		Name:        "engine-perf test loader",
+		Description: "",
+		Run:         makeRunner(suite_perf.Tests, "full"),
+		AlwaysRun:   true,
+	})
 	simulator := hivesim.New()
 
 	// Mark suites for execution
@@ -87,9 +187,22 @@ func main() {
 	hivesim.MustRunSuite(simulator, excap)
 	hivesim.MustRunSuite(simulator, withdrawals)
 	hivesim.MustRunSuite(simulator, cancun)
+	hivesim.MustRunSuite(simulator, prague)
+	hivesim.MustRunSuite(simulator, osaka)
+	hivesim.MustRunSuite(simulator, getblobs)
+	hivesim.MustRunSuite(simulator, fuzz)
+	hivesim.MustRunSuite(simulator, multiclient)
+	hivesim.MustRunSuite(simulator, syncing)
+	hivesim.MustRunSuite(simulator, perf)
 }
 
 func makeRunner(tests []test.Spec, nodeType string) func(t *hivesim.T) {
+	return makeClientSetRunner(tests, nodeType, 1)
+}
+
+// makeClientSetRunner returns a runner which executes each test once per set of
+// clientSetSize different client types, all driven by the same CL mocker.
+func makeClientSetRunner(tests []test.Spec, nodeType string, clientSetSize int) func(t *hivesim.T) {
 	return func(t *hivesim.T) {
 		parallelism := 16
 		if val, ok := os.LookupEnv("HIVE_PARALLELISM"); ok {
@@ -111,6 +224,26 @@ func makeRunner(tests []test.Spec, nodeType string) func(t *hivesim.T) {
 		}
 		t.Log("random_seed", random_seed)
 
+		clientTypes, err := t.Sim.ClientTypes()
+		if err != nil {
+			t.Fatal("can't get client types:", err)
+		}
+		sets := clientSets(clientTypes, clientSetSize)
+		if len(sets) == 0 {
+			t.Logf("no tests to run: %d different client types are needed, %d given", clientSetSize, len(clientTypes))
+			return
+		}
+
+		// Test selection by fork and tag
+		forks := envList("HIVE_ENGINE_FORKS")
+		tags := envList("HIVE_ENGINE_TAGS")
+		if len(forks) > 0 {
+			t.Log("forks", strings.Join(forks, ","))
+		}
+		if len(tags) > 0 {
+			t.Log("tags", strings.Join(tags, ","), "available tags", strings.Join(test.AllTags, ","))
+		}
+
 		var wg sync.WaitGroup
 		var testCh = make(chan hivesim.TestSpec)
 		wg.Add(parallelism)
@@ -126,6 +259,10 @@ func makeRunner(tests []test.Spec, nodeType string) func(t *hivesim.T) {
 		for _, currentTest := range tests {
 			currentTest := currentTest
 			currentTestName := fmt.Sprintf("%s (%s)", currentTest.GetName(), currentTest.GetMainFork())
+			if !selectTest(currentTest, forks, tags) {
+				t.Logf("skipping test \"%s\" because it does not match the selected forks and tags", currentTestName)
+				continue
+			}
 			// Load the genesis file specified and dynamically bundle it.
 			genesis := currentTest.GetGenesis()
 			forkConfig := currentTest.GetForkConfig()
@@ -149,6 +286,28 @@ func makeRunner(tests []test.Spec, nodeType string) func(t *hivesim.T) {
 				newParams = newParams.Set("HIVE_SHANGHAI_TIMESTAMP", fmt.Sprintf("%d", forkConfig.ShanghaiTimestamp))
 				if forkConfig.CancunTimestamp != nil {
 					newParams = newParams.Set("HIVE_CANCUN_TIMESTAMP", fmt.Sprintf("%d", forkConfig.CancunTimestamp))
+					if forkConfig.PragueTimestamp != nil {
+						newParams = newParams.Set("HIVE_PRAGUE_TIMESTAMP", fmt.Sprintf("%d", forkConfig.PragueTimestamp))
+						if forkConfig.OsakaTimestamp != nil {
+							newParams = newParams.Set("HIVE_OSAKA_TIMESTAMP", fmt.Sprintf("%d", forkConfig.OsakaTimestamp))
+						}
+					}
+				}
+			}
+
+			// Configure the blob schedule.
+			if schedule := genesis.Config.BlobScheduleConfig; schedule != nil {
+				for fork, blobConfig := range map[string]*params.BlobConfig{
+					"CANCUN": schedule.Cancun,
+					"PRAGUE": schedule.Prague,
+					"OSAKA":  schedule.Osaka,
+				} {
+					if blobConfig == nil {
+						continue
+					}
+					newParams = newParams.Set(fmt.Sprintf("HIVE_%s_BLOB_TARGET", fork), fmt.Sprintf("%d", blobConfig.Target))
+					newParams = newParams.Set(fmt.Sprintf("HIVE_%s_BLOB_MAX", fork), fmt.Sprintf("%d", blobConfig.Max))
+					newParams = newParams.Set(fmt.Sprintf("HIVE_%s_BLOB_BASE_FEE_UPDATE_FRACTION", fork), fmt.Sprintf("%d", blobConfig.UpdateFraction))
 				}
 			}
 
@@ -157,42 +316,64 @@ func makeRunner(tests []test.Spec, nodeType string) func(t *hivesim.T) {
 				newParams = newParams.Set("HIVE_NODETYPE", nodeType)
 			}
 
-			if clientTypes, err := t.Sim.ClientTypes(); err == nil {
-				for _, clientType := range clientTypes {
-					test := hivesim.TestSpec{
-						Name:        fmt.Sprintf("%s (%s)", currentTestName, clientType.Name),
-						Description: currentTest.GetAbout(),
-						Run: func(t *hivesim.T) {
-							// Start the client with given options
-							c := t.StartClient(
-								clientType.Name,
-								newParams,
-								genesisStartOption,
-							)
-							t.Logf("Start test (%s): %s", c.Type, currentTestName)
-							defer func() {
-								t.Logf("End test (%s): %s", c.Type, currentTestName)
-							}()
-							timeout := globals.DefaultTestCaseTimeout
-							// If a test.Spec specifies a timeout, use that instead
-							if currentTest.GetTimeout() != 0 {
-								timeout = time.Second * time.Duration(currentTest.GetTimeout())
+			for _, clientSet := range sets {
+				clientSet := clientSet
+				clientNames := make([]string, len(clientSet))
+				for i, clientType := range clientSet {
+					clientNames[i] = clientType.Name
+				}
+				test := hivesim.TestSpec{
+					Name:        fmt.Sprintf("%s (%s)", currentTestName, strings.Join(clientNames, ", ")),
+					Description: currentTest.GetAbout(),
+					Tags:        currentTest.GetTags(),
+					Run: func(t *hivesim.T) {
+						// Start the client with given options
+						c := t.StartClient(
+							clientSet[0].Name,
+							newParams,
+							genesisStartOption,
+						)
+						t.Logf("Start test (%s): %s", c.Type, currentTestName)
+						defer func() {
+							t.Logf("End test (%s): %s", c.Type, currentTestName)
+						}()
+						// Start the rest of the clients of the set, peered to the first one
+						// so they share the transactions sent during the test
+						peers := make([]*hivesim.Client, 0, len(clientSet)-1)
+						if len(clientSet) > 1 {
+							enode, err := c.EnodeURL()
+							if err != nil {
+								t.Fatalf("FAIL: Unable to get enode of client %s: %v", c.Type, err)
 							}
-							// Run the test case
-							test.Run(
-								currentTest,
-								timeout,
-								t,
-								c,
-								genesis,
-								rand.New(rand.NewSource(random_seed)),
-								newParams,
-								hivesim.Params{},
-							)
-						},
-					}
-					testCh <- test
+							peerParams := newParams.Set("HIVE_BOOTNODE", enode)
+							for _, clientType := range clientSet[1:] {
+								peers = append(peers, t.StartClient(
+									clientType.Name,
+									peerParams,
+									genesisStartOption,
+								))
+							}
+						}
+						timeout := globals.DefaultTestCaseTimeout
+						// If a test.Spec specifies a timeout, use that instead
+						if currentTest.GetTimeout() != 0 {
+							timeout = time.Second * time.Duration(currentTest.GetTimeout())
+						}
+						// Run the test case
+						test.Run(
+							currentTest,
+							timeout,
+							t,
+							c,
+							genesis,
+							rand.New(rand.NewSource(random_seed)),
+							newParams,
+							hivesim.Params{},
+							peers...,
+						)
+					},
 				}
+				testCh <- test
 			}
 		}
 
@@ -201,3 +382,55 @@ func makeRunner(tests []test.Spec, nodeType string) func(t *hivesim.T) {
 		defer wg.Wait()
 	}
 }
+
+// clientSets returns all combinations of size different client types, in the
+// order the client types were given. Each client type is its own set when size is 1.
+func clientSets(clientTypes []*hivesim.ClientDefinition, size int) [][]*hivesim.ClientDefinition {
+	if size <= 0 || size > len(clientTypes) {
+		return nil
+	}
+	if size == 1 {
+		sets := make([][]*hivesim.ClientDefinition, len(clientTypes))
+		for i, clientType := range clientTypes {
+			sets[i] = []*hivesim.ClientDefinition{clientType}
+		}
+		return sets
+	}
+	var sets [][]*hivesim.ClientDefinition
+	for i, clientType := range clientTypes {
+		for _, rest := range clientSets(clientTypes[i+1:], size-1) {
+			sets = append(sets, append([]*hivesim.ClientDefinition{clientType}, rest...))
+		}
+	}
+	return sets
+}
+
+// envList returns the comma-separated values of the environment variable, or nil
+// if the variable is not set.
+func envList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// selectTest returns whether the test runs on one of the forks and has one of
+// the tags given. An empty list selects all the tests.
+func selectTest(spec test.Spec, forks []string, tags []string) bool {
+	if len(forks) > 0 {
+		forkSelected := false
+		for _, fork := range forks {
+			if strings.EqualFold(fork, string(spec.GetMainFork())) {
+				forkSelected = true
+				break
+			}
+		}
+		if !forkSelected {
+			return false
+		}
+	}
+	return len(tags) == 0 || test.HasAnyTag(spec, tags)
+}
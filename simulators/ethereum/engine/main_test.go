@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/hive/hivesim"
+	suite_fuzz "github.com/ethereum/hive/simulators/ethereum/engine/suites/fuzz"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// This test runs a suite in docs mode, and checks that the tags used by the tests of the
+// suite, and the tags of the test cases, are listed in the generated documentation.
+func TestDocsTags(t *testing.T) {
+	outdir := t.TempDir()
+	t.Setenv("HIVE_DOCS_MODE", "true")
+	t.Setenv("HIVE_DOCS_OUTPUT_DIR", outdir)
+	t.Setenv("HIVE_SIMULATOR_NAME", "ethereum/engine")
+
+	suite := fuzz
+	suite.Tags = test.SpecTags(suite_fuzz.Tests)
+	suite.Add(hivesim.TestSpec{
+		Name:      "engine-fuzz test loader",
+		Run:       makeRunner(suite_fuzz.Tests, "full"),
+		AlwaysRun: true,
+	})
+	if err := hivesim.RunSuite(hivesim.New(), suite); err != nil {
+		t.Fatal(err)
+	}
+
+	markdown, err := os.ReadFile(filepath.Join(outdir, "TESTS-ENGINE-FUZZ.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the tags of the fuzz tests should be listed.
+	for _, tag := range test.AllTags {
+		used := tag == test.TagFuzz || tag == test.TagInvalidPayload
+		if listed := strings.Contains(string(markdown), fmt.Sprintf("- `%s`: ", tag)); listed != used {
+			t.Errorf("suite tag %q: listed in docs = %t, want %t", tag, listed, used)
+		}
+	}
+	exp := fmt.Sprintf("- `%s`: %d test cases\n", test.TagFuzz, len(suite_fuzz.Tests))
+	if !strings.Contains(string(markdown), exp) {
+		t.Errorf("expected %q in docs:\n%s", exp, markdown)
+	}
+	if !strings.Contains(string(markdown), "Tags: `fuzz`") {
+		t.Errorf("test case tags not listed in docs:\n%s", markdown)
+	}
+}
@@ -0,0 +1,187 @@
+package suite_auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Dials the engine endpoint of the main client over HTTP or WebSocket, using the
+// given authentication on each HTTP request or on the WebSocket handshake.
+func dialEngine(t *test.Env, webSocket bool, auth rpc.HTTPAuth) (*rpc.Client, error) {
+	scheme := "http"
+	if webSocket {
+		scheme = "ws"
+	}
+	ctx, cancel := context.WithTimeout(t.TestContext, globals.RPCTimeout)
+	defer cancel()
+	return rpc.DialOptions(ctx, fmt.Sprintf("%s://%s:%d/", scheme, t.Client.IP, globals.EnginePortHTTP), rpc.WithHTTPAuth(auth))
+}
+
+// Sends a simple engine_exchangeCapabilities request to check the authentication,
+// and returns the resulting error.
+func callWithAuth(t *test.Env, webSocket bool, auth rpc.HTTPAuth) error {
+	c, err := dialEngine(t, webSocket, auth)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(t.TestContext, globals.RPCTimeout)
+	defer cancel()
+	var result []string
+	return c.CallContext(ctx, &result, "engine_exchangeCapabilities", []string{})
+}
+
+// Returns whether the client accepts WebSocket connections with a valid token on the
+// engine port. Handshakes rejected as unauthorized fail the test.
+func webSocketSupported(t *test.Env) bool {
+	c, err := dialEngine(t, true, TokenConfig{}.HTTPAuth())
+	if err != nil {
+		if strings.Contains(err.Error(), "HTTP status 401") || strings.Contains(err.Error(), "HTTP status 403") {
+			t.Fatalf("FAIL (%s): WebSocket handshake with a valid token was rejected: %v", t.TestName, err)
+		}
+		t.Logf("INFO (%s): Client does not support the Engine API over WebSocket: %v", t.TestName, err)
+		return false
+	}
+	c.Close()
+	return true
+}
+
+// Waits for the given duration, or fails the test on timeout.
+func wait(t *test.Env, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-t.TimeoutContext.Done():
+		t.Fatalf("FAIL (%s): Timeout while waiting", t.TestName)
+	}
+}
+
+// Test that sends a token with custom claims or signature, over HTTP or WebSocket.
+type AuthTokenTestSpec struct {
+	test.BaseSpec
+	Token         TokenConfig
+	WebSocket     bool
+	AuthOk        bool
+	RetryAttempts int64
+}
+
+func (s AuthTokenTestSpec) Execute(t *test.Env) {
+	if s.WebSocket && !webSocketSupported(t) {
+		return
+	}
+	// Time drift test cases are reattempted in order to mitigate false negatives
+	retryAttemptsLeft := s.RetryAttempts
+	for {
+		err := callWithAuth(t, s.WebSocket, s.Token.HTTPAuth())
+		if (s.AuthOk && err == nil) || (!s.AuthOk && err != nil) {
+			// Test passed
+			return
+		}
+		if retryAttemptsLeft == 0 {
+			if err != nil {
+				t.Fatalf("FAIL (%s): Authentication was supposed to pass authentication but failed: %v", t.TestName, err)
+			} else {
+				t.Fatalf("FAIL (%s): Authentication was supposed to fail authentication but passed", t.TestName)
+			}
+		}
+		retryAttemptsLeft--
+		wait(t, time.Second)
+	}
+}
+
+func (s AuthTokenTestSpec) WithMainFork(fork config.Fork) test.Spec {
+	specCopy := s
+	specCopy.MainFork = fork
+	return specCopy
+}
+
+func (s AuthTokenTestSpec) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagAuth)
+}
+
+// Test that sends the same token on several requests, while its iat is within the
+// allowed time drift and once it is outside of it.
+type TokenReuseTestSpec struct {
+	test.BaseSpec
+	// Number of requests sent with the token while it is valid
+	ReuseCount int
+}
+
+func (s TokenReuseTestSpec) Execute(t *test.Env) {
+	// The token is created close to the end of its validity, to shorten the wait
+	iat := time.Now().Add(-time.Duration(globals.MaxTimeDriftSeconds-10) * time.Second)
+	token, err := TokenConfig{}.NewToken(iat)
+	if err != nil {
+		t.Fatalf("FAIL (%s): Unable to create the token: %v", t.TestName, err)
+	}
+	auth := FixedTokenAuth(token)
+	for i := 0; i < s.ReuseCount; i++ {
+		if err := callWithAuth(t, false, auth); err != nil {
+			t.Fatalf("FAIL (%s): Token reused within the allowed time drift was rejected on request %d: %v", t.TestName, i+1, err)
+		}
+	}
+
+	// Wait until the iat of the token is outside the allowed time drift
+	wait(t, time.Until(iat.Add(time.Duration(globals.MaxTimeDriftSeconds+2)*time.Second)))
+	for retryAttemptsLeft := 5; ; retryAttemptsLeft-- {
+		if err := callWithAuth(t, false, auth); err != nil {
+			// Test passed
+			return
+		}
+		if retryAttemptsLeft == 0 {
+			t.Fatalf("FAIL (%s): Token reused outside the allowed time drift was accepted", t.TestName)
+		}
+		wait(t, time.Second)
+	}
+}
+
+func (s TokenReuseTestSpec) WithMainFork(fork config.Fork) test.Spec {
+	specCopy := s
+	specCopy.MainFork = fork
+	return specCopy
+}
+
+func (s TokenReuseTestSpec) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagAuth)
+}
+
+// Test that restarts the client with a new secret file, and verifies that the new
+// secret is loaded and the old one is no longer accepted.
+type ClientRestartTestSpec struct {
+	test.BaseSpec
+}
+
+func (s ClientRestartTestSpec) Execute(t *test.Env) {
+	if err := callWithAuth(t, false, TokenConfig{}.HTTPAuth()); err != nil {
+		t.Fatalf("FAIL (%s): Authentication failed before the restart: %v", t.TestName, err)
+	}
+
+	newSecret := make([]byte, 32)
+	t.Rand.Read(newSecret)
+	if err := t.RestartClient(newSecret); err != nil {
+		t.Fatalf("FAIL (%s): Unable to restart the client: %v", t.TestName, err)
+	}
+
+	if err := callWithAuth(t, false, TokenConfig{Secret: newSecret}.HTTPAuth()); err != nil {
+		t.Fatalf("FAIL (%s): Authentication with the new secret failed after the restart: %v", t.TestName, err)
+	}
+	if err := callWithAuth(t, false, TokenConfig{}.HTTPAuth()); err == nil {
+		t.Fatalf("FAIL (%s): Authentication with the old secret passed after the restart", t.TestName)
+	}
+}
+
+func (s ClientRestartTestSpec) WithMainFork(fork config.Fork) test.Spec {
+	specCopy := s
+	specCopy.MainFork = fork
+	return specCopy
+}
+
+func (s ClientRestartTestSpec) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagAuth)
+}
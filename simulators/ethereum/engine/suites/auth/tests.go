@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/hive/simulators/ethereum/engine/config"
 	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
 	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+	"github.com/golang-jwt/jwt/v4"
 )
 
 // JWT Authentication Tests
@@ -72,6 +73,120 @@ var Tests = []test.Spec{
 		AuthOk:                true,
 		RetryAttempts:         5,
 	},
+
+	// Claims
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Missing iat claim",
+		},
+		Token: TokenConfig{
+			OmitIat: true,
+		},
+		AuthOk: false,
+	},
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Negative time drift, one hour, correct secret",
+		},
+		Token: TokenConfig{
+			TimeDriftSeconds: -3600,
+		},
+		AuthOk: false,
+	},
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Positive time drift, one hour, correct secret",
+		},
+		Token: TokenConfig{
+			TimeDriftSeconds: 3600,
+		},
+		AuthOk: false,
+	},
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Optional id and clv claims",
+		},
+		Token: TokenConfig{
+			ExtraClaims: jwt.MapClaims{
+				"id":  "hive-engine-simulator",
+				"clv": "hive/v1.0.0",
+			},
+		},
+		AuthOk: true,
+	},
+
+	// Algorithms
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Unsigned token (alg none)",
+		},
+		Token: TokenConfig{
+			SigningMethod: jwt.SigningMethodNone,
+		},
+		AuthOk: false,
+	},
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Unsupported algorithm (RS256)",
+		},
+		Token: TokenConfig{
+			SigningMethod: jwt.SigningMethodRS256,
+		},
+		AuthOk: false,
+	},
+
+	// Token reuse and client restart
+	TokenReuseTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Token reuse",
+		},
+		ReuseCount: 3,
+	},
+	ClientRestartTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: Client restart",
+		},
+	},
+
+	// WebSocket
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: WebSocket, no time drift, correct secret",
+		},
+		WebSocket: true,
+		AuthOk:    true,
+	},
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: WebSocket, no time drift, incorrect secret",
+		},
+		Token: TokenConfig{
+			Secret: []byte("secretsecretsecretsecretsecrets"),
+		},
+		WebSocket: true,
+		AuthOk:    false,
+	},
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: WebSocket, positive time drift, exceeding limit, correct secret",
+		},
+		Token: TokenConfig{
+			TimeDriftSeconds: globals.MaxTimeDriftSeconds + 1,
+		},
+		WebSocket:     true,
+		AuthOk:        false,
+		RetryAttempts: 5,
+	},
+	AuthTokenTestSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "JWT Authentication: WebSocket, unsigned token (alg none)",
+		},
+		Token: TokenConfig{
+			SigningMethod: jwt.SigningMethodNone,
+		},
+		WebSocket: true,
+		AuthOk:    false,
+	},
 }
 
 type AuthTestSpec struct {
@@ -130,3 +245,7 @@ func (s AuthTestSpec) WithMainFork(fork config.Fork) test.Spec {
 	specCopy.MainFork = fork
 	return specCopy
 }
+
+func (s AuthTestSpec) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagAuth)
+}
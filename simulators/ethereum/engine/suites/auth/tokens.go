@@ -0,0 +1,98 @@
+package suite_auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	rsaKey     *rsa.PrivateKey
+	rsaKeyErr  error
+	rsaKeyOnce sync.Once
+)
+
+// Returns the RSA key used to sign RS256 tokens, generated once per simulator run.
+func getRSAKey() (*rsa.PrivateKey, error) {
+	rsaKeyOnce.Do(func() {
+		rsaKey, rsaKeyErr = rsa.GenerateKey(rand.Reader, 2048)
+	})
+	return rsaKey, rsaKeyErr
+}
+
+// Claims and signature of the JWT tokens sent to the client.
+type TokenConfig struct {
+	// Signing method of the token, HS256 if nil
+	SigningMethod jwt.SigningMethod
+	// Secret used to sign HMAC tokens, the default secret if nil
+	Secret []byte
+	// Whether the iat claim is omitted
+	OmitIat bool
+	// Time drift of the iat claim from the time the token is created
+	TimeDriftSeconds int64
+	// Claims of the token besides iat
+	ExtraClaims jwt.MapClaims
+}
+
+func (c TokenConfig) signingKey() (interface{}, error) {
+	switch c.SigningMethod {
+	case nil, jwt.SigningMethodHS256, jwt.SigningMethodHS384, jwt.SigningMethodHS512:
+		if c.Secret == nil {
+			return globals.DefaultJwtTokenSecretBytes, nil
+		}
+		return c.Secret, nil
+	case jwt.SigningMethodNone:
+		return jwt.UnsafeAllowNoneSignatureType, nil
+	case jwt.SigningMethodRS256:
+		return getRSAKey()
+	default:
+		return nil, fmt.Errorf("unsupported signing method %s", c.SigningMethod.Alg())
+	}
+}
+
+// Creates a new token with the iat claim relative to the given time.
+func (c TokenConfig) NewToken(now time.Time) (string, error) {
+	claims := jwt.MapClaims{}
+	for k, v := range c.ExtraClaims {
+		claims[k] = v
+	}
+	if !c.OmitIat {
+		claims["iat"] = now.Add(time.Duration(c.TimeDriftSeconds) * time.Second).Unix()
+	}
+	method := c.SigningMethod
+	if method == nil {
+		method = jwt.SigningMethodHS256
+	}
+	key, err := c.signingKey()
+	if err != nil {
+		return "", err
+	}
+	return jwt.NewWithClaims(method, claims).SignedString(key)
+}
+
+// Returns an authentication provider which creates a new token on each request.
+func (c TokenConfig) HTTPAuth() rpc.HTTPAuth {
+	return func(h http.Header) error {
+		token, err := c.NewToken(time.Now())
+		if err != nil {
+			return err
+		}
+		h.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// Returns an authentication provider which sends the same token on every request.
+func FixedTokenAuth(token string) rpc.HTTPAuth {
+	return func(h http.Header) error {
+		h.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package suite_auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestTokenConfig(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		config TokenConfig
+		alg    string
+		claims jwt.MapClaims
+	}{
+		{
+			config: TokenConfig{},
+			alg:    "HS256",
+			claims: jwt.MapClaims{"iat": float64(now.Unix())},
+		},
+		{
+			config: TokenConfig{TimeDriftSeconds: -61, ExtraClaims: jwt.MapClaims{"id": "a", "clv": "b"}},
+			alg:    "HS256",
+			claims: jwt.MapClaims{"iat": float64(now.Unix() - 61), "id": "a", "clv": "b"},
+		},
+		{
+			config: TokenConfig{OmitIat: true},
+			alg:    "HS256",
+			claims: jwt.MapClaims{},
+		},
+		{
+			config: TokenConfig{SigningMethod: jwt.SigningMethodNone},
+			alg:    "none",
+			claims: jwt.MapClaims{"iat": float64(now.Unix())},
+		},
+		{
+			config: TokenConfig{SigningMethod: jwt.SigningMethodRS256},
+			alg:    "RS256",
+			claims: jwt.MapClaims{"iat": float64(now.Unix())},
+		},
+	}
+	for i, test := range tests {
+		token, err := test.config.NewToken(now)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		claims := jwt.MapClaims{}
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, claims)
+		if err != nil {
+			t.Fatalf("test %d: unable to parse token: %v", i, err)
+		}
+		if alg := parsed.Header["alg"]; alg != test.alg {
+			t.Errorf("test %d: wrong alg %v, want %s", i, alg, test.alg)
+		}
+		if len(claims) != len(test.claims) {
+			t.Errorf("test %d: wrong claims %v, want %v", i, claims, test.claims)
+		}
+		for k, v := range test.claims {
+			if claims[k] != v {
+				t.Errorf("test %d: wrong claim %s: %v, want %v", i, k, claims[k], v)
+			}
+		}
+	}
+
+	// HS256 tokens must verify with the default secret
+	token, err := TokenConfig{}.NewToken(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return globals.DefaultJwtTokenSecretBytes, nil }); err != nil {
+		t.Fatalf("token does not verify with the default secret: %v", err)
+	}
+}
+
+func TestFixedTokenAuth(t *testing.T) {
+	auth := FixedTokenAuth("token")
+	for i := 0; i < 2; i++ {
+		h := http.Header{}
+		if err := auth(h); err != nil {
+			t.Fatal(err)
+		}
+		if got := h.Get("Authorization"); got != "Bearer token" {
+			t.Fatalf("wrong header: %q", got)
+		}
+	}
+}
@@ -14,22 +14,54 @@ type CancunBaseSpec struct {
 	TestSequence
 }
 
-// Base test case execution procedure for blobs tests.
-func (cs *CancunBaseSpec) Execute(t *test.Env) {
-	blobTestCtx := NewTestContext(t)
-	defer blobTestCtx.Close()
+// Returns the tags implied by the steps of the sequence.
+func (s TestSequence) StepTags() []string {
+	tags := make([]string, 0)
+	var addStepTags func(steps []TestStep)
+	addStepTags = func(steps []TestStep) {
+		for _, step := range steps {
+			switch step := step.(type) {
+			case ParallelSteps:
+				addStepTags(step.Steps)
+			case SendBlobTransactions, SendRejectedBlobTransaction:
+				tags = test.AppendTags(tags, test.TagBlob)
+			case SendModifiedLatestPayload:
+				tags = test.AppendTags(tags, test.TagInvalidPayload)
+			case NewPayloads:
+				if step.NewPayloadCustomizer != nil && step.NewPayloadCustomizer.GetExpectInvalidStatus() {
+					tags = test.AppendTags(tags, test.TagInvalidPayload)
+				}
+			}
+		}
+	}
+	addStepTags(s)
+	return tags
+}
 
-	blobTestCtx.TestBlobTxPool.HashesByIndex = make(map[uint64]common.Hash)
+// Executes the steps of the sequence in order, on a new test context.
+func (s TestSequence) Run(t *test.Env) {
+	testCtx := NewTestContext(t)
+	defer testCtx.Close()
 
-	if cs.GetPayloadDelay != 0 {
-		t.CLMock.PayloadProductionClientDelay = time.Duration(cs.GetPayloadDelay) * time.Second
-	}
+	testCtx.TestBlobTxPool.HashesByIndex = make(map[uint64]common.Hash)
 
-	for stepId, step := range cs.TestSequence {
+	for stepId, step := range s {
 		t.Logf("INFO: Executing step %d: %s", stepId+1, step.Description())
-		if err := step.Execute(blobTestCtx); err != nil {
+		if err := step.Execute(testCtx); err != nil {
 			t.Fatalf("FAIL: Error executing step %d: %v", stepId+1, err)
 		}
 	}
+}
+
+// Derives the tags of the test from the steps of its sequence.
+func (cs *CancunBaseSpec) GetTags() []string {
+	return test.AppendTags(cs.BaseSpec.GetTags(), cs.TestSequence.StepTags()...)
+}
 
+// Base test case execution procedure for blobs tests.
+func (cs *CancunBaseSpec) Execute(t *test.Env) {
+	if cs.GetPayloadDelay != 0 {
+		t.CLMock.PayloadProductionClientDelay = time.Duration(cs.GetPayloadDelay) * time.Second
+	}
+	cs.TestSequence.Run(t)
 }
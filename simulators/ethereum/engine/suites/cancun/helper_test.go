@@ -1,9 +1,11 @@
 package suite_cancun
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
 )
 
 func TestBeaconRootStorageIndexes(t *testing.T) {
@@ -20,3 +22,26 @@ func TestBeaconRootStorageIndexes(t *testing.T) {
 		t.Fatal("expected root key to be", expectedRootKey.Hex(), "got", gotRootKey.Hex())
 	}
 }
+
+func TestEncodeExecutionRequests(t *testing.T) {
+	requests := map[byte][][]byte{
+		prague.CONSOLIDATION_REQUEST_TYPE: {{0x05}},
+		prague.DEPOSIT_REQUEST_TYPE:       {{0x01}, {0x02}},
+	}
+	encoded := EncodeExecutionRequests(requests)
+	expected := [][]byte{
+		{prague.DEPOSIT_REQUEST_TYPE, 0x01, 0x02},
+		{prague.CONSOLIDATION_REQUEST_TYPE, 0x05},
+	}
+	if len(encoded) != len(expected) {
+		t.Fatalf("wrong number of requests: %d", len(encoded))
+	}
+	for i := range expected {
+		if !bytes.Equal(encoded[i], expected[i]) {
+			t.Fatalf("request %d mismatch: want %x, got %x", i, expected[i], encoded[i])
+		}
+	}
+	if encoded := EncodeExecutionRequests(nil); encoded == nil || len(encoded) != 0 {
+		t.Fatalf("expected empty non-nil list, got %v", encoded)
+	}
+}
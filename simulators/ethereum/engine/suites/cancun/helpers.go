@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -60,6 +61,29 @@ func CalcExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed uint64) uint64 {
 	}
 }
 
+// Encodes the requests of each type as the execution requests list of
+// engine_newPayloadV4: the requests are ordered by request type, and types
+// without requests are omitted.
+func EncodeExecutionRequests(requests map[byte][][]byte) [][]byte {
+	requestTypes := make([]byte, 0, len(requests))
+	for requestType := range requests {
+		requestTypes = append(requestTypes, requestType)
+	}
+	slices.Sort(requestTypes)
+	encoded := make([][]byte, 0)
+	for _, requestType := range requestTypes {
+		if len(requests[requestType]) == 0 {
+			continue
+		}
+		encodedType := []byte{requestType}
+		for _, r := range requests[requestType] {
+			encodedType = append(encodedType, r...)
+		}
+		encoded = append(encoded, encodedType)
+	}
+	return encoded
+}
+
 type TestBlobTxPool struct {
 	Mutex                   sync.Mutex
 	CurrentBlobID           helper.BlobID
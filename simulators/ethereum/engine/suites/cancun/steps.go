@@ -11,7 +11,9 @@ import (
 
 	api "github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/hive/simulators/ethereum/engine/client"
 	"github.com/ethereum/hive/simulators/ethereum/engine/clmock"
 	"github.com/ethereum/hive/simulators/ethereum/engine/config"
@@ -28,6 +30,16 @@ type TestContext struct {
 	*test.Env
 	*TestBlobTxPool
 	DevP2PConnections map[uint64]*devp2p.Conn
+
+	// Transactions which must be included in the next payload
+	PendingTransactions []typ.Transaction
+	// Execution requests expected in the next payload, by request type
+	PendingRequests map[byte][][]byte
+	// Verifications of the effects of the pending transactions, run once the next
+	// payload is the head of the chain
+	PendingVerifications []func(t *TestContext) error
+	// Number of execution requests sent so far, by request type
+	RequestCount map[byte]uint64
 }
 
 // Initializes a TestContext
@@ -36,9 +48,26 @@ func NewTestContext(env *test.Env) *TestContext {
 		Env:               env,
 		TestBlobTxPool:    new(TestBlobTxPool),
 		DevP2PConnections: make(map[uint64]*devp2p.Conn),
+		PendingRequests:   make(map[byte][][]byte),
+		RequestCount:      make(map[byte]uint64),
 	}
 }
 
+// Adds a transaction that produces an execution request, which is expected in the
+// next payload.
+func (t *TestContext) AddPendingRequest(tx typ.Transaction, requestType byte, requestData []byte) {
+	t.PendingTransactions = append(t.PendingTransactions, tx)
+	t.PendingRequests[requestType] = append(t.PendingRequests[requestType], requestData)
+	t.RequestCount[requestType]++
+}
+
+// Clears the expectations on the next payload, after it was produced.
+func (t *TestContext) ClearPending() {
+	t.PendingTransactions = nil
+	t.PendingRequests = make(map[byte][][]byte)
+	t.PendingVerifications = nil
+}
+
 // Performs TestContext clean up
 func (t *TestContext) Close() error {
 	for _, conn := range t.DevP2PConnections {
@@ -214,7 +243,9 @@ type BlobWrapData struct {
 	VersionedHash common.Hash
 	KZG           typ.KZGCommitment
 	Blob          typ.Blob
-	Proof         typ.KZGProof
+	// Proof of the blob, or its cell proofs if the transaction was sent with
+	// a version 1 wrapper
+	Proofs []typ.KZGProof
 }
 
 func GetBlobDataInPayload(pool *TestBlobTxPool, payload *typ.ExecutableData) ([]*typ.TransactionWithBlobData, []*BlobWrapData, error) {
@@ -257,7 +288,11 @@ func GetBlobDataInPayload(pool *TestBlobTxPool, payload *typ.ExecutableData) ([]
 					versionedHashes = blobTx.BlobHashes()
 				)
 
-				if len(versionedHashes) != len(kzgs) || len(kzgs) != len(blobs) || len(blobs) != len(proofs) {
+				proofsPerBlob := 1
+				if blobTx.BlobData.Version == typ.BlobTxWrapperVersion1 {
+					proofsPerBlob = typ.CellsPerExtBlob
+				}
+				if len(versionedHashes) != len(kzgs) || len(kzgs) != len(blobs) || len(blobs)*proofsPerBlob != len(proofs) {
 					return nil, nil, fmt.Errorf("invalid blob wrap data")
 				}
 				for i := 0; i < len(versionedHashes); i++ {
@@ -265,7 +300,7 @@ func GetBlobDataInPayload(pool *TestBlobTxPool, payload *typ.ExecutableData) ([]
 						VersionedHash: versionedHashes[i],
 						KZG:           kzgs[i],
 						Blob:          blobs[i],
-						Proof:         proofs[i],
+						Proofs:        proofs[i*proofsPerBlob : (i+1)*proofsPerBlob],
 					})
 				}
 				blobTxsInPayload = append(blobTxsInPayload, blobTx)
@@ -299,22 +334,29 @@ func VerifyBeaconRootStorage(ctx context.Context, testEngine *test.TestEngineCli
 	return nil
 }
 
-func (step NewPayloads) VerifyPayload(ctx context.Context, forkConfig *config.ForkConfig, testEngine *test.TestEngineClient, blobTxsInPayload []*typ.TransactionWithBlobData, shouldOverrideBuilder *bool, payload *typ.ExecutableData, previousPayload *typ.ExecutableData) error {
-	var (
-		parentExcessBlobGas = uint64(0)
-		parentBlobGasUsed   = uint64(0)
-	)
-	if previousPayload != nil {
-		if previousPayload.ExcessBlobGas != nil {
-			parentExcessBlobGas = *previousPayload.ExcessBlobGas
-		}
-		if previousPayload.BlobGasUsed != nil {
-			parentBlobGasUsed = *previousPayload.BlobGasUsed
-		}
+// Returns the excess blob gas of a payload built on top of the given parent, using
+// the blob schedule of the chain config.
+func ExpectedExcessBlobGas(chainConfig *params.ChainConfig, parent *typ.ExecutableData, timestamp uint64) uint64 {
+	if parent == nil {
+		return 0
+	}
+	parentHeader := &types.Header{
+		Number:        new(big.Int).SetUint64(parent.Number),
+		Time:          parent.Timestamp,
+		BaseFee:       parent.BaseFeePerGas,
+		ExcessBlobGas: parent.ExcessBlobGas,
+		BlobGasUsed:   parent.BlobGasUsed,
+	}
+	if parentHeader.ExcessBlobGas == nil || parentHeader.BlobGasUsed == nil {
+		// Parent is a pre-Cancun payload
+		parentHeader.ExcessBlobGas, parentHeader.BlobGasUsed = nil, nil
 	}
-	expectedExcessBlobGas := CalcExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed)
+	return eip4844.CalcExcessBlobGas(chainConfig, parentHeader, timestamp)
+}
 
+func (step NewPayloads) VerifyPayload(ctx context.Context, forkConfig *config.ForkConfig, chainConfig *params.ChainConfig, testEngine *test.TestEngineClient, blobTxsInPayload []*typ.TransactionWithBlobData, shouldOverrideBuilder *bool, payload *typ.ExecutableData, previousPayload *typ.ExecutableData) error {
 	if forkConfig.IsCancun(payload.Timestamp) {
+		expectedExcessBlobGas := ExpectedExcessBlobGas(chainConfig, previousPayload, payload.Timestamp)
 		if payload.ExcessBlobGas == nil {
 			return fmt.Errorf("payload contains nil excessDataGas")
 		}
@@ -330,7 +372,10 @@ func (step NewPayloads) VerifyPayload(ctx context.Context, forkConfig *config.Fo
 		}
 
 		totalBlobCount := uint64(0)
-		expectedBlobGasPrice := new(big.Int).SetUint64(GetBlobGasPrice(expectedExcessBlobGas))
+		expectedBlobGasPrice := eip4844.CalcBlobFee(chainConfig, &types.Header{
+			Time:          payload.Timestamp,
+			ExcessBlobGas: &expectedExcessBlobGas,
+		})
 
 		for _, tx := range blobTxsInPayload {
 			blobCount := uint64(len(tx.BlobHashes()))
@@ -363,9 +408,14 @@ func (step NewPayloads) VerifyPayload(ctx context.Context, forkConfig *config.Fo
 	return nil
 }
 
-func (step NewPayloads) VerifyBlobBundle(blobDataInPayload []*BlobWrapData, payload *typ.ExecutableData, blobBundle *typ.BlobsBundle) error {
-
-	if len(blobBundle.Blobs) != len(blobBundle.Commitments) || len(blobBundle.Blobs) != len(blobBundle.Proofs) {
+// Verifies the blobs bundle of a built payload: the bundle must contain a single
+// proof per blob before Osaka, and the cell proofs of each blob after Osaka.
+func (step NewPayloads) VerifyBlobBundle(forkConfig *config.ForkConfig, blobDataInPayload []*BlobWrapData, payload *typ.ExecutableData, blobBundle *typ.BlobsBundle) error {
+	proofsPerBlob := 1
+	if forkConfig.IsOsaka(payload.Timestamp) {
+		proofsPerBlob = typ.CellsPerExtBlob
+	}
+	if len(blobBundle.Blobs) != len(blobBundle.Commitments) || len(blobBundle.Blobs)*proofsPerBlob != len(blobBundle.Proofs) {
 		return fmt.Errorf("unexpected length in blob bundle: %d blobs, %d proofs, %d commitments", len(blobBundle.Blobs), len(blobBundle.Proofs), len(blobBundle.Commitments))
 	}
 	if len(blobBundle.Blobs) != int(step.ExpectedIncludedBlobCount) {
@@ -381,15 +431,32 @@ func (step NewPayloads) VerifyBlobBundle(blobDataInPayload []*BlobWrapData, payl
 	for i, blobData := range blobDataInPayload {
 		bundleCommitment := blobBundle.Commitments[i]
 		bundleBlob := blobBundle.Blobs[i]
-		bundleProof := blobBundle.Proofs[i]
+		bundleProofs := blobBundle.Proofs[i*proofsPerBlob : (i+1)*proofsPerBlob]
 		if !bytes.Equal(bundleCommitment[:], blobData.KZG[:]) {
 			return fmt.Errorf("KZG mismatch at index %d of the bundle", i)
 		}
 		if !bytes.Equal(bundleBlob[:], blobData.Blob[:]) {
 			return fmt.Errorf("blob mismatch at index %d of the bundle", i)
 		}
-		if !bytes.Equal(bundleProof[:], blobData.Proof[:]) {
-			return fmt.Errorf("proof mismatch at index %d of the bundle", i)
+		if len(blobData.Proofs) == proofsPerBlob {
+			for j := range bundleProofs {
+				if !bytes.Equal(bundleProofs[j][:], blobData.Proofs[j][:]) {
+					return fmt.Errorf("proof mismatch at index %d of the bundle", i*proofsPerBlob+j)
+				}
+			}
+		} else if proofsPerBlob == 1 {
+			// The transaction was sent with cell proofs, verify the proof computed
+			// by the client instead
+			if err := helper.VerifyBlobProof(&bundleBlob, &bundleCommitment, &bundleProofs[0]); err != nil {
+				return fmt.Errorf("invalid proof at index %d of the bundle: %v", i, err)
+			}
+		}
+	}
+	if proofsPerBlob > 1 {
+		// The cell proofs computed by the client for transactions sent with a
+		// single proof per blob are only verified here
+		if err := helper.VerifyCellProofs(blobBundle.Blobs, blobBundle.Commitments, blobBundle.Proofs); err != nil {
+			return fmt.Errorf("invalid cell proofs: %v", err)
 		}
 	}
 
@@ -414,6 +481,30 @@ func (step NewPayloads) VerifyBlobBundle(blobDataInPayload []*BlobWrapData, payl
 	return nil
 }
 
+// Verifies the execution requests of a built payload against the requests of the
+// transactions sent since the previous payload.
+func VerifyExecutionRequests(t *TestContext, payload *typ.ExecutableData) error {
+	if !t.ForkConfig.IsPrague(payload.Timestamp) {
+		if payload.ExecutionRequests != nil {
+			return fmt.Errorf("payload contains non-nil execution requests pre-fork")
+		}
+		return nil
+	}
+	if payload.ExecutionRequests == nil {
+		return fmt.Errorf("payload contains nil execution requests")
+	}
+	expected := EncodeExecutionRequests(t.PendingRequests)
+	if len(payload.ExecutionRequests) != len(expected) {
+		return fmt.Errorf("unexpected execution request count: want %d, got %d", len(expected), len(payload.ExecutionRequests))
+	}
+	for i := range expected {
+		if !bytes.Equal(payload.ExecutionRequests[i], expected[i]) {
+			return fmt.Errorf("unexpected execution request %d: want %x, got %x", i, expected[i], payload.ExecutionRequests[i])
+		}
+	}
+	return nil
+}
+
 func (step NewPayloads) Execute(t *TestContext) error {
 	// Create a new payload
 	// Produce the payload
@@ -509,6 +600,16 @@ func (step NewPayloads) Execute(t *TestContext) error {
 					payload    = &t.CLMock.LatestPayloadBuilt
 				)
 
+				// Verify the transactions and execution requests sent by the test
+				for _, tx := range t.PendingTransactions {
+					if !helper.TransactionInPayload(payload, tx) {
+						t.Fatalf("FAIL: Transaction %s not included in payload (payload %d/%d)", tx.Hash(), p+1, payloadCount)
+					}
+				}
+				if err := VerifyExecutionRequests(t, payload); err != nil {
+					t.Fatalf("FAIL: Error verifying execution requests (payload %d/%d): %v", p+1, payloadCount, err)
+				}
+
 				if !t.Env.ForkConfig.IsCancun(payload.Timestamp) {
 					// Nothing to do
 					return
@@ -522,7 +623,7 @@ func (step NewPayloads) Execute(t *TestContext) error {
 					t.Fatalf("FAIL: Error retrieving blob bundle (payload %d/%d): %v", p+1, payloadCount, err)
 				}
 
-				if err := step.VerifyBlobBundle(blobDataInPayload, payload, blobBundle); err != nil {
+				if err := step.VerifyBlobBundle(t.ForkConfig, blobDataInPayload, payload, blobBundle); err != nil {
 					t.Fatalf("FAIL: Error verifying blob bundle (payload %d/%d): %v", p+1, payloadCount, err)
 				}
 			},
@@ -601,10 +702,25 @@ func (step NewPayloads) Execute(t *TestContext) error {
 				if err != nil {
 					t.Fatalf("FAIL: Error retrieving blob bundle (payload %d/%d): %v", p+1, payloadCount, err)
 				}
-				if err := step.VerifyPayload(t.TimeoutContext, t.Env.ForkConfig, t.TestEngine, blobTxsInPayload, t.CLMock.LatestShouldOverrideBuilder, payload, &previousPayload); err != nil {
+				if err := step.VerifyPayload(t.TimeoutContext, t.Env.ForkConfig, t.Genesis.Config, t.TestEngine, blobTxsInPayload, t.CLMock.LatestShouldOverrideBuilder, payload, &previousPayload); err != nil {
 					t.Fatalf("FAIL: Error verifying payload (payload %d/%d): %v", p+1, payloadCount, err)
 				}
 				previousPayload = t.CLMock.LatestPayloadBuilt
+
+				// Verify the receipts and the effects of the transactions sent by the test
+				for _, tx := range t.PendingTransactions {
+					r := t.TestEngine.TestTransactionReceipt(tx.Hash())
+					r.ExpectNoError()
+					if r.Receipt.Status != types.ReceiptStatusSuccessful {
+						t.Fatalf("FAIL: Transaction %s failed (payload %d/%d)", tx.Hash(), p+1, payloadCount)
+					}
+				}
+				for _, verify := range t.PendingVerifications {
+					if err := verify(t); err != nil {
+						t.Fatalf("FAIL: Error verifying transactions (payload %d/%d): %v", p+1, payloadCount, err)
+					}
+				}
+				t.ClearPending()
 			},
 		})
 		t.Logf("INFO: Correctly produced payload %d/%d", p+1, payloadCount)
@@ -675,6 +791,7 @@ func (step SendBlobTransactions) Execute(t *TestContext) error {
 			BlobGasFee: step.BlobTransactionMaxBlobGasCost,
 			BlobCount:  blobCountPerTx,
 			BlobID:     t.CurrentBlobID,
+			ForkConfig: t.ForkConfig,
 		}
 		sender := globals.TestAccounts[step.AccountIndex]
 		var (
@@ -708,7 +825,55 @@ func (step SendBlobTransactions) Execute(t *TestContext) error {
 }
 
 func (step SendBlobTransactions) Description() string {
-	return fmt.Sprintf("SendBlobTransactions: %d Transactions, %d blobs each, %d max data gas fee", step.TransactionCount, step.GetBlobsPerTransaction(), step.BlobTransactionMaxBlobGasCost.Uint64())
+	desc := fmt.Sprintf("SendBlobTransactions: %d Transactions, %d blobs each", step.TransactionCount, step.GetBlobsPerTransaction())
+	if step.BlobTransactionMaxBlobGasCost != nil {
+		desc += fmt.Sprintf(", %d max data gas fee", step.BlobTransactionMaxBlobGasCost.Uint64())
+	}
+	return desc
+}
+
+// A step that sends a blob transaction which must be rejected by the client
+type SendRejectedBlobTransaction struct {
+	// Number of blobs in the transaction
+	BlobCount uint64
+	// Account index to send the blob transaction from
+	AccountIndex uint64
+}
+
+func (step SendRejectedBlobTransaction) Execute(t *TestContext) error {
+	addr := common.BigToAddress(cancun.DATAHASH_START_ADDRESS)
+	sender := globals.TestAccounts[step.AccountIndex]
+	ctx, cancel := context.WithTimeout(t.TestContext, globals.RPCTimeout)
+	defer cancel()
+	header, err := t.Engine.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error getting header: %v", err)
+	}
+	nonce, err := t.Engine.NonceAt(ctx, sender.GetAddress(), nil)
+	if err != nil {
+		return fmt.Errorf("error getting nonce: %v", err)
+	}
+	blobTxCreator := &helper.BlobTransactionCreator{
+		To:         &addr,
+		GasLimit:   100000,
+		BlobCount:  step.BlobCount,
+		BlobID:     t.CurrentBlobID,
+		ForkConfig: t.ForkConfig,
+	}
+	blobTx, err := blobTxCreator.MakeTransaction(sender, nonce, header.Time)
+	if err != nil {
+		return fmt.Errorf("error crafting blob transaction: %v", err)
+	}
+	if err := t.Engine.SendTransaction(ctx, blobTx); err == nil {
+		return fmt.Errorf("blob transaction with %d blobs was accepted", step.BlobCount)
+	} else {
+		t.Logf("INFO: Blob transaction with %d blobs rejected: %v", step.BlobCount, err)
+	}
+	return nil
+}
+
+func (step SendRejectedBlobTransaction) Description() string {
+	return fmt.Sprintf("SendRejectedBlobTransaction: %d blobs", step.BlobCount)
 }
 
 // Send a modified version of the latest payload produced using NewPayloadV3
@@ -54,6 +54,10 @@ func (b BadHashOnNewPayload) GetName() string {
 	return fmt.Sprintf("Bad Hash on NewPayload (Syncing=%v, Sidechain=%v)", b.Syncing, b.Sidechain)
 }
 
+func (b BadHashOnNewPayload) GetTags() []string {
+	return test.AppendTags(b.BaseSpec.GetTags(), test.TagInvalidPayload)
+}
+
 func (b BadHashOnNewPayload) Execute(t *test.Env) {
 	// Produce blocks before starting the test
 	t.CLMock.ProduceBlocks(5, clmock.BlockProcessCallbacks{})
@@ -157,6 +161,10 @@ func (p ParentHashOnNewPayload) GetName() string {
 	return name
 }
 
+func (p ParentHashOnNewPayload) GetTags() []string {
+	return test.AppendTags(p.BaseSpec.GetTags(), test.TagInvalidPayload)
+}
+
 // Copy the parentHash into the blockHash, client should reject the payload
 // (from Kintsugi Incident Report: https://notes.ethereum.org/@ExXcnR0-SJGthjz1dwkA1A/BkkdHWXTY)
 func (b ParentHashOnNewPayload) Execute(t *test.Env) {
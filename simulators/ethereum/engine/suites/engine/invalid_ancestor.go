@@ -51,6 +51,10 @@ func (tc InvalidMissingAncestorReOrgTest) GetName() string {
 	)
 }
 
+func (tc InvalidMissingAncestorReOrgTest) GetTags() []string {
+	return test.AppendTags(tc.BaseSpec.GetTags(), test.TagReorg, test.TagInvalidPayload)
+}
+
 func (tc InvalidMissingAncestorReOrgTest) Execute(t *test.Env) {
 	// Produce blocks before starting the test
 	t.CLMock.ProduceBlocks(5, clmock.BlockProcessCallbacks{})
@@ -219,6 +223,10 @@ func (tc InvalidMissingAncestorReOrgSyncTest) GetName() string {
 	)
 }
 
+func (tc InvalidMissingAncestorReOrgSyncTest) GetTags() []string {
+	return test.AppendTags(tc.BaseSpec.GetTags(), test.TagReorg, test.TagInvalidPayload, test.TagSync)
+}
+
 func (tc InvalidMissingAncestorReOrgSyncTest) Execute(t *test.Env) {
 	var (
 		err             error
@@ -63,6 +63,10 @@ func (i InvalidPayloadTestCase) GetName() string {
 	)
 }
 
+func (i InvalidPayloadTestCase) GetTags() []string {
+	return test.AppendTags(i.BaseSpec.GetTags(), test.TagInvalidPayload)
+}
+
 func (tc InvalidPayloadTestCase) Execute(t *test.Env) {
 	if tc.Syncing {
 		// To allow sending the primary engine client into SYNCING state, we need a secondary client to guide the payload creation
@@ -326,6 +330,10 @@ func (i PayloadBuildAfterInvalidPayloadTest) GetName() string {
 	return name
 }
 
+func (i PayloadBuildAfterInvalidPayloadTest) GetTags() []string {
+	return test.AppendTags(i.BaseSpec.GetTags(), test.TagInvalidPayload)
+}
+
 func (tc PayloadBuildAfterInvalidPayloadTest) Execute(t *test.Env) {
 	// Add a second client to build the invalid payload
 	secondaryEngine, err := hive_rpc.HiveRPCEngineStarter{}.StartClient(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
@@ -32,6 +32,10 @@ func (s SidechainReOrgTest) GetName() string {
 	return name
 }
 
+func (s SidechainReOrgTest) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagReorg)
+}
+
 // Reorg to a Sidechain using ForkchoiceUpdated
 func (spec SidechainReOrgTest) Execute(t *test.Env) {
 	// Produce blocks before starting the test
@@ -141,6 +145,10 @@ func (s TransactionReOrgTest) GetName() string {
 	return name
 }
 
+func (s TransactionReOrgTest) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagReorg)
+}
+
 // Test transaction status after a forkchoiceUpdated re-orgs to an alternative hash where a transaction is not present
 func (spec TransactionReOrgTest) Execute(t *test.Env) {
 	// Produce blocks before starting the test (So we don't try to reorg back to the genesis block)
@@ -426,6 +434,10 @@ func (s ReOrgBackToCanonicalTest) GetName() string {
 	return name
 }
 
+func (s ReOrgBackToCanonicalTest) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagReorg)
+}
+
 func (s ReOrgBackToCanonicalTest) GetDepth() uint64 {
 	if s.ReOrgDepth == 0 {
 		return 3
@@ -555,6 +567,10 @@ func (s ReOrgBackFromSyncingTest) GetName() string {
 	return name
 }
 
+func (s ReOrgBackFromSyncingTest) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagReorg)
+}
+
 // Test that performs a re-org back to the canonical chain after re-org to syncing/unavailable chain.
 func (spec ReOrgBackFromSyncingTest) Execute(t *test.Env) {
 	// Produce an alternative chain
@@ -641,6 +657,10 @@ func (s ReOrgPrevValidatedPayloadOnSideChainTest) GetName() string {
 	return name
 }
 
+func (s ReOrgPrevValidatedPayloadOnSideChainTest) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagReorg)
+}
+
 // Test that performs a re-org to a previously validated payload on a side chain.
 func (spec ReOrgPrevValidatedPayloadOnSideChainTest) Execute(t *test.Env) {
 	// Produce blocks before starting the test
@@ -755,6 +775,10 @@ func (s SafeReOrgToSideChainTest) GetName() string {
 	return name
 }
 
+func (s SafeReOrgToSideChainTest) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagReorg)
+}
+
 // Test that performs a re-org of the safe block to a side chain.
 func (s SafeReOrgToSideChainTest) Execute(t *test.Env) {
 	// Produce an alternative chain
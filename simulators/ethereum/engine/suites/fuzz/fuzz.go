@@ -0,0 +1,217 @@
+package suite_fuzz
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	api "github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/simulators/ethereum/engine/clmock"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+)
+
+var big1 = big.NewInt(1)
+
+// Test that sends randomly mutated versions of each payload built by the client
+// before the valid payload, and verifies that none of them is accepted.
+type PayloadFuzzSpec struct {
+	test.BaseSpec
+	// Seed of the fuzzer, a random seed is drawn from the test randomness source if zero
+	Seed int64
+	// Number of payloads to build and mutate
+	PayloadCount uint64
+	// Maximum number of mutations composed on each invalid payload
+	MaxMutations int
+	// Maximum number of transactions sent before each payload is built
+	MaxTransactionsPerPayload int
+}
+
+func (s PayloadFuzzSpec) WithMainFork(fork config.Fork) test.Spec {
+	specCopy := s
+	specCopy.MainFork = fork
+	return specCopy
+}
+
+func (s PayloadFuzzSpec) GetName() string {
+	return fmt.Sprintf("%s, MaxMutations=%d", s.Name, s.GetMaxMutations())
+}
+
+func (s PayloadFuzzSpec) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), test.TagFuzz, test.TagInvalidPayload)
+}
+
+func (s PayloadFuzzSpec) GetPayloadCount() uint64 {
+	if s.PayloadCount == 0 {
+		return 10
+	}
+	return s.PayloadCount
+}
+
+func (s PayloadFuzzSpec) GetMaxMutations() int {
+	if s.MaxMutations == 0 {
+		return 1
+	}
+	return s.MaxMutations
+}
+
+func (s PayloadFuzzSpec) GetMaxTransactionsPerPayload() int {
+	if s.MaxTransactionsPerPayload == 0 {
+		return 4
+	}
+	return s.MaxTransactionsPerPayload
+}
+
+// Sends the payload resulting from the mutations to all clients and verifies
+// that it is not accepted. Returns a description of the first violation found,
+// or an empty string if the clients behaved correctly.
+func checkMutatedPayload(t *test.Env, seed int64, base *typ.ExecutableData, mutations PayloadMutations) (string, error) {
+	payload, err := mutations.Apply(seed, base)
+	if err != nil {
+		return "", err
+	}
+	// Execution specification:
+	// - {status: INVALID, latestValidHash: validHash, validationError: errorMessage | null} if the payload is invalid
+	// - {status: SYNCING, latestValidHash: null, validationError: null} if the parent is unknown, or
+	//   {status: ACCEPTED, ...} if the payload does not extend the canonical chain
+	unknownParent := mutations.UnknownParent()
+	version := t.ForkConfig.NewPayloadVersion(base.Timestamp)
+	for _, resp := range t.CLMock.BroadcastNewPayload(payload, version) {
+		if resp.Error != nil {
+			return fmt.Sprintf("client %s returned error on NewPayload: %v", resp.Container, resp.Error), nil
+		}
+		status := resp.ExecutePayloadResponse.Status
+		switch {
+		case status == api.INVALID:
+		case unknownParent && (status == api.SYNCING || status == api.ACCEPTED):
+		default:
+			return fmt.Sprintf("client %s returned %s on NewPayload of invalid payload %v", resp.Container, status, payload.BlockHash), nil
+		}
+	}
+
+	if unknownParent || payload.BlockHash == base.BlockHash {
+		// Sending the payload as head would start a sync which cannot finish,
+		// or the mutations are not part of the block hash (e.g. versioned hashes)
+		// and the head would be the valid payload not yet sent
+		return "", nil
+	}
+
+	// The client must not make the invalid payload canonical
+	fcState := api.ForkchoiceStateV1{
+		HeadBlockHash:      payload.BlockHash,
+		SafeBlockHash:      t.CLMock.LatestForkchoice.SafeBlockHash,
+		FinalizedBlockHash: t.CLMock.LatestForkchoice.FinalizedBlockHash,
+	}
+	fcUVersion := t.ForkConfig.ForkchoiceUpdatedVersion(base.Timestamp, nil)
+	for _, resp := range t.CLMock.BroadcastForkchoiceUpdated(&fcState, nil, fcUVersion) {
+		if resp.Error != nil {
+			return fmt.Sprintf("client %s returned error on ForkchoiceUpdated: %v", resp.Container, resp.Error), nil
+		}
+		if resp.ForkchoiceResponse.PayloadStatus.Status == api.VALID {
+			return fmt.Sprintf("client %s returned VALID on ForkchoiceUpdated to invalid payload %v", resp.Container, payload.BlockHash), nil
+		}
+	}
+	for _, ec := range t.CLMock.EngineClients {
+		ctx, cancel := context.WithTimeout(t.TestContext, globals.RPCTimeout)
+		defer cancel()
+		head, err := ec.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return "", err
+		}
+		if head.Hash() == payload.BlockHash {
+			return fmt.Sprintf("client %s made invalid payload %v canonical", ec.ID(), payload.BlockHash), nil
+		}
+	}
+	return "", nil
+}
+
+// Removes mutations one at a time while the violation is still reproduced, and
+// returns the reduced list of mutations.
+func minimizeMutations(t *test.Env, seed int64, base *typ.ExecutableData, mutations PayloadMutations) PayloadMutations {
+	for i := 0; i < len(mutations) && len(mutations) > 1; {
+		candidate := mutations.Without(i)
+		violation, err := checkMutatedPayload(t, seed, base, candidate)
+		if err == nil && violation != "" {
+			mutations = candidate
+		} else {
+			i++
+		}
+	}
+	return mutations
+}
+
+func (s PayloadFuzzSpec) Execute(t *test.Env) {
+	seed := s.Seed
+	if seed == 0 {
+		seed = t.Rand.Int63()
+	}
+	t.Logf("INFO (%s): Fuzzer seed: %d", t.TestName, seed)
+	r := rand.New(rand.NewSource(seed))
+
+	blobID := helper.BlobID(0)
+	for i := uint64(0); i < s.GetPayloadCount(); i++ {
+		t.CLMock.ProduceSingleBlock(clmock.BlockProcessCallbacks{
+			OnPayloadProducerSelected: func() {
+				// Transactions from the same sender, so their order matters
+				txCount := 1 + r.Intn(s.GetMaxTransactionsPerPayload())
+				for j := 0; j < txCount; j++ {
+					_, err := t.SendTransaction(
+						t.TestContext,
+						globals.TestAccounts[0],
+						t.CLMock.NextBlockProducer,
+						&helper.BaseTransactionCreator{
+							Recipient:  &globals.PrevRandaoContractAddr,
+							Amount:     big1,
+							TxType:     t.TestTransactionType,
+							GasLimit:   75000,
+							ForkConfig: t.ForkConfig,
+						},
+					)
+					if err != nil {
+						t.Fatalf("FAIL (%s): Error trying to send transaction: %v", t.TestName, err)
+					}
+				}
+				if t.ForkConfig.IsCancun(t.CLMock.GetNextBlockTimestamp()) {
+					_, err := t.SendTransaction(
+						t.TestContext,
+						globals.TestAccounts[1],
+						t.CLMock.NextBlockProducer,
+						&helper.BlobTransactionCreator{
+							To:         &globals.PrevRandaoContractAddr,
+							GasLimit:   100000,
+							BlobGasFee: globals.BlobGasPrice,
+							BlobID:     blobID,
+							BlobCount:  1,
+							ForkConfig: t.ForkConfig,
+						},
+					)
+					if err != nil {
+						t.Fatalf("FAIL (%s): Error trying to send blob transaction: %v", t.TestName, err)
+					}
+					blobID++
+				}
+			},
+			OnGetPayload: func() {
+				base := t.CLMock.LatestPayloadBuilt
+				parent := types.CopyHeader(t.CLMock.LatestHeader)
+				mutations := GenerateMutations(r, &base, parent, s.GetMaxMutations())
+				payloadSeed := r.Int63()
+				t.Logf("INFO (%s): Payload %d mutations: %s", t.TestName, base.Number, mutations)
+
+				violation, err := checkMutatedPayload(t, payloadSeed, &base, mutations)
+				if err != nil {
+					t.Fatalf("FAIL (%s): seed=%d, payload=%d, mutations=%s: unable to check mutated payload: %v", t.TestName, seed, base.Number, mutations, err)
+				}
+				if violation != "" {
+					minimal := minimizeMutations(t, payloadSeed, &base, mutations)
+					t.Fatalf("FAIL (%s): seed=%d, payload=%d, mutations=%s, minimal mutations=%s: %s", t.TestName, seed, base.Number, mutations, minimal, violation)
+				}
+			},
+		})
+	}
+}
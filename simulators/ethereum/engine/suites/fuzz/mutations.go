@@ -0,0 +1,525 @@
+package suite_fuzz
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+)
+
+// A single modification of a payload which makes it invalid on its own.
+// All random values are drawn when the mutation is generated, so applying it
+// again produces the same payload.
+type PayloadMutation struct {
+	// Description of the mutation, including the values used
+	Description string
+	// The mutated payload references a parent unknown to the client
+	UnknownParent bool
+	// Applies the mutation on the customized payload data, which already
+	// contains the mutations applied before this one
+	Apply func(base *typ.ExecutableData, custom *helper.CustomPayloadData) error
+}
+
+func (m *PayloadMutation) String() string {
+	return m.Description
+}
+
+// Generates a random mutation for the base payload built on top of parent, or
+// returns nil if the mutation is not applicable to the payload.
+type MutationGenerator func(r *rand.Rand, base *typ.ExecutableData, parent *types.Header) *PayloadMutation
+
+type PayloadMutations []*PayloadMutation
+
+func (ms PayloadMutations) String() string {
+	descriptions := make([]string, len(ms))
+	for i, m := range ms {
+		descriptions[i] = m.Description
+	}
+	return "[" + strings.Join(descriptions, "; ") + "]"
+}
+
+// Returns whether any of the mutations makes the parent of the payload unknown.
+func (ms PayloadMutations) UnknownParent() bool {
+	for _, m := range ms {
+		if m.UnknownParent {
+			return true
+		}
+	}
+	return false
+}
+
+func (ms PayloadMutations) customPayloadData(base *typ.ExecutableData) (*helper.CustomPayloadData, error) {
+	custom := &helper.CustomPayloadData{}
+	for _, m := range ms {
+		if err := m.Apply(base, custom); err != nil {
+			return nil, fmt.Errorf("unable to apply mutation %s: %v", m, err)
+		}
+	}
+	return custom, nil
+}
+
+// Applies all mutations in order to the base payload and returns the resulting
+// payload, with its block hash recalculated.
+func (ms PayloadMutations) Apply(seed int64, base *typ.ExecutableData) (*typ.ExecutableData, error) {
+	custom, err := ms.customPayloadData(base)
+	if err != nil {
+		return nil, err
+	}
+	return custom.CustomizePayload(rand.New(rand.NewSource(seed)), base)
+}
+
+// Returns a copy of the mutations without the mutation at index i.
+func (ms PayloadMutations) Without(i int) PayloadMutations {
+	result := make(PayloadMutations, 0, len(ms)-1)
+	result = append(result, ms[:i]...)
+	return append(result, ms[i+1:]...)
+}
+
+// Generates up to maxCount mutations applicable to the base payload, each one
+// of a different kind.
+func GenerateMutations(r *rand.Rand, base *typ.ExecutableData, parent *types.Header, maxCount int) PayloadMutations {
+	count := 1 + r.Intn(maxCount)
+	mutations := make(PayloadMutations, 0, count)
+	for _, i := range r.Perm(len(MutationGenerators)) {
+		if len(mutations) == count {
+			break
+		}
+		m := MutationGenerators[i](r, base, parent)
+		if m == nil {
+			continue
+		}
+		// Mutations of the transaction list must still apply after the
+		// previous ones, e.g. a removed transaction cannot be swapped
+		if _, err := append(mutations, m).customPayloadData(base); err == nil {
+			mutations = append(mutations, m)
+		}
+	}
+	return mutations
+}
+
+func randomHash(r *rand.Rand, exclude common.Hash) common.Hash {
+	for {
+		var h common.Hash
+		r.Read(h[:])
+		if h != exclude {
+			return h
+		}
+	}
+}
+
+// Returns the transactions of the payload after the previous mutations.
+func currentTransactions(base *typ.ExecutableData, custom *helper.CustomPayloadData) [][]byte {
+	if custom.Transactions != nil {
+		return *custom.Transactions
+	}
+	return base.Transactions
+}
+
+func setTransactions(custom *helper.CustomPayloadData, txs [][]byte) {
+	custom.Transactions = &txs
+}
+
+func decodeTransactions(txs [][]byte) ([]*types.Transaction, error) {
+	decoded := make([]*types.Transaction, len(txs))
+	for i, txBytes := range txs {
+		decoded[i] = new(types.Transaction)
+		if err := decoded[i].UnmarshalBinary(txBytes); err != nil {
+			return nil, err
+		}
+	}
+	return decoded, nil
+}
+
+func testAccount(tx *types.Transaction) *globals.TestAccount {
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil
+	}
+	for _, account := range globals.TestAccounts {
+		if account.GetAddress() == sender {
+			return account
+		}
+	}
+	return nil
+}
+
+// Returns a copy of the transaction list where the transaction at index is
+// replaced by the customized version.
+func customizeTransactionAt(txs [][]byte, index int, sender *globals.TestAccount, customTxData *helper.CustomTransactionData) ([][]byte, error) {
+	if index >= len(txs) {
+		return nil, fmt.Errorf("transaction index %d out of range", index)
+	}
+	var baseTx types.Transaction
+	if err := baseTx.UnmarshalBinary(txs[index]); err != nil {
+		return nil, err
+	}
+	modifiedTx, err := helper.CustomizeTransaction(&baseTx, sender, customTxData)
+	if err != nil {
+		return nil, err
+	}
+	modifiedTxBytes, err := modifiedTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]byte, len(txs))
+	copy(result, txs)
+	result[index] = modifiedTxBytes
+	return result, nil
+}
+
+// List of all mutation kinds used by the fuzzer
+var MutationGenerators = []MutationGenerator{
+	// Header field corruption
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		parentHash := randomHash(r, base.ParentHash)
+		return &PayloadMutation{
+			Description:   fmt.Sprintf("ParentHash=%s", parentHash),
+			UnknownParent: true,
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.ParentHash = &parentHash
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		stateRoot := randomHash(r, base.StateRoot)
+		return &PayloadMutation{
+			Description: fmt.Sprintf("StateRoot=%s", stateRoot),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.StateRoot = &stateRoot
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		receiptsRoot := randomHash(r, base.ReceiptsRoot)
+		return &PayloadMutation{
+			Description: fmt.Sprintf("ReceiptsRoot=%s", receiptsRoot),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.ReceiptsRoot = &receiptsRoot
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		logsBloom := make([]byte, types.BloomByteLength)
+		r.Read(logsBloom)
+		return &PayloadMutation{
+			Description: fmt.Sprintf("LogsBloom=0x%x...", logsBloom[:8]),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.LogsBloom = &logsBloom
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		gasUsed := base.GasUsed
+		for gasUsed == base.GasUsed {
+			gasUsed = r.Uint64() % (base.GasLimit + 1)
+		}
+		return &PayloadMutation{
+			Description: fmt.Sprintf("GasUsed=%d", gasUsed),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.GasUsed = &gasUsed
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, _ *typ.ExecutableData, parent *types.Header) *PayloadMutation {
+		// Out of the bounds allowed relative to the parent gas limit
+		delta := parent.GasLimit/1024 + 1 + r.Uint64()%1000
+		gasLimit := parent.GasLimit + delta
+		if r.Intn(2) == 0 && delta < parent.GasLimit {
+			gasLimit = parent.GasLimit - delta
+		}
+		return &PayloadMutation{
+			Description: fmt.Sprintf("GasLimit=%d", gasLimit),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.GasLimit = &gasLimit
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		number := base.Number + 1 + uint64(r.Intn(5))
+		if r.Intn(2) == 0 {
+			number = base.Number - 1 - uint64(r.Intn(int(base.Number)))
+		}
+		return &PayloadMutation{
+			Description: fmt.Sprintf("Number=%d", number),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.Number = &number
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, _ *typ.ExecutableData, parent *types.Header) *PayloadMutation {
+		// Timestamp equal to or lower than the parent's
+		maxDelta := parent.Time
+		if maxDelta > 12 {
+			maxDelta = 12
+		}
+		timestamp := parent.Time - uint64(r.Int63n(int64(maxDelta)+1))
+		return &PayloadMutation{
+			Description: fmt.Sprintf("Timestamp=%d (parent %d)", timestamp, parent.Time),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.Timestamp = &timestamp
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		if base.BaseFeePerGas == nil {
+			return nil
+		}
+		delta := big.NewInt(1 + r.Int63n(1e9))
+		baseFee := new(big.Int).Add(base.BaseFeePerGas, delta)
+		if r.Intn(2) == 0 && base.BaseFeePerGas.Cmp(delta) >= 0 {
+			baseFee = new(big.Int).Sub(base.BaseFeePerGas, delta)
+		}
+		return &PayloadMutation{
+			Description: fmt.Sprintf("BaseFeePerGas=%d", baseFee),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.BaseFeePerGas = baseFee
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, _ *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		// Longer than the 32 bytes allowed
+		extraData := make([]byte, 33+r.Intn(32))
+		r.Read(extraData)
+		return &PayloadMutation{
+			Description: fmt.Sprintf("ExtraData=0x%x", extraData),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.ExtraData = &extraData
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		// Priority fees of the transactions are credited to the fee recipient,
+		// so the state root no longer matches
+		if len(base.Transactions) == 0 {
+			return nil
+		}
+		var feeRecipient common.Address
+		for feeRecipient == (common.Address{}) || feeRecipient == base.FeeRecipient {
+			r.Read(feeRecipient[:])
+		}
+		return &PayloadMutation{
+			Description: fmt.Sprintf("FeeRecipient=%s", feeRecipient),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.FeeRecipient = &feeRecipient
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		if base.ParentBeaconBlockRoot == nil {
+			return nil
+		}
+		beaconRoot := randomHash(r, *base.ParentBeaconBlockRoot)
+		return &PayloadMutation{
+			Description: fmt.Sprintf("ParentBeaconBlockRoot=%s", beaconRoot),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.ParentBeaconRoot = &beaconRoot
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		if base.BlobGasUsed == nil {
+			return nil
+		}
+		// Either not a multiple of the blob gas, or an extra blob
+		blobGasUsed := *base.BlobGasUsed + 1 + uint64(r.Intn(int(cancun.GAS_PER_BLOB)-1))
+		if r.Intn(2) == 0 {
+			blobGasUsed = *base.BlobGasUsed + cancun.GAS_PER_BLOB
+		}
+		return &PayloadMutation{
+			Description: fmt.Sprintf("BlobGasUsed=%d", blobGasUsed),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.BlobGasUsed = &blobGasUsed
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		if base.ExcessBlobGas == nil {
+			return nil
+		}
+		excessBlobGas := *base.ExcessBlobGas + 1 + uint64(r.Intn(int(cancun.GAS_PER_BLOB)))
+		return &PayloadMutation{
+			Description: fmt.Sprintf("ExcessBlobGas=%d", excessBlobGas),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.ExcessBlobGas = &excessBlobGas
+				return nil
+			},
+		}
+	},
+
+	// Blob versioned hashes
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		if base.VersionedHashes == nil || len(*base.VersionedHashes) == 0 {
+			return nil
+		}
+		customizers := []struct {
+			name       string
+			customizer helper.VersionedHashesCustomizer
+		}{
+			{"IncreaseVersion", &helper.IncreaseVersionVersionedHashes{}},
+			{"Corrupt", &helper.CorruptVersionedHashes{}},
+			{"Remove", &helper.RemoveVersionedHash{}},
+			{"Extra", &helper.ExtraVersionedHash{}},
+		}
+		c := customizers[r.Intn(len(customizers))]
+		return &PayloadMutation{
+			Description: fmt.Sprintf("VersionedHashes=%s", c.name),
+			Apply: func(_ *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				custom.VersionedHashesCustomizer = c.customizer
+				return nil
+			},
+		}
+	},
+
+	// Transaction list mutations
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		// Swap two transactions of the same sender, which breaks the nonce order
+		txs, err := decodeTransactions(base.Transactions)
+		if err != nil {
+			return nil
+		}
+		type pair struct{ i, j int }
+		pairs := make([]pair, 0)
+		for i := range txs {
+			for j := i + 1; j < len(txs); j++ {
+				si, erri := types.Sender(types.LatestSignerForChainID(txs[i].ChainId()), txs[i])
+				sj, errj := types.Sender(types.LatestSignerForChainID(txs[j].ChainId()), txs[j])
+				if erri == nil && errj == nil && si == sj {
+					pairs = append(pairs, pair{i, j})
+				}
+			}
+		}
+		if len(pairs) == 0 {
+			return nil
+		}
+		p := pairs[r.Intn(len(pairs))]
+		return &PayloadMutation{
+			Description: fmt.Sprintf("SwapTransactions=%d,%d", p.i, p.j),
+			Apply: func(base *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				txs := currentTransactions(base, custom)
+				if p.j >= len(txs) {
+					return fmt.Errorf("transaction index %d out of range", p.j)
+				}
+				swapped := make([][]byte, len(txs))
+				copy(swapped, txs)
+				swapped[p.i], swapped[p.j] = swapped[p.j], swapped[p.i]
+				setTransactions(custom, swapped)
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		if len(base.Transactions) == 0 {
+			return nil
+		}
+		index := r.Intn(len(base.Transactions))
+		return &PayloadMutation{
+			Description: fmt.Sprintf("RemoveTransaction=%d", index),
+			Apply: func(base *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				txs := currentTransactions(base, custom)
+				if index >= len(txs) {
+					return fmt.Errorf("transaction index %d out of range", index)
+				}
+				removed := make([][]byte, 0, len(txs)-1)
+				removed = append(removed, txs[:index]...)
+				setTransactions(custom, append(removed, txs[index+1:]...))
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		// Include a transaction twice, the second one has an already used nonce
+		if len(base.Transactions) == 0 {
+			return nil
+		}
+		index := r.Intn(len(base.Transactions))
+		return &PayloadMutation{
+			Description: fmt.Sprintf("DuplicateTransaction=%d", index),
+			Apply: func(base *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				txs := currentTransactions(base, custom)
+				if index >= len(txs) {
+					return fmt.Errorf("transaction index %d out of range", index)
+				}
+				duplicated := make([][]byte, 0, len(txs)+1)
+				duplicated = append(duplicated, txs[:index+1]...)
+				duplicated = append(duplicated, txs[index])
+				setTransactions(custom, append(duplicated, txs[index+1:]...))
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		if len(base.Transactions) == 0 {
+			return nil
+		}
+		index := r.Intn(len(base.Transactions))
+		var baseTx types.Transaction
+		if err := baseTx.UnmarshalBinary(base.Transactions[index]); err != nil {
+			return nil
+		}
+		v, rawR, rawS := baseTx.RawSignatureValues()
+		signature := helper.SignatureValuesFromRaw(v, rawR, rawS)
+		signature.S = new(big.Int).Sub(signature.S, big.NewInt(1+r.Int63n(1e6)))
+		return &PayloadMutation{
+			Description: fmt.Sprintf("TransactionSignature=%d,S=%d", index, signature.S),
+			Apply: func(base *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				txs, err := customizeTransactionAt(currentTransactions(base, custom), index, nil, &helper.CustomTransactionData{
+					Signature: &signature,
+				})
+				if err != nil {
+					return err
+				}
+				setTransactions(custom, txs)
+				return nil
+			},
+		}
+	},
+	func(r *rand.Rand, base *typ.ExecutableData, _ *types.Header) *PayloadMutation {
+		// Re-signed transaction with a nonce gap
+		if len(base.Transactions) == 0 {
+			return nil
+		}
+		index := r.Intn(len(base.Transactions))
+		var baseTx types.Transaction
+		if err := baseTx.UnmarshalBinary(base.Transactions[index]); err != nil {
+			return nil
+		}
+		sender := testAccount(&baseTx)
+		if sender == nil {
+			return nil
+		}
+		nonce := baseTx.Nonce() + 1 + uint64(r.Intn(1000))
+		return &PayloadMutation{
+			Description: fmt.Sprintf("TransactionNonce=%d,Nonce=%d", index, nonce),
+			Apply: func(base *typ.ExecutableData, custom *helper.CustomPayloadData) error {
+				txs, err := customizeTransactionAt(currentTransactions(base, custom), index, sender, &helper.CustomTransactionData{
+					Nonce: &nonce,
+				})
+				if err != nil {
+					return err
+				}
+				setTransactions(custom, txs)
+				return nil
+			},
+		}
+	},
+}
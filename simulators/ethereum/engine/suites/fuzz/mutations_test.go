@@ -0,0 +1,106 @@
+package suite_fuzz
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+)
+
+func testBasePayload(t *testing.T) (*typ.ExecutableData, *types.Header) {
+	parent := &types.Header{
+		Number:   big.NewInt(4),
+		GasLimit: 30_000_000,
+		Time:     100,
+	}
+	txs := make([][]byte, 0)
+	for nonce, txType := range []helper.TestTransactionType{helper.LegacyTxOnly, helper.DynamicFeeTxOnly, helper.LegacyTxOnly} {
+		tx, err := (&helper.BaseTransactionCreator{
+			Recipient: &globals.PrevRandaoContractAddr,
+			Amount:    big1,
+			GasLimit:  75000,
+			TxType:    txType,
+		}).MakeTransaction(globals.TestAccounts[0], uint64(nonce), parent.Time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txBytes, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, txBytes)
+	}
+	blobGasUsed, excessBlobGas := uint64(0x20000), uint64(0)
+	beaconRoot := common.Hash{1}
+	base := &typ.ExecutableData{
+		ParentHash:            parent.Hash(),
+		StateRoot:             common.Hash{2},
+		ReceiptsRoot:          common.Hash{3},
+		LogsBloom:             make([]byte, types.BloomByteLength),
+		Number:                parent.Number.Uint64() + 1,
+		GasLimit:              parent.GasLimit,
+		GasUsed:               150000,
+		Timestamp:             parent.Time + 1,
+		BaseFeePerGas:         big.NewInt(7),
+		Transactions:          txs,
+		BlobGasUsed:           &blobGasUsed,
+		ExcessBlobGas:         &excessBlobGas,
+		ParentBeaconBlockRoot: &beaconRoot,
+		VersionedHashes:       &[]common.Hash{{0x01, 0x02}},
+	}
+	base, err := (&helper.CustomPayloadData{}).CustomizePayload(rand.New(rand.NewSource(0)), base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base, parent
+}
+
+func TestMutationGenerators(t *testing.T) {
+	base, parent := testBasePayload(t)
+	r := rand.New(rand.NewSource(1))
+	for i, generator := range MutationGenerators {
+		m := generator(r, base, parent)
+		if m == nil {
+			t.Fatalf("generator %d not applicable to the test payload", i)
+		}
+		mutations := PayloadMutations{m}
+		payload, err := mutations.Apply(0, base)
+		if err != nil {
+			t.Fatalf("mutation %s: %v", m, err)
+		}
+		if payload.BlockHash == base.BlockHash && reflect.DeepEqual(payload.VersionedHashes, base.VersionedHashes) {
+			t.Fatalf("mutation %s did not modify the payload", m)
+		}
+		// Applying the mutation again must produce the same payload
+		again, err := mutations.Apply(0, base)
+		if err != nil {
+			t.Fatalf("mutation %s: %v", m, err)
+		}
+		if again.BlockHash != payload.BlockHash {
+			t.Fatalf("mutation %s is not deterministic", m)
+		}
+	}
+}
+
+func TestGenerateMutationsSeed(t *testing.T) {
+	base, parent := testBasePayload(t)
+	for seed := int64(0); seed < 100; seed++ {
+		first := GenerateMutations(rand.New(rand.NewSource(seed)), base, parent, 4)
+		second := GenerateMutations(rand.New(rand.NewSource(seed)), base, parent, 4)
+		if first.String() != second.String() {
+			t.Fatalf("seed %d generated different mutations: %s != %s", seed, first, second)
+		}
+		if len(first) == 0 || len(first) > 4 {
+			t.Fatalf("seed %d generated %d mutations", seed, len(first))
+		}
+		if _, err := first.Apply(seed, base); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// # Test suite for payload fuzzing tests
+package suite_fuzz
+
+import (
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Forks on which the payload fuzzing tests run, all of them active since genesis
+var fuzzForks = []config.Fork{
+	config.Paris,
+	config.Shanghai,
+	config.Cancun,
+	config.Prague,
+	config.Osaka,
+}
+
+// List of all payload fuzzing tests
+var Tests = make([]test.Spec, 0)
+
+func init() {
+	for _, fork := range fuzzForks {
+		for _, maxMutations := range []int{1, 3} {
+			Tests = append(Tests, PayloadFuzzSpec{
+				BaseSpec: test.BaseSpec{
+					Name: "Fuzz Invalid Payloads",
+					About: `
+					Builds payloads with transactions from the same sender and, before
+					broadcasting each valid payload, sends a version of it with random
+					mutations composed on top: corrupted header fields, reordered,
+					removed, duplicated or re-signed transactions, invalid signatures,
+					wrong blob versioned hashes and timestamps not after the parent.
+
+					Verifications performed:
+					- NewPayload returns INVALID, or SYNCING/ACCEPTED if the parent
+					  of the mutated payload is unknown
+					- ForkchoiceUpdated to the mutated payload does not return VALID
+					- The mutated payload never becomes the head of the chain
+					- The valid payload is accepted after the mutated ones
+
+					On failure, the fuzzer seed and the smallest subset of the
+					mutations which still reproduces the failure are reported.
+					`,
+					MainFork:       fork,
+					TimeoutSeconds: 120,
+				},
+				PayloadCount: 10,
+				MaxMutations: maxMutations,
+			})
+		}
+	}
+}
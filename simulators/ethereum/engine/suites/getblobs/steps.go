@@ -0,0 +1,223 @@
+package suite_getblobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/simulators/ethereum/engine/client"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+)
+
+// Status of a blob in the blob pool of the client, derived from the blob
+// transactions sent during the test.
+type BlobStatus int
+
+const (
+	// No transaction containing the blob was sent to the client
+	BlobUnknown BlobStatus = iota
+	// The transaction containing the blob was replaced by another transaction
+	// with the same sender and nonce
+	BlobReplaced
+	// The transaction containing the blob was included in a payload, the client
+	// may or may not still return the blob
+	BlobIncluded
+	// The transaction containing the blob is pending in the blob pool
+	BlobPooled
+)
+
+func (s BlobStatus) String() string {
+	switch s {
+	case BlobUnknown:
+		return "unknown"
+	case BlobReplaced:
+		return "replaced"
+	case BlobIncluded:
+		return "included"
+	case BlobPooled:
+		return "pooled"
+	}
+	return fmt.Sprintf("BlobStatus(%d)", int(s))
+}
+
+// Returns whether the client is required to return null for the blob.
+func (s BlobStatus) MustBeMissing() bool {
+	return s == BlobUnknown || s == BlobReplaced
+}
+
+func transactionSender(tx typ.Transaction) (common.Address, error) {
+	signer := types.NewCancunSigner(globals.ChainID)
+	switch tx := tx.(type) {
+	case *types.Transaction:
+		return types.Sender(signer, tx)
+	case *typ.TransactionWithBlobData:
+		return types.Sender(signer, tx.Tx)
+	}
+	return common.Address{}, fmt.Errorf("unknown transaction type %T", tx)
+}
+
+// Returns the status of the blob identified by the versioned hash, based on
+// the blob transactions sent to the client during the test.
+func GetBlobStatus(ctx context.Context, t *suite_cancun.TestContext, eth client.Eth, versionedHash common.Hash) (BlobStatus, error) {
+	t.TestBlobTxPool.Mutex.Lock()
+	defer t.TestBlobTxPool.Mutex.Unlock()
+
+	status := BlobUnknown
+	for index := uint64(0); index < t.CurrentTransactionIndex; index++ {
+		tx := t.Transactions[t.HashesByIndex[index]]
+		containsBlob := false
+		for _, h := range tx.BlobHashes() {
+			if h == versionedHash {
+				containsBlob = true
+				break
+			}
+		}
+		if !containsBlob {
+			continue
+		}
+
+		txStatus := BlobPooled
+		sender, err := transactionSender(tx)
+		if err != nil {
+			return status, err
+		}
+		// Look for a later transaction replacing this one
+		for laterIndex := index + 1; laterIndex < t.CurrentTransactionIndex; laterIndex++ {
+			laterTx := t.Transactions[t.HashesByIndex[laterIndex]]
+			if laterTx.Nonce() != tx.Nonce() {
+				continue
+			}
+			laterSender, err := transactionSender(laterTx)
+			if err != nil {
+				return status, err
+			}
+			if laterSender == sender {
+				txStatus = BlobReplaced
+				break
+			}
+		}
+		if txStatus != BlobReplaced {
+			if receipt, err := eth.TransactionReceipt(ctx, tx.Hash()); err == nil && receipt != nil {
+				txStatus = BlobIncluded
+			}
+		}
+		// The same blob could be contained in multiple transactions, keep the
+		// status which makes the blob available
+		if txStatus > status {
+			status = txStatus
+		}
+	}
+	return status, nil
+}
+
+// A step that requests blobs from the client using engine_getBlobsV1 or
+// engine_getBlobsV2, and verifies the response against the blob transactions
+// sent during the test.
+type GetBlobs struct {
+	// Version of engine_getBlobs to use
+	Version int
+	// Blobs to request, in order
+	BlobIDs helper.BlobIDs
+	// Expected error on the call
+	ExpectedError *int
+	// Client index to request the blobs from
+	ClientIndex uint64
+}
+
+func (step GetBlobs) Execute(t *suite_cancun.TestContext) error {
+	if step.ClientIndex >= uint64(len(t.Engines)) {
+		return fmt.Errorf("invalid client index %d", step.ClientIndex)
+	}
+	engine := t.Engines[step.ClientIndex]
+	testEngine := test.NewTestEngineClient(t.Env, engine)
+
+	versionedHashes := make([]common.Hash, len(step.BlobIDs))
+	for i, blobID := range step.BlobIDs {
+		versionedHash, err := blobID.GetVersionedHash(cancun.BLOB_COMMITMENT_VERSION_KZG)
+		if err != nil {
+			return err
+		}
+		versionedHashes[i] = versionedHash
+	}
+
+	var r *test.GetBlobsResponseExpectObject
+	switch step.Version {
+	case 1:
+		r = testEngine.TestEngineGetBlobsV1(versionedHashes)
+	case 2:
+		r = testEngine.TestEngineGetBlobsV2(versionedHashes)
+	default:
+		return fmt.Errorf("unknown getBlobs version %d", step.Version)
+	}
+	if step.ExpectedError != nil {
+		r.ExpectErrorCode(*step.ExpectedError)
+		return nil
+	}
+	r.ExpectNoError()
+
+	ctx, cancel := context.WithTimeout(t.TestContext, globals.RPCTimeout)
+	defer cancel()
+	statuses := make([]BlobStatus, len(versionedHashes))
+	anyMissing, allPooled := false, true
+	for i, versionedHash := range versionedHashes {
+		status, err := GetBlobStatus(ctx, t, engine, versionedHash)
+		if err != nil {
+			return err
+		}
+		statuses[i] = status
+		anyMissing = anyMissing || status.MustBeMissing()
+		allPooled = allPooled && status == BlobPooled
+	}
+
+	if step.Version == 2 {
+		// engine_getBlobsV2 returns null unless all blobs are available
+		if anyMissing {
+			r.ExpectNullResponse()
+			return nil
+		}
+		if !allPooled && r.BlobsV2 == nil {
+			t.Logf("INFO: Included blobs no longer available, got null response")
+			return nil
+		}
+		for i, blobID := range step.BlobIDs {
+			r.ExpectBlob(i, blobID)
+		}
+		return nil
+	}
+
+	for i, blobID := range step.BlobIDs {
+		switch statuses[i] {
+		case BlobPooled:
+			r.ExpectBlob(i, blobID)
+		case BlobIncluded:
+			// Client may drop the blob after inclusion, but if returned it
+			// must be correct
+			if r.HasBlob(i) {
+				r.ExpectBlob(i, blobID)
+			} else {
+				r.ExpectMissingBlob(i)
+				t.Logf("INFO: Included blob %d no longer available", blobID)
+			}
+		default:
+			r.ExpectMissingBlob(i)
+		}
+	}
+	return nil
+}
+
+func (step GetBlobs) Description() string {
+	desc := fmt.Sprintf("GetBlobsV%d: blobs %v", step.Version, step.BlobIDs)
+	if len(step.BlobIDs) > 8 {
+		desc = fmt.Sprintf("GetBlobsV%d: %d blobs", step.Version, len(step.BlobIDs))
+	}
+	if step.ExpectedError != nil {
+		desc += fmt.Sprintf(", expected error %d", *step.ExpectedError)
+	}
+	return desc
+}
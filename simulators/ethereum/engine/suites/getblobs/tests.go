@@ -0,0 +1,457 @@
+// # Test suite for engine_getBlobs tests
+package suite_getblobs
+
+import (
+	"math/big"
+
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Execution specification reference:
+// https://github.com/ethereum/execution-apis/blob/main/src/engine/cancun.md#engine_getblobsv1
+// https://github.com/ethereum/execution-apis/blob/main/src/engine/osaka.md#engine_getblobsv2
+
+// Maximum number of versioned hashes clients must accept on a single request
+const MAX_REQUEST_BLOB_COUNT = 128
+
+// Blob IDs far away from the ones sent during the tests, used to request blobs
+// which are not known to the client
+var unknownBlobIDs = helper.GetBlobList(1000, MAX_REQUEST_BLOB_COUNT+1)
+
+// List of all getBlobs tests
+var Tests = []test.Spec{
+	// engine_getBlobsV1
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV1, Known Blobs",
+			About: `
+			Tests engine_getBlobsV1 with versioned hashes of blobs in the blob
+			pool of the client.
+
+			Verifications performed:
+			- Blobs are returned in the order of the request
+			- KZG proof of each blob is valid for its commitment
+			`,
+			MainFork: config.Cancun,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob IDs 0-5
+				TransactionCount:              2,
+				BlobsPerTransaction:           3,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.GetBlobList(0, 6),
+			},
+			// Reversed order
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.BlobIDs{5, 4, 3, 2, 1, 0},
+			},
+			// Repeated versioned hashes
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.BlobIDs{2, 2, 4, 2},
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV1, Unknown Blobs",
+			About: `
+			Tests engine_getBlobsV1 with versioned hashes of blobs which were
+			never sent to the client.
+
+			Verifications performed:
+			- A null entry is returned for each unknown blob
+			- An empty request returns an empty response
+			`,
+			MainFork: config.Cancun,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			GetBlobs{
+				Version: 1,
+				BlobIDs: unknownBlobIDs[:3],
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.BlobIDs{},
+			},
+			suite_cancun.SendBlobTransactions{ // Blob ID 0
+				TransactionCount:              1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: unknownBlobIDs[:3],
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV1, Mixed Known And Unknown Blobs",
+			About: `
+			Tests engine_getBlobsV1 with a mix of versioned hashes of blobs in
+			the blob pool and blobs unknown to the client.
+
+			Verifications performed:
+			- Known blobs are returned at the position of their versioned hash
+			- A null entry is returned at the position of each unknown blob
+			`,
+			MainFork: config.Cancun,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob IDs 0-3
+				TransactionCount:              2,
+				BlobsPerTransaction:           2,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.BlobIDs{unknownBlobIDs[0], 0, unknownBlobIDs[1], 3, 1, unknownBlobIDs[2]},
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.BlobIDs{3, unknownBlobIDs[0], unknownBlobIDs[1], 2},
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV1, Blobs Included In Payload",
+			About: `
+			Tests engine_getBlobsV1 before and after the blob transactions are
+			included in a payload.
+
+			Verifications performed:
+			- Blobs are returned while the transactions are pending
+			- After inclusion, blobs are either null or correct, as clients
+			  are allowed to drop included blobs from the blob pool
+			- Blobs of pending transactions are still returned when requested
+			  along with included blobs
+			`,
+			MainFork: config.Cancun,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob IDs 0-3
+				TransactionCount:              2,
+				BlobsPerTransaction:           2,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.GetBlobList(0, 4),
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: 4,
+				ExpectedBlobs:             helper.GetBlobList(0, 4),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.GetBlobList(0, 4),
+			},
+			suite_cancun.SendBlobTransactions{ // Blob IDs 4-5
+				TransactionCount:              1,
+				BlobsPerTransaction:           2,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.BlobIDs{5, 0, 4, 1, unknownBlobIDs[0]},
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV1, Replaced Blob Transaction",
+			About: `
+			Tests engine_getBlobsV1 after a blob transaction is evicted from
+			the blob pool by a replacement transaction with the same nonce and
+			higher fees.
+
+			Verifications performed:
+			- Blobs of the evicted transaction are returned as null
+			- Blobs of the replacement transaction are returned
+			`,
+			MainFork: config.Cancun,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob IDs 0-1
+				TransactionCount:              1,
+				BlobsPerTransaction:           2,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+				BlobTransactionGasFeeCap:      big.NewInt(1e9),
+				BlobTransactionGasTipCap:      big.NewInt(1e9),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.GetBlobList(0, 2),
+			},
+			suite_cancun.SendBlobTransactions{ // Blob IDs 2-3
+				TransactionCount:              1,
+				BlobsPerTransaction:           2,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1e2),
+				BlobTransactionGasFeeCap:      big.NewInt(1e10),
+				BlobTransactionGasTipCap:      big.NewInt(1e10),
+				ReplaceTransactions:           true,
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.GetBlobList(0, 4),
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: 2,
+				ExpectedBlobs:             helper.GetBlobList(2, 2),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.GetBlobList(0, 4),
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV1, Max Request Size",
+			About: `
+			Tests engine_getBlobsV1 with the maximum number of versioned hashes
+			clients must support, and with one more.
+
+			Verifications performed:
+			- A request of 128 versioned hashes is served
+			- A request of 129 versioned hashes fails with -38004: Too large request
+			`,
+			MainFork: config.Cancun,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob IDs 0-1
+				TransactionCount:              1,
+				BlobsPerTransaction:           2,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: append(helper.GetBlobList(0, 2), unknownBlobIDs[:MAX_REQUEST_BLOB_COUNT-2]...),
+			},
+			GetBlobs{
+				Version:       1,
+				BlobIDs:       append(helper.GetBlobList(0, 2), unknownBlobIDs[:MAX_REQUEST_BLOB_COUNT-1]...),
+				ExpectedError: globals.TOO_LARGE_REQUEST,
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV1 After Osaka",
+			About: `
+			Tests that engine_getBlobsV1 is rejected after Osaka.
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob ID 0
+				TransactionCount:              1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version:       1,
+				BlobIDs:       helper.GetBlobList(0, 1),
+				ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+			},
+		},
+	},
+
+	// engine_getBlobsV2
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV2 Before Osaka",
+			About: `
+			Tests that engine_getBlobsV2 is rejected before Osaka, and that
+			engine_getBlobsV1 is accepted.
+			`,
+			MainFork:   config.Osaka,
+			ForkHeight: 2,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob ID 0
+				TransactionCount:              1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version:       2,
+				BlobIDs:       helper.GetBlobList(0, 1),
+				ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+			},
+			GetBlobs{
+				Version: 1,
+				BlobIDs: helper.GetBlobList(0, 1),
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV2, Known Blobs",
+			About: `
+			Tests engine_getBlobsV2 with versioned hashes of blobs in the blob
+			pool of the client.
+
+			Verifications performed:
+			- Blobs are returned in the order of the request
+			- Cell KZG proofs of each blob are valid for its commitment
+			- After inclusion, the response is either null or correct
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob IDs 0-5
+				TransactionCount:              2,
+				BlobsPerTransaction:           3,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.GetBlobList(0, 6),
+			},
+			// Subset, out of order
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.BlobIDs{4, 0, 5, 2},
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: 6,
+				ExpectedBlobs:             helper.GetBlobList(0, 6),
+			},
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.GetBlobList(0, 6),
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV2, Unknown And Mixed Blobs",
+			About: `
+			Tests engine_getBlobsV2 with versioned hashes of blobs unknown to
+			the client, alone and mixed with blobs in the blob pool.
+
+			Verifications performed:
+			- A null response is returned if any requested blob is unknown
+			- An empty request returns an empty response
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			GetBlobs{
+				Version: 2,
+				BlobIDs: unknownBlobIDs[:2],
+			},
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.BlobIDs{},
+			},
+			suite_cancun.SendBlobTransactions{ // Blob IDs 0-1
+				TransactionCount:              1,
+				BlobsPerTransaction:           2,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+			},
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.BlobIDs{0, unknownBlobIDs[0], 1},
+			},
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.BlobIDs{1, 0},
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV2, Replaced Blob Transaction",
+			About: `
+			Tests engine_getBlobsV2 after a blob transaction is evicted from
+			the blob pool by a replacement transaction with the same nonce and
+			higher fees.
+
+			Verifications performed:
+			- A null response is returned if any blob of the evicted
+			  transaction is requested
+			- Blobs of the replacement transaction are returned
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{ // Blob ID 0
+				TransactionCount:              1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1),
+				BlobTransactionGasFeeCap:      big.NewInt(1e9),
+				BlobTransactionGasTipCap:      big.NewInt(1e9),
+			},
+			suite_cancun.SendBlobTransactions{ // Blob ID 1
+				TransactionCount:              1,
+				BlobTransactionMaxBlobGasCost: big.NewInt(1e2),
+				BlobTransactionGasFeeCap:      big.NewInt(1e10),
+				BlobTransactionGasTipCap:      big.NewInt(1e10),
+				ReplaceTransactions:           true,
+			},
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.BlobIDs{0, 1},
+			},
+			GetBlobs{
+				Version: 2,
+				BlobIDs: helper.BlobIDs{1},
+			},
+		},
+	},
+
+	&suite_cancun.CancunBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Tags: []string{test.TagBlob},
+			Name: "GetBlobsV2, Max Request Size",
+			About: `
+			Tests engine_getBlobsV2 with the maximum number of versioned hashes
+			clients must support, and with one more.
+
+			Verifications performed:
+			- A request of 128 versioned hashes is served
+			- A request of 129 versioned hashes fails with -38004: Too large request
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			GetBlobs{
+				Version: 2,
+				BlobIDs: unknownBlobIDs[:MAX_REQUEST_BLOB_COUNT],
+			},
+			GetBlobs{
+				Version:       2,
+				BlobIDs:       unknownBlobIDs[:MAX_REQUEST_BLOB_COUNT+1],
+				ExpectedError: globals.TOO_LARGE_REQUEST,
+			},
+		},
+	},
+}
@@ -0,0 +1,44 @@
+// # Test suite for Engine API tests driving pairs of different clients
+package suite_multiclient
+
+import (
+	"math/big"
+
+	suite_engine "github.com/ethereum/hive/simulators/ethereum/engine/suites/engine"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// List of the tests run on each pair of clients.
+//
+// Only tests where the payloads are built and imported by both clients are included:
+// block production, payload execution and re-orgs. Tests which send transactions to the
+// main client and expect them in the next payload are left out, because the payload
+// producer alternates between the clients, so the transactions would have to be
+// gossiped to the other client in time.
+var Tests = []test.Spec{
+	// Block production and payload execution
+	suite_engine.ReExecutePayloadTest{},
+	suite_engine.InOrderPayloadExecutionTest{},
+	suite_engine.MultiplePayloadsExtendingCanonicalChainTest{
+		SetHeadToFirstPayloadReceived: true,
+	},
+	suite_engine.MultiplePayloadsExtendingCanonicalChainTest{
+		SetHeadToFirstPayloadReceived: false,
+	},
+
+	// Re-orgs
+	suite_engine.SafeReOrgToSideChainTest{
+		BaseSpec: test.BaseSpec{
+			SlotsToSafe:      big.NewInt(1),
+			SlotsToFinalized: big.NewInt(2),
+		},
+	},
+	suite_engine.ReOrgBackToCanonicalTest{
+		BaseSpec: test.BaseSpec{
+			SlotsToSafe:      big.NewInt(10),
+			SlotsToFinalized: big.NewInt(20),
+			TimeoutSeconds:   60,
+		},
+		ReOrgDepth: 5,
+	},
+}
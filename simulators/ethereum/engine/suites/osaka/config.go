@@ -0,0 +1,36 @@
+package suite_osaka
+
+import (
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Contains the base spec for all osaka tests.
+type OsakaBaseSpec struct {
+	test.BaseSpec
+	suite_cancun.TestSequence
+	// Blob schedule of the Osaka fork, the fork default is used if nil
+	BlobConfig *params.BlobConfig
+}
+
+// Sets the blob schedule of the test on the fork config.
+func (s *OsakaBaseSpec) GetForkConfig() *config.ForkConfig {
+	forkConfig := s.BaseSpec.GetForkConfig()
+	if forkConfig == nil {
+		return nil
+	}
+	forkConfig.OsakaBlobConfig = s.BlobConfig
+	return forkConfig
+}
+
+// Derives the tags of the test from the steps of its sequence.
+func (s *OsakaBaseSpec) GetTags() []string {
+	return test.AppendTags(s.BaseSpec.GetTags(), s.TestSequence.StepTags()...)
+}
+
+// Base test case execution procedure for osaka tests.
+func (s *OsakaBaseSpec) Execute(t *test.Env) {
+	s.TestSequence.Run(t)
+}
@@ -0,0 +1,187 @@
+// # Test suite for osaka tests
+package suite_osaka
+
+import (
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/osaka"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	suite_engine "github.com/ethereum/hive/simulators/ethereum/engine/suites/engine"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Execution specification reference:
+// https://github.com/ethereum/execution-apis/blob/main/src/engine/osaka.md
+
+// Blob schedule with lower limits than the Osaka default
+var customBlobConfig = &params.BlobConfig{
+	Target:         2,
+	Max:            4,
+	UpdateFraction: osaka.BLOB_GASPRICE_UPDATE_FRACTION,
+}
+
+// List of all osaka tests
+var Tests = []test.Spec{
+	&OsakaBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Blob Transactions On Osaka Transition, Prague Genesis",
+			About: `
+			Tests the Osaka fork since Block 2.
+
+			Verifications performed:
+			- Correct implementation of Engine API changes for Osaka:
+			  - engine_getPayloadV5 returns the cell proofs of each blob
+			- Blob transactions included before and after the fork
+			`,
+			MainFork:   config.Osaka,
+			ForkHeight: 2,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			// First payload is still on Prague, one proof per blob
+			suite_cancun.SendBlobTransactions{
+				TransactionCount:    1,
+				BlobsPerTransaction: 2,
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: 2,
+			},
+			// First payload on Osaka
+			suite_cancun.NewPayloads{},
+			// Blob transactions sent after the fork contain cell proofs
+			suite_cancun.SendBlobTransactions{
+				TransactionCount:    2,
+				BlobsPerTransaction: 2,
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: 4,
+			},
+		},
+	},
+
+	// Engine API versions
+	&OsakaBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "GetPayloadV4 After Osaka",
+			About: `
+			Tests that engine_getPayloadV4 is rejected for payloads after Osaka.
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				GetPayloadCustomizer: &helper.DowngradeGetPayloadVersion{
+					GetPayloadCustomizer: &helper.BaseGetPayloadCustomizer{
+						ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+					},
+				},
+				ExpectationDescription: `
+				GetPayloadV4 returns UnsupportedForkError on an Osaka payload.
+				`,
+			},
+		},
+	},
+
+	&OsakaBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "GetPayloadV5 Before Osaka",
+			About: `
+			Tests that engine_getPayloadV5 is rejected for payloads before Osaka.
+			`,
+			MainFork:   config.Osaka,
+			ForkHeight: 2,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				GetPayloadCustomizer: &helper.UpgradeGetPayloadVersion{
+					GetPayloadCustomizer: &helper.BaseGetPayloadCustomizer{
+						ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+					},
+				},
+				ExpectationDescription: `
+				GetPayloadV5 returns UnsupportedForkError on a Prague payload.
+				`,
+			},
+		},
+	},
+
+	// Blob count limits
+	&OsakaBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Max Blobs Per Block, Default Blob Schedule",
+			About: `
+			Tests that payloads built after Osaka contain at most the maximum
+			number of blobs of the default blob schedule.
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{
+				TransactionCount:    4,
+				BlobsPerTransaction: 3,
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: osaka.MAX_BLOBS_PER_BLOCK,
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: 4*3 - osaka.MAX_BLOBS_PER_BLOCK,
+			},
+		},
+	},
+
+	&OsakaBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Max Blobs Per Block, Custom Blob Schedule",
+			About: `
+			Tests that payloads built after Osaka contain at most the maximum
+			number of blobs of a blob schedule configured with lower limits
+			than the fork default.
+			`,
+			MainFork: config.Osaka,
+		},
+		BlobConfig: customBlobConfig,
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendBlobTransactions{
+				TransactionCount:    3,
+				BlobsPerTransaction: 2,
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: uint64(customBlobConfig.Max),
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: 3*2 - uint64(customBlobConfig.Max),
+			},
+		},
+	},
+
+	&OsakaBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Max Blobs Per Transaction",
+			About: `
+			Tests that blob transactions with more than the EIP-7594 maximum
+			number of blobs are rejected after Osaka.
+			`,
+			MainFork: config.Osaka,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.SendRejectedBlobTransaction{
+				BlobCount: osaka.MAX_BLOBS_PER_TX + 1,
+			},
+			suite_cancun.SendBlobTransactions{
+				TransactionCount:    1,
+				BlobsPerTransaction: osaka.MAX_BLOBS_PER_TX,
+			},
+			suite_cancun.NewPayloads{
+				ExpectedIncludedBlobCount: osaka.MAX_BLOBS_PER_TX,
+			},
+		},
+	},
+}
+
+func init() {
+	// Append all engine api tests with Osaka as main fork
+	for _, test := range suite_engine.Tests {
+		Tests = append(Tests, test.WithMainFork(config.Osaka))
+	}
+}
@@ -0,0 +1,262 @@
+package suite_perf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/hive/simulators/ethereum/engine/client"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+)
+
+// Environment variable with the latency thresholds above which the perf tests fail,
+// as a comma-separated list of `<call>:p<percentile>=<duration>`,
+// e.g. `newPayload:p99=500ms,forkchoiceUpdatedWithAttributes:p90=100ms`.
+const ThresholdsEnvVar = "HIVE_ENGINE_PERF_THRESHOLDS"
+
+// Names of the engine calls measured by the perf tests.
+const (
+	CallNewPayload                      = "newPayload"
+	CallForkchoiceUpdated               = "forkchoiceUpdated"
+	CallForkchoiceUpdatedWithAttributes = "forkchoiceUpdatedWithAttributes"
+	CallGetPayload                      = "getPayload"
+)
+
+// All the calls measured, in the order they are reported.
+var AllCalls = []string{
+	CallForkchoiceUpdatedWithAttributes,
+	CallGetPayload,
+	CallNewPayload,
+	CallForkchoiceUpdated,
+}
+
+// Percentiles reported for each call.
+var reportedPercentiles = []float64{50, 90, 99}
+
+// Records the latency of the engine calls of a client, grouped by the step of the
+// test in which the calls are made.
+type LatencyRecorder struct {
+	mutex     sync.Mutex
+	step      string
+	steps     []string
+	latencies map[string]map[string][]time.Duration
+}
+
+func NewLatencyRecorder() *LatencyRecorder {
+	return &LatencyRecorder{
+		latencies: make(map[string]map[string][]time.Duration),
+	}
+}
+
+// Sets the step to which the calls recorded from now on belong.
+func (r *LatencyRecorder) SetStep(step string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.step = step
+	if _, ok := r.latencies[step]; !ok {
+		r.steps = append(r.steps, step)
+		r.latencies[step] = make(map[string][]time.Duration)
+	}
+}
+
+func (r *LatencyRecorder) Record(call string, latency time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.latencies[r.step]; !ok {
+		r.steps = append(r.steps, r.step)
+		r.latencies[r.step] = make(map[string][]time.Duration)
+	}
+	r.latencies[r.step][call] = append(r.latencies[r.step][call], latency)
+}
+
+// Returns the latency statistics of each call on each step, in the order the steps
+// were set.
+func (r *LatencyRecorder) Stats() []LatencyStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stats := make([]LatencyStats, 0)
+	for _, step := range r.steps {
+		for _, call := range AllCalls {
+			if latencies := r.latencies[step][call]; len(latencies) > 0 {
+				stats = append(stats, NewLatencyStats(step, call, latencies))
+			}
+		}
+	}
+	return stats
+}
+
+// Latency distribution of a call on a step of a test, in milliseconds.
+type LatencyStats struct {
+	Step        string             `json:"step"`
+	Call        string             `json:"call"`
+	Count       int                `json:"count"`
+	Mean        float64            `json:"mean_ms"`
+	Max         float64            `json:"max_ms"`
+	Percentiles map[string]float64 `json:"percentiles_ms"`
+}
+
+func NewLatencyStats(step string, call string, latencies []time.Duration) LatencyStats {
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	stats := LatencyStats{
+		Step:        step,
+		Call:        call,
+		Count:       len(sorted),
+		Mean:        milliseconds(total / time.Duration(len(sorted))),
+		Max:         milliseconds(sorted[len(sorted)-1]),
+		Percentiles: make(map[string]float64),
+	}
+	for _, p := range reportedPercentiles {
+		stats.Percentiles[percentileName(p)] = milliseconds(Percentile(sorted, p))
+	}
+	return stats
+}
+
+// Returns the percentile of the sorted latencies using the nearest-rank method.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+func percentileName(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Maximum latency allowed for a percentile of a call.
+type Threshold struct {
+	Call       string
+	Percentile float64
+	Max        time.Duration
+}
+
+func (t Threshold) String() string {
+	return fmt.Sprintf("%s:%s=%s", t.Call, percentileName(t.Percentile), t.Max)
+}
+
+// Parses a comma-separated list of thresholds in the format of the
+// HIVE_ENGINE_PERF_THRESHOLDS environment variable.
+func ParseThresholds(value string) ([]Threshold, error) {
+	thresholds := make([]Threshold, 0)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, max, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid threshold %q: missing duration", entry)
+		}
+		call, percentile, ok := strings.Cut(key, ":")
+		if !ok || !strings.HasPrefix(percentile, "p") {
+			return nil, fmt.Errorf("invalid threshold %q: missing percentile", entry)
+		}
+		threshold := Threshold{}
+		for _, c := range AllCalls {
+			if strings.EqualFold(c, call) {
+				threshold.Call = c
+			}
+		}
+		if threshold.Call == "" {
+			return nil, fmt.Errorf("invalid threshold %q: unknown call %q, must be one of %s", entry, call, strings.Join(AllCalls, ","))
+		}
+		p, err := strconv.ParseFloat(percentile[1:], 64)
+		if err != nil || p <= 0 || p > 100 {
+			return nil, fmt.Errorf("invalid threshold %q: invalid percentile %q", entry, percentile)
+		}
+		threshold.Percentile = p
+		if threshold.Max, err = time.ParseDuration(max); err != nil {
+			return nil, fmt.Errorf("invalid threshold %q: %v", entry, err)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// Returns the thresholds configured in the environment of the simulator.
+func EnvThresholds() ([]Threshold, error) {
+	return ParseThresholds(os.Getenv(ThresholdsEnvVar))
+}
+
+// Returns a description of each threshold exceeded by the recorded latencies.
+func (r *LatencyRecorder) CheckThresholds(thresholds []Threshold) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	violations := make([]string, 0)
+	for _, step := range r.steps {
+		for _, threshold := range thresholds {
+			latencies := r.latencies[step][threshold.Call]
+			if len(latencies) == 0 {
+				continue
+			}
+			sorted := append([]time.Duration{}, latencies...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			if latency := Percentile(sorted, threshold.Percentile); latency > threshold.Max {
+				violations = append(violations, fmt.Sprintf("%s: %s %s latency %s exceeds %s", step, threshold.Call, percentileName(threshold.Percentile), latency, threshold.Max))
+			}
+		}
+	}
+	return violations
+}
+
+// Engine client which records the latency of the calls made by the CL Mock to
+// build and import payloads.
+type TimedEngineClient struct {
+	client.EngineClient
+	Recorder *LatencyRecorder
+}
+
+func (ec *TimedEngineClient) ForkchoiceUpdated(ctx context.Context, version int, fcState *api.ForkchoiceStateV1, pAttributes *typ.PayloadAttributes) (api.ForkChoiceResponse, error) {
+	start := time.Now()
+	resp, err := ec.EngineClient.ForkchoiceUpdated(ctx, version, fcState, pAttributes)
+	if err == nil {
+		call := CallForkchoiceUpdated
+		if pAttributes != nil {
+			call = CallForkchoiceUpdatedWithAttributes
+		}
+		ec.Recorder.Record(call, time.Since(start))
+	}
+	return resp, err
+}
+
+func (ec *TimedEngineClient) GetPayload(ctx context.Context, version int, payloadId *api.PayloadID) (typ.ExecutableData, *big.Int, *typ.BlobsBundle, *bool, error) {
+	start := time.Now()
+	payload, blockValue, blobsBundle, shouldOverrideBuilder, err := ec.EngineClient.GetPayload(ctx, version, payloadId)
+	if err == nil {
+		ec.Recorder.Record(CallGetPayload, time.Since(start))
+	}
+	return payload, blockValue, blobsBundle, shouldOverrideBuilder, err
+}
+
+func (ec *TimedEngineClient) NewPayload(ctx context.Context, version int, payload *typ.ExecutableData) (api.PayloadStatusV1, error) {
+	start := time.Now()
+	status, err := ec.EngineClient.NewPayload(ctx, version, payload)
+	if err == nil {
+		ec.Recorder.Record(CallNewPayload, time.Since(start))
+	}
+	return status, err
+}
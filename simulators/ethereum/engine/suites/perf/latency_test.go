@@ -0,0 +1,89 @@
+package suite_perf
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 0)
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{p: 1, want: 1 * time.Millisecond},
+		{p: 50, want: 5 * time.Millisecond},
+		{p: 90, want: 9 * time.Millisecond},
+		{p: 99, want: 10 * time.Millisecond},
+		{p: 100, want: 10 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if got := Percentile(sorted, test.p); got != test.want {
+			t.Errorf("p%v: got %v, want %v", test.p, got, test.want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("empty: got %v, want 0", got)
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	got, err := ParseThresholds(" newPayload:p99=500ms, ForkchoiceUpdatedWithAttributes:p90=1s,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Threshold{
+		{Call: CallNewPayload, Percentile: 99, Max: 500 * time.Millisecond},
+		{Call: CallForkchoiceUpdatedWithAttributes, Percentile: 90, Max: time.Second},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong thresholds\ngot:  %v\nwant: %v", got, want)
+	}
+
+	for _, invalid := range []string{
+		"newPayload=1s",
+		"newPayload:p99",
+		"newPayload:99=1s",
+		"newPayload:p0=1s",
+		"newPayload:p101=1s",
+		"getBlobs:p50=1s",
+		"newPayload:p50=1",
+	} {
+		if _, err := ParseThresholds(invalid); err == nil {
+			t.Errorf("%q: expected error", invalid)
+		}
+	}
+}
+
+func TestCheckThresholds(t *testing.T) {
+	r := NewLatencyRecorder()
+	r.SetStep("gas=0")
+	for i := 1; i <= 10; i++ {
+		r.Record(CallNewPayload, time.Duration(i)*time.Millisecond)
+		r.Record(CallGetPayload, time.Millisecond)
+	}
+	r.SetStep("gas=1000")
+	r.Record(CallNewPayload, 20*time.Millisecond)
+
+	violations := r.CheckThresholds([]Threshold{
+		{Call: CallNewPayload, Percentile: 90, Max: 9 * time.Millisecond},
+		{Call: CallGetPayload, Percentile: 50, Max: 0},
+		{Call: CallForkchoiceUpdated, Percentile: 50, Max: 0},
+	})
+	want := []string{
+		"gas=0: getPayload p50 latency 1ms exceeds 0s",
+		"gas=1000: newPayload p90 latency 20ms exceeds 9ms",
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Fatalf("wrong violations\ngot:  %q\nwant: %q", violations, want)
+	}
+
+	stats := r.Stats()
+	if len(stats) != 3 || stats[0].Call != CallGetPayload || stats[1].Call != CallNewPayload || stats[1].Percentiles["p90"] != 9 || stats[1].Mean != 5.5 {
+		t.Fatalf("wrong stats: %+v", stats)
+	}
+}
@@ -0,0 +1,228 @@
+package suite_perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/hive/simulators/ethereum/engine/clmock"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+)
+
+// Gas limit of each transaction sent to fill the payloads, which creates a contract
+// using all of it.
+const transactionGasLimit = 250000
+
+// Test that measures the latency of the engine calls made by the CL Mock to build
+// and import payloads, across steps of increasing gas usage.
+type PerfSpec struct {
+	test.BaseSpec
+	// Gas used by the transactions sent before each payload is built, one value for
+	// each step of the test
+	GasSteps []uint64
+	// Number of payloads built on each step
+	PayloadsPerStep uint64
+	// Number of blobs sent before each payload is built, one per transaction
+	BlobsPerPayload uint64
+}
+
+func (s PerfSpec) WithMainFork(fork config.Fork) test.Spec {
+	specCopy := s
+	specCopy.MainFork = fork
+	return specCopy
+}
+
+func (s PerfSpec) GetName() string {
+	name := []string{s.Name}
+	if s.GetPayloadDelay != 0 {
+		name = append(name, fmt.Sprintf("GetPayloadDelay=%ds", s.GetPayloadDelay))
+	}
+	if s.BlobsPerPayload > 0 {
+		name = append(name, fmt.Sprintf("Blobs=%d", s.BlobsPerPayload))
+	}
+	return strings.Join(name, ", ")
+}
+
+func (s PerfSpec) GetTags() []string {
+	tags := test.AppendTags(s.BaseSpec.GetTags(), test.TagPerf)
+	if s.BlobsPerPayload > 0 {
+		tags = test.AppendTags(tags, test.TagBlob)
+	}
+	return tags
+}
+
+func (s PerfSpec) GetGasSteps() []uint64 {
+	if len(s.GasSteps) == 0 {
+		return []uint64{0}
+	}
+	return s.GasSteps
+}
+
+func (s PerfSpec) GetPayloadsPerStep() uint64 {
+	if s.PayloadsPerStep == 0 {
+		return 5
+	}
+	return s.PayloadsPerStep
+}
+
+func (s PerfSpec) stepName(gas uint64) string {
+	if s.BlobsPerPayload > 0 {
+		return fmt.Sprintf("gas=%d,blobs=%d", gas, s.BlobsPerPayload)
+	}
+	return fmt.Sprintf("gas=%d", gas)
+}
+
+// Sends the transactions to fill the next payload with the given gas and the blobs
+// of the spec, and returns the number of transactions sent.
+func (s PerfSpec) sendTransactions(t *test.Env, gas uint64, blobID *helper.BlobID) uint64 {
+	txCount := gas / transactionGasLimit
+	if txCount > 0 {
+		_, err := t.SendNextTransactions(
+			t.TestContext,
+			t.CLMock.NextBlockProducer,
+			&helper.BigContractTransactionCreator{
+				BaseTransactionCreator: helper.BaseTransactionCreator{
+					GasLimit:   transactionGasLimit,
+					TxType:     t.TestTransactionType,
+					ForkConfig: t.ForkConfig,
+				},
+			},
+			txCount,
+		)
+		if err != nil {
+			t.Fatalf("FAIL (%s): Error trying to send transactions: %v", t.TestName, err)
+		}
+	}
+	for i := uint64(0); i < s.BlobsPerPayload; i++ {
+		// Blob transactions are sent from the last accounts, which are not used by the
+		// other transactions, because clients do not accept blob and non-blob
+		// transactions from the same account at the same time
+		_, err := t.SendTransaction(
+			t.TestContext,
+			globals.TestAccounts[globals.TestAccountCount-1-i],
+			t.CLMock.NextBlockProducer,
+			&helper.BlobTransactionCreator{
+				To:         &globals.PrevRandaoContractAddr,
+				GasLimit:   100000,
+				BlobGasFee: globals.BlobGasPrice,
+				BlobID:     *blobID,
+				BlobCount:  1,
+				ForkConfig: t.ForkConfig,
+			},
+		)
+		if err != nil {
+			t.Fatalf("FAIL (%s): Error trying to send blob transaction: %v", t.TestName, err)
+		}
+		*blobID++
+	}
+	return txCount + s.BlobsPerPayload
+}
+
+// Contents of the payloads built by the client on a step, compared to the
+// transactions sent before each payload was requested.
+type BuildStats struct {
+	Step               string  `json:"step"`
+	GetPayloadDelay    float64 `json:"get_payload_delay_ms"`
+	Payloads           int     `json:"payloads"`
+	MeanGasUsed        uint64  `json:"mean_gas_used"`
+	MeanBlobGasUsed    uint64  `json:"mean_blob_gas_used"`
+	IncompletePayloads int     `json:"incomplete_payloads"`
+
+	totalGasUsed     uint64
+	totalBlobGasUsed uint64
+}
+
+// Adds a payload built after sending the given number of transactions.
+func (b *BuildStats) add(payload *typ.ExecutableData, txCount uint64) {
+	b.Payloads++
+	b.totalGasUsed += payload.GasUsed
+	if payload.BlobGasUsed != nil {
+		b.totalBlobGasUsed += *payload.BlobGasUsed
+	}
+	b.MeanGasUsed = b.totalGasUsed / uint64(b.Payloads)
+	b.MeanBlobGasUsed = b.totalBlobGasUsed / uint64(b.Payloads)
+	if uint64(len(payload.Transactions)) < txCount {
+		b.IncompletePayloads++
+	}
+}
+
+// Report of a perf test, logged as a single JSON line prefixed with "PERF REPORT".
+type PerfReport struct {
+	Test       string         `json:"test"`
+	Client     string         `json:"client"`
+	Fork       config.Fork    `json:"fork"`
+	Latencies  []LatencyStats `json:"latencies"`
+	Builds     []BuildStats   `json:"builds"`
+	Thresholds []string       `json:"thresholds,omitempty"`
+	Violations []string       `json:"violations,omitempty"`
+}
+
+func (s PerfSpec) Execute(t *test.Env) {
+	thresholds, err := EnvThresholds()
+	if err != nil {
+		t.Fatalf("FAIL (%s): Invalid %s: %v", t.TestName, ThresholdsEnvVar, err)
+	}
+
+	// Replace the client in the CL Mock with one that records the latency of the calls
+	recorder := NewLatencyRecorder()
+	timedEngine := &TimedEngineClient{
+		EngineClient: t.Engine,
+		Recorder:     recorder,
+	}
+	t.CLMock.RemoveEngineClient(t.Engine)
+	t.CLMock.AddEngineClient(timedEngine)
+
+	builds := make([]BuildStats, 0)
+	blobID := helper.BlobID(0)
+	for _, gas := range s.GetGasSteps() {
+		step := s.stepName(gas)
+		recorder.SetStep(step)
+		build := BuildStats{
+			Step:            step,
+			GetPayloadDelay: milliseconds(t.CLMock.PayloadProductionClientDelay),
+		}
+		for i := uint64(0); i < s.GetPayloadsPerStep(); i++ {
+			var txCount uint64
+			t.CLMock.ProduceSingleBlock(clmock.BlockProcessCallbacks{
+				OnPayloadProducerSelected: func() {
+					txCount = s.sendTransactions(t, gas, &blobID)
+				},
+				OnGetPayload: func() {
+					build.add(&t.CLMock.LatestPayloadBuilt, txCount)
+				},
+			})
+		}
+		builds = append(builds, build)
+	}
+
+	report := PerfReport{
+		Test:       t.TestName,
+		Client:     t.Client.Type,
+		Fork:       s.GetMainFork(),
+		Latencies:  recorder.Stats(),
+		Builds:     builds,
+		Violations: recorder.CheckThresholds(thresholds),
+	}
+	for _, threshold := range thresholds {
+		report.Thresholds = append(report.Thresholds, threshold.String())
+	}
+	for _, stats := range report.Latencies {
+		t.Logf("INFO (%s): %s, %s: count=%d, mean=%.3fms, p50=%.3fms, p90=%.3fms, p99=%.3fms, max=%.3fms", t.TestName, stats.Step, stats.Call, stats.Count, stats.Mean, stats.Percentiles["p50"], stats.Percentiles["p90"], stats.Percentiles["p99"], stats.Max)
+	}
+	for _, build := range builds {
+		t.Logf("INFO (%s): %s, built payloads: count=%d, mean gas used=%d, mean blob gas used=%d, incomplete=%d", t.TestName, build.Step, build.Payloads, build.MeanGasUsed, build.MeanBlobGasUsed, build.IncompletePayloads)
+	}
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("FAIL (%s): Unable to marshal perf report: %v", t.TestName, err)
+	}
+	t.Logf("PERF REPORT (%s): %s", t.TestName, reportJSON)
+
+	if len(report.Violations) > 0 {
+		t.Fatalf("FAIL (%s): Latency thresholds exceeded:\n%s", t.TestName, strings.Join(report.Violations, "\n"))
+	}
+}
@@ -0,0 +1,96 @@
+// # Test suite for Engine API latency measurements
+package suite_perf
+
+import (
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Gas used on each step of the tests, limited by the gas limit of the test genesis
+// of ~3.14M gas.
+var gasSteps = []uint64{0, 750000, 1500000, 3000000}
+
+// List of all perf tests
+var Tests = []test.Spec{
+	PerfSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Engine API Latency, Increasing Gas Usage",
+			About: `
+			Builds payloads with transactions using an increasing amount of
+			gas, and measures the latency of each engine call made by the CL
+			Mock to build and import the payloads: forkchoiceUpdated with
+			payload attributes, getPayload, newPayload and forkchoiceUpdated.
+
+			The mean, maximum and percentiles of the latency of each call on
+			each gas step are reported as a JSON line prefixed with
+			"PERF REPORT". The test fails if any of the thresholds configured
+			with HIVE_ENGINE_PERF_THRESHOLDS is exceeded.
+			`,
+			MainFork:       config.Cancun,
+			TimeoutSeconds: 120,
+		},
+		GasSteps: gasSteps,
+	},
+	PerfSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Engine API Latency, Increasing Gas Usage",
+			About: `
+			Same as the test above, on Prague.
+			`,
+			MainFork:       config.Prague,
+			TimeoutSeconds: 120,
+		},
+		GasSteps: gasSteps,
+	},
+
+	// Payload build time
+	PerfSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Payload Build Time",
+			About: `
+			Builds payloads with transactions using an increasing amount of
+			gas, requesting each payload two seconds after the forkchoiceUpdated
+			with payload attributes instead of one.
+
+			Besides the latencies, the mean gas used of the built payloads and
+			the number of payloads which did not include all the transactions
+			sent before the build started are reported for each gas step, to
+			compare with the results of the default delay.
+			`,
+			MainFork:        config.Cancun,
+			GetPayloadDelay: 2,
+			TimeoutSeconds:  180,
+		},
+		GasSteps: gasSteps,
+	},
+
+	// Blob-heavy payloads
+	PerfSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Engine API Latency, Blob Payloads",
+			About: `
+			Builds payloads with the maximum number of blobs allowed per block
+			on Cancun, with and without other transactions, and measures the
+			latency of each engine call.
+			`,
+			MainFork:       config.Cancun,
+			TimeoutSeconds: 120,
+		},
+		GasSteps:        []uint64{0, 1500000},
+		BlobsPerPayload: 6,
+	},
+	PerfSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Engine API Latency, Blob Payloads",
+			About: `
+			Builds payloads with the maximum number of blobs allowed per block
+			on Prague, with and without other transactions, and measures the
+			latency of each engine call.
+			`,
+			MainFork:       config.Prague,
+			TimeoutSeconds: 120,
+		},
+		GasSteps:        []uint64{0, 1500000},
+		BlobsPerPayload: 9,
+	},
+}
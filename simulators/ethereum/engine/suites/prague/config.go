@@ -0,0 +1,33 @@
+package suite_prague
+
+import (
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Contains the base spec for all prague tests.
+type PragueBaseSpec struct {
+	test.BaseSpec
+	suite_cancun.TestSequence
+}
+
+// Adds the prague test accounts to the genesis.
+func (ps *PragueBaseSpec) GetGenesis() *core.Genesis {
+	genesis := ps.BaseSpec.GetGenesis()
+	if err := prague.ConfigTestAccounts(genesis); err != nil {
+		panic(err)
+	}
+	return genesis
+}
+
+// Derives the tags of the test from the steps of its sequence.
+func (ps *PragueBaseSpec) GetTags() []string {
+	return test.AppendTags(ps.BaseSpec.GetTags(), ps.TestSequence.StepTags()...)
+}
+
+// Base test case execution procedure for prague tests.
+func (ps *PragueBaseSpec) Execute(t *test.Env) {
+	ps.TestSequence.Run(t)
+}
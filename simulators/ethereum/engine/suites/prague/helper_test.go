@@ -0,0 +1,25 @@
+package suite_prague
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
+)
+
+func TestDepositEventData(t *testing.T) {
+	for _, index := range []uint64{0, 1, 1000} {
+		request := DepositRequestData(index)
+		if len(request) != prague.DEPOSIT_REQUEST_SIZE {
+			t.Fatalf("wrong request size: %d", len(request))
+		}
+		parsed, err := types.DepositLogToRequest(DepositEventData(request))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(parsed, request) {
+			t.Fatalf("deposit %d mismatch:\nwant %x\ngot  %x", index, request, parsed)
+		}
+	}
+}
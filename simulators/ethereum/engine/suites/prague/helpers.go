@@ -0,0 +1,91 @@
+package suite_prague
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
+)
+
+var (
+	// Amount of every deposit, in gwei
+	DEPOSIT_AMOUNT = uint64(32_000_000_000)
+	// Fee sent along with withdrawal and consolidation requests, it is well above
+	// the minimum fee of the system contracts for the number of requests sent in
+	// these tests.
+	REQUEST_FEE = common.Big256
+)
+
+// Returns a deterministic validator public key for the given index.
+func ValidatorPubkey(index uint64) []byte {
+	pubkey := bytes.Repeat([]byte{0xaa}, 40)
+	return binary.BigEndian.AppendUint64(pubkey, index)
+}
+
+// Returns the data of the deposit request with the given deposit index, as it
+// appears in the execution requests of the payload.
+func DepositRequestData(index uint64) []byte {
+	data := make([]byte, 0, prague.DEPOSIT_REQUEST_SIZE)
+	data = append(data, ValidatorPubkey(index)...)
+	withdrawalCredentials := make([]byte, 32)
+	withdrawalCredentials[0] = 0x01
+	binary.BigEndian.PutUint64(withdrawalCredentials[24:], index)
+	data = append(data, withdrawalCredentials...)
+	data = binary.LittleEndian.AppendUint64(data, DEPOSIT_AMOUNT)
+	data = append(data, bytes.Repeat([]byte{0xbb}, 96)...) // signature
+	data = binary.LittleEndian.AppendUint64(data, index)
+	return data
+}
+
+// Returns the ABI encoded data of the DepositEvent log for the given deposit
+// request data. This is the call data of the deposit contract deployed at genesis.
+func DepositEventData(request []byte) []byte {
+	fields := [][]byte{
+		request[0:48],    // pubkey
+		request[48:80],   // withdrawal_credentials
+		request[80:88],   // amount
+		request[88:184],  // signature
+		request[184:192], // index
+	}
+	var (
+		head = make([]byte, 0, 32*len(fields))
+		tail []byte
+	)
+	for _, field := range fields {
+		head = append(head, abiWord(uint64(32*len(fields)+len(tail)))...)
+		tail = append(tail, abiWord(uint64(len(field)))...)
+		tail = append(tail, common.RightPadBytes(field, (len(field)+31)/32*32)...)
+	}
+	return append(head, tail...)
+}
+
+func abiWord(v uint64) []byte {
+	word := make([]byte, 32)
+	binary.BigEndian.PutUint64(word[24:], v)
+	return word
+}
+
+// Returns the call data of an EIP-7002 withdrawal request.
+func WithdrawalRequestCallData(pubkey []byte, amount uint64) []byte {
+	return binary.BigEndian.AppendUint64(common.CopyBytes(pubkey), amount)
+}
+
+// Returns the data of a withdrawal request, as it appears in the execution
+// requests of the payload.
+func WithdrawalRequestData(source common.Address, pubkey []byte, amount uint64) []byte {
+	data := append(source.Bytes(), pubkey...)
+	return binary.LittleEndian.AppendUint64(data, amount)
+}
+
+// Returns the call data of an EIP-7251 consolidation request.
+func ConsolidationRequestCallData(sourcePubkey []byte, targetPubkey []byte) []byte {
+	return append(common.CopyBytes(sourcePubkey), targetPubkey...)
+}
+
+// Returns the data of a consolidation request, as it appears in the execution
+// requests of the payload.
+func ConsolidationRequestData(source common.Address, sourcePubkey []byte, targetPubkey []byte) []byte {
+	data := append(source.Bytes(), sourcePubkey...)
+	return append(data, targetPubkey...)
+}
@@ -0,0 +1,237 @@
+package suite_prague
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/hive/simulators/ethereum/engine/client"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+	"github.com/holiman/uint256"
+)
+
+// Returns the client to send the transactions to.
+func getEngine(t *suite_cancun.TestContext, clientIndex uint64) (client.EngineClient, error) {
+	if clientIndex >= uint64(len(t.Engines)) {
+		return nil, fmt.Errorf("invalid client index %d", clientIndex)
+	}
+	return t.Engines[clientIndex], nil
+}
+
+// A step that sends EIP-6110 deposits to the deposit contract
+type SendDepositRequests struct {
+	// Number of deposit transactions to send
+	TransactionCount uint64
+	// Account index to send the transactions from
+	AccountIndex uint64
+	// Client index to send the transactions to
+	ClientIndex uint64
+}
+
+func (step SendDepositRequests) Execute(t *suite_cancun.TestContext) error {
+	engine, err := getEngine(t, step.ClientIndex)
+	if err != nil {
+		return err
+	}
+	sender := globals.TestAccounts[step.AccountIndex]
+	for i := uint64(0); i < step.TransactionCount; i++ {
+		request := DepositRequestData(t.RequestCount[prague.DEPOSIT_REQUEST_TYPE])
+		tx, err := t.SendTransaction(t.TestContext, sender, engine, &helper.BaseTransactionCreator{
+			Recipient: &prague.DEPOSIT_CONTRACT_ADDRESS,
+			GasLimit:  100000,
+			Payload:   DepositEventData(request),
+			TxType:    helper.DynamicFeeTxOnly,
+		})
+		if err != nil {
+			return fmt.Errorf("error sending deposit transaction: %v", err)
+		}
+		t.AddPendingRequest(tx, prague.DEPOSIT_REQUEST_TYPE, request)
+	}
+	return nil
+}
+
+func (step SendDepositRequests) Description() string {
+	return fmt.Sprintf("SendDepositRequests: %d deposits from account %d", step.TransactionCount, step.AccountIndex)
+}
+
+// A step that sends EIP-7002 withdrawal requests to the system contract
+type SendWithdrawalRequests struct {
+	// Number of withdrawal request transactions to send
+	TransactionCount uint64
+	// Amount to withdraw, in gwei. Zero requests a full exit.
+	Amount uint64
+	// Account index to send the transactions from
+	AccountIndex uint64
+	// Client index to send the transactions to
+	ClientIndex uint64
+}
+
+func (step SendWithdrawalRequests) Execute(t *suite_cancun.TestContext) error {
+	engine, err := getEngine(t, step.ClientIndex)
+	if err != nil {
+		return err
+	}
+	sender := globals.TestAccounts[step.AccountIndex]
+	for i := uint64(0); i < step.TransactionCount; i++ {
+		pubkey := ValidatorPubkey(i)
+		tx, err := t.SendTransaction(t.TestContext, sender, engine, &helper.BaseTransactionCreator{
+			Recipient: &prague.WITHDRAWAL_REQUEST_ADDRESS,
+			GasLimit:  200000,
+			Amount:    REQUEST_FEE,
+			Payload:   WithdrawalRequestCallData(pubkey, step.Amount),
+			TxType:    helper.DynamicFeeTxOnly,
+		})
+		if err != nil {
+			return fmt.Errorf("error sending withdrawal request transaction: %v", err)
+		}
+		t.AddPendingRequest(tx, prague.WITHDRAWAL_REQUEST_TYPE, WithdrawalRequestData(sender.GetAddress(), pubkey, step.Amount))
+	}
+	return nil
+}
+
+func (step SendWithdrawalRequests) Description() string {
+	return fmt.Sprintf("SendWithdrawalRequests: %d requests from account %d, amount %d", step.TransactionCount, step.AccountIndex, step.Amount)
+}
+
+// A step that sends EIP-7251 consolidation requests to the system contract
+type SendConsolidationRequests struct {
+	// Number of consolidation request transactions to send
+	TransactionCount uint64
+	// Account index to send the transactions from
+	AccountIndex uint64
+	// Client index to send the transactions to
+	ClientIndex uint64
+}
+
+func (step SendConsolidationRequests) Execute(t *suite_cancun.TestContext) error {
+	engine, err := getEngine(t, step.ClientIndex)
+	if err != nil {
+		return err
+	}
+	sender := globals.TestAccounts[step.AccountIndex]
+	for i := uint64(0); i < step.TransactionCount; i++ {
+		var (
+			sourcePubkey = ValidatorPubkey(2 * i)
+			targetPubkey = ValidatorPubkey(2*i + 1)
+		)
+		tx, err := t.SendTransaction(t.TestContext, sender, engine, &helper.BaseTransactionCreator{
+			Recipient: &prague.CONSOLIDATION_REQUEST_ADDRESS,
+			GasLimit:  200000,
+			Amount:    REQUEST_FEE,
+			Payload:   ConsolidationRequestCallData(sourcePubkey, targetPubkey),
+			TxType:    helper.DynamicFeeTxOnly,
+		})
+		if err != nil {
+			return fmt.Errorf("error sending consolidation request transaction: %v", err)
+		}
+		t.AddPendingRequest(tx, prague.CONSOLIDATION_REQUEST_TYPE, ConsolidationRequestData(sender.GetAddress(), sourcePubkey, targetPubkey))
+	}
+	return nil
+}
+
+func (step SendConsolidationRequests) Description() string {
+	return fmt.Sprintf("SendConsolidationRequests: %d requests from account %d", step.TransactionCount, step.AccountIndex)
+}
+
+// A step that sends EIP-7702 set-code transactions, each one delegating a new
+// account to the test delegation target and calling it in the same transaction.
+type SendSetCodeTransactions struct {
+	// Number of set-code transactions to send
+	TransactionCount uint64
+	// Number of authorizations in every transaction
+	AuthorizationCount uint64
+	// Account index to send the transactions from
+	AccountIndex uint64
+	// Client index to send the transactions to
+	ClientIndex uint64
+}
+
+func (step SendSetCodeTransactions) GetAuthorizationCount() uint64 {
+	authorizationCount := step.AuthorizationCount
+	if authorizationCount == 0 {
+		authorizationCount = 1
+	}
+	return authorizationCount
+}
+
+func (step SendSetCodeTransactions) Execute(t *suite_cancun.TestContext) error {
+	engine, err := getEngine(t, step.ClientIndex)
+	if err != nil {
+		return err
+	}
+	sender := globals.TestAccounts[step.AccountIndex]
+	for i := uint64(0); i < step.TransactionCount; i++ {
+		var (
+			authorizations = make([]types.SetCodeAuthorization, 0)
+			authorities    = make([]common.Address, 0)
+		)
+		for a := uint64(0); a < step.GetAuthorizationCount(); a++ {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				return err
+			}
+			auth, err := types.SignSetCode(key, types.SetCodeAuthorization{
+				ChainID: *uint256.MustFromBig(globals.ChainID),
+				Address: prague.DELEGATE_TARGET_ADDRESS,
+				Nonce:   0,
+			})
+			if err != nil {
+				return err
+			}
+			authorizations = append(authorizations, auth)
+			authorities = append(authorities, crypto.PubkeyToAddress(key.PublicKey))
+		}
+		// The transaction calls the first authority, which runs the code of the
+		// delegation target in the context of the authority.
+		tx, err := t.SendTransaction(t.TestContext, sender, engine, &helper.SetCodeTransactionCreator{
+			BaseTransactionCreator: helper.BaseTransactionCreator{
+				Recipient: &authorities[0],
+				GasLimit:  100000 + 30000*uint64(len(authorizations)),
+				Amount:    big.NewInt(0),
+			},
+			AuthorizationList: authorizations,
+		})
+		if err != nil {
+			return fmt.Errorf("error sending set-code transaction: %v", err)
+		}
+		// The delegation target stores the caller in the first storage slot of
+		// the authority
+		var (
+			authority = authorities[0]
+			caller    = common.BytesToHash(sender.GetAddress().Bytes())
+		)
+		t.PendingTransactions = append(t.PendingTransactions, tx)
+		t.PendingVerifications = append(t.PendingVerifications, func(t *suite_cancun.TestContext) error {
+			r := t.TestEngine.TestStorageAt(authority, common.Hash{}, nil)
+			r.ExpectStorageEqual(caller)
+			return nil
+		})
+	}
+	return nil
+}
+
+func (step SendSetCodeTransactions) Description() string {
+	return fmt.Sprintf("SendSetCodeTransactions: %d transactions with %d authorizations from account %d", step.TransactionCount, step.GetAuthorizationCount(), step.AccountIndex)
+}
+
+// Customizer that modifies the given field of the payload to make it invalid.
+type InvalidPayloadField struct {
+	Field helper.InvalidPayloadBlockField
+}
+
+var _ helper.PayloadCustomizer = (*InvalidPayloadField)(nil)
+
+func (customizer *InvalidPayloadField) CustomizePayload(randSource *rand.Rand, basePayload *typ.ExecutableData) (*typ.ExecutableData, error) {
+	return helper.GenerateInvalidPayload(randSource, basePayload, customizer.Field)
+}
+
+func (customizer *InvalidPayloadField) GetTimestamp(basePayload *typ.ExecutableData) (uint64, error) {
+	return basePayload.Timestamp, nil
+}
@@ -0,0 +1,525 @@
+// # Test suite for prague tests
+package suite_prague
+
+import (
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config/prague"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	suite_cancun "github.com/ethereum/hive/simulators/ethereum/engine/suites/cancun"
+	suite_engine "github.com/ethereum/hive/simulators/ethereum/engine/suites/engine"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Execution specification reference:
+// https://github.com/ethereum/execution-apis/blob/main/src/engine/prague.md
+
+// Requests used to build payloads with invalid execution requests.
+var (
+	// The first deposit of a test, with a modified amount
+	modifiedDepositRequest = func() []byte {
+		request := DepositRequestData(0)
+		request[80] = request[80] + 1
+		return request
+	}()
+	// A withdrawal request that was never sent to the system contract
+	unsentWithdrawalRequest = WithdrawalRequestData(globals.TestAccounts[0].GetAddress(), ValidatorPubkey(0), 0)
+)
+
+// List of all prague tests
+var Tests = []test.Spec{
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Execution Requests On Block 1, Cancun Genesis",
+			About: `
+			Tests the Prague fork since Block 1.
+
+			Verifications performed:
+			- Correct implementation of Engine API changes for Prague:
+			  - engine_newPayloadV4, engine_getPayloadV4
+			- Empty execution requests list on payloads without requests
+			- Execution requests of all types on the first payloads after the fork
+			`,
+			MainFork:   config.Prague,
+			ForkHeight: 1,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			// First payload is still on Cancun and must not contain requests
+			suite_cancun.NewPayloads{},
+			// First payload on Prague contains no requests
+			suite_cancun.NewPayloads{},
+			SendDepositRequests{
+				TransactionCount: 1,
+			},
+			SendWithdrawalRequests{
+				TransactionCount: 1,
+				AccountIndex:     1,
+			},
+			SendConsolidationRequests{
+				TransactionCount: 1,
+				AccountIndex:     2,
+			},
+			suite_cancun.NewPayloads{},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Deposit Requests",
+			About: `
+			Tests EIP-6110 deposit requests.
+
+			Sends deposit transactions to the deposit contract and verifies that
+			the payload built contains one deposit request per deposit log, in
+			the order of the transactions.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendDepositRequests{
+				TransactionCount: 3,
+			},
+			suite_cancun.NewPayloads{},
+			// Requests must not be carried over to the next payload
+			suite_cancun.NewPayloads{},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Withdrawal Requests",
+			About: `
+			Tests EIP-7002 withdrawal requests.
+
+			Sends partial withdrawal and full exit requests to the withdrawal
+			request system contract and verifies that the payload built contains
+			the requests dequeued by the system call at the end of the block.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendWithdrawalRequests{
+				TransactionCount: 2,
+				Amount:           1_000_000_000,
+			},
+			suite_cancun.NewPayloads{},
+			SendWithdrawalRequests{
+				TransactionCount: 1,
+				AccountIndex:     1,
+			},
+			suite_cancun.NewPayloads{},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Consolidation Requests",
+			About: `
+			Tests EIP-7251 consolidation requests.
+
+			Sends consolidation requests to the consolidation request system
+			contract and verifies that the payload built contains the requests
+			dequeued by the system call at the end of the block.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendConsolidationRequests{
+				TransactionCount: 2,
+			},
+			suite_cancun.NewPayloads{},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Multiple Request Types",
+			About: `
+			Tests a payload containing requests of all types.
+
+			Verifies that the execution requests are ordered by request type,
+			regardless of the order of the transactions in the payload.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendConsolidationRequests{
+				TransactionCount: 1,
+			},
+			SendWithdrawalRequests{
+				TransactionCount: 2,
+				AccountIndex:     1,
+			},
+			SendDepositRequests{
+				TransactionCount: 2,
+				AccountIndex:     2,
+			},
+			suite_cancun.NewPayloads{},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Set Code Transactions",
+			About: `
+			Tests EIP-7702 set-code transactions in payloads.
+
+			Sends set-code transactions which delegate new accounts to a contract
+			that stores the caller, and call the delegated account in the same
+			transaction. Verifies that the transactions are included in the
+			payload and that the storage of the delegated account was modified.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendSetCodeTransactions{
+				TransactionCount: 1,
+			},
+			SendSetCodeTransactions{
+				TransactionCount:   2,
+				AuthorizationCount: 3,
+				AccountIndex:       1,
+			},
+			suite_cancun.NewPayloads{},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "Set Code Transactions With Requests",
+			About: `
+			Tests a payload containing set-code transactions and requests of all
+			types.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendSetCodeTransactions{
+				TransactionCount: 1,
+			},
+			SendDepositRequests{
+				TransactionCount: 1,
+				AccountIndex:     1,
+			},
+			SendWithdrawalRequests{
+				TransactionCount: 1,
+				AccountIndex:     2,
+			},
+			SendConsolidationRequests{
+				TransactionCount: 1,
+				AccountIndex:     3,
+			},
+			suite_cancun.NewPayloads{},
+		},
+	},
+
+	// Invalid execution requests
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Modified Request",
+			About: `
+			Tests engine_newPayloadV4 with a request that does not match the
+			request produced by the execution of the payload.
+			The block hash is updated to match the modified requests.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendDepositRequests{
+				TransactionCount: 1,
+			},
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &helper.CustomPayloadData{
+						ExecutionRequests: &[][]byte{
+							append([]byte{prague.DEPOSIT_REQUEST_TYPE}, modifiedDepositRequest...),
+						},
+					},
+					ExpectInvalidStatus: true,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns INVALID status because the deposit request
+				does not match the deposit log of the payload.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Corrupted Request",
+			About: `
+			Tests engine_newPayloadV4 with the last byte of the first request
+			corrupted, using the invalid payload generator.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendWithdrawalRequests{
+				TransactionCount: 1,
+			},
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &InvalidPayloadField{
+						Field: helper.InvalidExecutionRequests,
+					},
+					ExpectInvalidStatus: true,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns INVALID status because the withdrawal request
+				does not match the request dequeued from the system contract.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Missing Request",
+			About: `
+			Tests engine_newPayloadV4 with an empty execution requests list on a
+			payload that contains a deposit.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendDepositRequests{
+				TransactionCount: 1,
+			},
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &helper.CustomPayloadData{
+						ExecutionRequests: &[][]byte{},
+					},
+					ExpectInvalidStatus: true,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns INVALID status because the deposit request
+				of the payload is missing.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Extra Request",
+			About: `
+			Tests engine_newPayloadV4 with a withdrawal request on a payload that
+			does not produce any requests.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &helper.CustomPayloadData{
+						ExecutionRequests: &[][]byte{
+							append([]byte{prague.WITHDRAWAL_REQUEST_TYPE}, unsentWithdrawalRequest...),
+						},
+					},
+					ExpectInvalidStatus: true,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns INVALID status because the withdrawal request
+				was not produced by the payload.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Nil Requests",
+			About: `
+			Tests engine_newPayloadV4 with null as the execution requests
+			parameter.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &helper.CustomPayloadData{
+						RemoveExecutionRequests: true,
+					},
+					ExpectedError: globals.INVALID_PARAMS_ERROR,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns InvalidParamsError because the execution
+				requests are required after Prague.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Empty Request Data",
+			About: `
+			Tests engine_newPayloadV4 with a request that only contains the
+			request type.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &helper.CustomPayloadData{
+						ExecutionRequests: &[][]byte{
+							{prague.DEPOSIT_REQUEST_TYPE},
+						},
+					},
+					ExpectedError: globals.INVALID_PARAMS_ERROR,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns InvalidParamsError because requests with
+				empty data must be omitted from the list.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Unordered Request Types",
+			About: `
+			Tests engine_newPayloadV4 with requests which are not sorted by
+			request type.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendDepositRequests{
+				TransactionCount: 1,
+			},
+			SendWithdrawalRequests{
+				TransactionCount: 1,
+				AccountIndex:     1,
+			},
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &helper.CustomPayloadData{
+						ExecutionRequests: &[][]byte{
+							append([]byte{prague.WITHDRAWAL_REQUEST_TYPE}, WithdrawalRequestData(globals.TestAccounts[1].GetAddress(), ValidatorPubkey(0), 0)...),
+							append([]byte{prague.DEPOSIT_REQUEST_TYPE}, DepositRequestData(0)...),
+						},
+					},
+					ExpectedError: globals.INVALID_PARAMS_ERROR,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns InvalidParamsError because the requests are
+				not ordered by request type.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Execution Requests, Duplicate Request Type",
+			About: `
+			Tests engine_newPayloadV4 with two entries of the same request type.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			SendDepositRequests{
+				TransactionCount: 2,
+			},
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+					PayloadCustomizer: &helper.CustomPayloadData{
+						ExecutionRequests: &[][]byte{
+							append([]byte{prague.DEPOSIT_REQUEST_TYPE}, DepositRequestData(0)...),
+							append([]byte{prague.DEPOSIT_REQUEST_TYPE}, DepositRequestData(1)...),
+						},
+					},
+					ExpectedError: globals.INVALID_PARAMS_ERROR,
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns InvalidParamsError because the requests of
+				each type must be contained in a single entry.
+				`,
+			},
+		},
+	},
+
+	// Engine API versions
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV3 After Prague",
+			About: `
+			Tests that engine_newPayloadV3 is rejected for payloads after Prague.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.DowngradeNewPayloadVersion{
+					NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+						ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+					},
+				},
+				ExpectationDescription: `
+				NewPayloadV3 returns UnsupportedForkError on a Prague payload.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "GetPayloadV3 After Prague",
+			About: `
+			Tests that engine_getPayloadV3 is rejected for payloads after Prague.
+			`,
+			MainFork: config.Prague,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				GetPayloadCustomizer: &helper.DowngradeGetPayloadVersion{
+					GetPayloadCustomizer: &helper.BaseGetPayloadCustomizer{
+						ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+					},
+				},
+				ExpectationDescription: `
+				GetPayloadV3 returns UnsupportedForkError on a Prague payload.
+				`,
+			},
+		},
+	},
+
+	&PragueBaseSpec{
+		BaseSpec: test.BaseSpec{
+			Name: "NewPayloadV4 Before Prague",
+			About: `
+			Tests that engine_newPayloadV4 is rejected for payloads before Prague.
+			`,
+			MainFork:   config.Prague,
+			ForkHeight: 2,
+		},
+		TestSequence: suite_cancun.TestSequence{
+			suite_cancun.NewPayloads{
+				NewPayloadCustomizer: &helper.UpgradeNewPayloadVersion{
+					NewPayloadCustomizer: &helper.BaseNewPayloadVersionCustomizer{
+						PayloadCustomizer: &helper.CustomPayloadData{
+							ExecutionRequests: &[][]byte{},
+						},
+						ExpectedError: globals.UNSUPPORTED_FORK_ERROR,
+					},
+				},
+				ExpectationDescription: `
+				NewPayloadV4 returns UnsupportedForkError on a Cancun payload.
+				`,
+			},
+		},
+	},
+}
+
+func init() {
+	// Append all engine api tests with Prague as main fork
+	for _, test := range suite_engine.Tests {
+		Tests = append(Tests, test.WithMainFork(config.Prague))
+	}
+}
@@ -0,0 +1,345 @@
+package suite_sync
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	api "github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/hive/simulators/ethereum/engine/client/hive_rpc"
+	"github.com/ethereum/hive/simulators/ethereum/engine/client/node"
+	"github.com/ethereum/hive/simulators/ethereum/engine/clmock"
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
+)
+
+var big1 = big.NewInt(1)
+
+// Test where the chain is built by an in-process geth peer while the client is
+// disconnected from the CL, and the client is then prompted to sync the chain from
+// the peer with a forkchoice update to the head.
+type SyncSpec struct {
+	test.BaseSpec
+	// Number of payloads built by the peer before the client is prompted to sync
+	ChainLength uint64
+	// Whether the client also receives the head payload via newPayload before the
+	// forkchoice update
+	SendHeadPayload bool
+	// Number of payloads built by the peer once the client started syncing, which
+	// change the head the client syncs to. Value of 0 keeps the original head.
+	HeadChangeLength uint64
+	// Index of the payload replaced with an invalid payload in the chain served by
+	// the peer, starting with 1 being the first payload built by the peer.
+	// Value must be greater than 1, or 0 to serve a valid chain.
+	InvalidIndex uint64
+	// Whether the peer is disconnected once the client started syncing, in which
+	// case the CL backfills the chain via newPayload
+	DisconnectPeer bool
+	// Whether the safe and finalized blocks are advanced on each forkchoice update
+	// sent during sync
+	UpdateSafeFinalized bool
+}
+
+func (s SyncSpec) WithMainFork(fork config.Fork) test.Spec {
+	specCopy := s
+	specCopy.MainFork = fork
+	return specCopy
+}
+
+func (s SyncSpec) GetName() string {
+	name := []string{
+		s.Name,
+		fmt.Sprintf("Length=%d", s.GetChainLength()),
+	}
+	if s.SendHeadPayload {
+		name = append(name, "NewPayload")
+	}
+	if s.HeadChangeLength > 0 {
+		name = append(name, fmt.Sprintf("HeadChange=%d", s.HeadChangeLength))
+	}
+	if s.InvalidIndex > 0 {
+		name = append(name, fmt.Sprintf("Invalid P%d", s.InvalidIndex))
+	}
+	return strings.Join(name, ", ")
+}
+
+func (s SyncSpec) GetTags() []string {
+	tags := test.AppendTags(s.BaseSpec.GetTags(), test.TagSync)
+	if s.InvalidIndex > 0 {
+		tags = test.AppendTags(tags, test.TagInvalidPayload)
+	}
+	return tags
+}
+
+func (s SyncSpec) GetChainLength() uint64 {
+	if s.ChainLength == 0 {
+		return 16
+	}
+	return s.ChainLength
+}
+
+// Sends one transaction on each payload built by the CL Mock, so the synced blocks
+// modify the state.
+func sendTransactionCallbacks(t *test.Env) clmock.BlockProcessCallbacks {
+	return clmock.BlockProcessCallbacks{
+		OnPayloadProducerSelected: func() {
+			_, err := t.SendNextTransaction(
+				t.TestContext,
+				t.CLMock.NextBlockProducer,
+				&helper.BaseTransactionCreator{
+					Recipient:  &globals.PrevRandaoContractAddr,
+					Amount:     big1,
+					TxType:     t.TestTransactionType,
+					GasLimit:   75000,
+					ForkConfig: t.ForkConfig,
+				},
+			)
+			if err != nil {
+				t.Fatalf("FAIL (%s): Error trying to send transaction: %v", t.TestName, err)
+			}
+		},
+	}
+}
+
+// Returns the payloads built by the CL Mock after the given block number.
+func executedPayloads(t *test.Env, after uint64) []*typ.ExecutableData {
+	payloads := make([]*typ.ExecutableData, 0)
+	for i := after + 1; i <= t.CLMock.LatestExecutedPayload.Number; i++ {
+		payload, ok := t.CLMock.ExecutedPayloadHistory[i]
+		if !ok {
+			t.Fatalf("FAIL (%s): TEST ISSUE - Payload %d not found in history", t.TestName, i)
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+// Replaces the payloads of the chain starting at the invalid index with a side
+// chain which contains an invalid payload, and sets it as the canonical chain of
+// the peer. Returns the head of the side chain.
+func (s SyncSpec) setInvalidSideChain(t *test.Env, peer *node.GethNode, chain []*typ.ExecutableData) *typ.ExecutableData {
+	if s.InvalidIndex < 2 || s.InvalidIndex > uint64(len(chain)) {
+		t.Fatalf("FAIL (%s): TEST ISSUE - Invalid payload index: %d", t.TestName, s.InvalidIndex)
+	}
+	parent := chain[s.InvalidIndex-2]
+	for i := s.InvalidIndex - 1; i < uint64(len(chain)); i++ {
+		// Insert extraData to ensure we deviate from the canonical payload
+		parentHash := parent.BlockHash
+		customizer := &helper.CustomPayloadData{
+			ParentHash: &parentHash,
+			ExtraData:  &([]byte{0x01}),
+		}
+		sidePayload, err := customizer.CustomizePayload(t.Rand, chain[i])
+		if err != nil {
+			t.Fatalf("FAIL (%s): Unable to customize payload: %v", t.TestName, err)
+		}
+		if i == s.InvalidIndex-1 {
+			sidePayload, err = helper.GenerateInvalidPayload(t.Rand, sidePayload, helper.InvalidStateRoot)
+			if err != nil {
+				t.Fatalf("FAIL (%s): Unable to customize payload: %v", t.TestName, err)
+			}
+		}
+		setPeerBlock(t, peer, sidePayload, parent)
+		parent = sidePayload
+	}
+	return parent
+}
+
+// Sets the given payloads as the canonical chain of the peer.
+func setPeerChain(t *test.Env, peer *node.GethNode, parent *typ.ExecutableData, payloads []*typ.ExecutableData) {
+	for _, payload := range payloads {
+		setPeerBlock(t, peer, payload, parent)
+		parent = payload
+	}
+}
+
+func setPeerBlock(t *test.Env, peer *node.GethNode, payload *typ.ExecutableData, parent *typ.ExecutableData) {
+	block, err := typ.ExecutableDataToBlock(*payload)
+	if err != nil {
+		t.Fatalf("FAIL (%s): TEST ISSUE - Failed to create block from payload: %v", t.TestName, err)
+	}
+	if err := peer.SetBlock(block, parent.Number, parent.StateRoot); err != nil {
+		t.Fatalf("FAIL (%s): TEST ISSUE - Failed to set block %d on peer: %v", t.TestName, payload.Number, err)
+	}
+}
+
+// Keeps track of the statuses returned by the client while syncing, and verifies
+// that the client only transitions from SYNCING/ACCEPTED to a final status.
+type statusTransitions struct {
+	statuses []test.PayloadStatus
+}
+
+func (st *statusTransitions) add(t *test.Env, status test.PayloadStatus) {
+	if len(st.statuses) > 0 {
+		previous := st.statuses[len(st.statuses)-1]
+		if previous == status {
+			return
+		}
+		if previous != test.Syncing && previous != test.Accepted {
+			t.Fatalf("FAIL (%s): Client transitioned from %s to %s while syncing: %s", t.TestName, previous, status, st)
+		}
+	}
+	st.statuses = append(st.statuses, status)
+}
+
+func (st *statusTransitions) String() string {
+	return strings.Join(test.StatusesToString(st.statuses), " -> ")
+}
+
+// Sends forkchoice updates to the client every second until it returns a status
+// other than SYNCING, and returns that status.
+// The forkchoice state sent on each update is obtained from the given function,
+// which receives the number of updates sent so far.
+func waitSync(t *test.Env, st *statusTransitions, forkchoice func(int) api.ForkchoiceStateV1, timestamp uint64) test.PayloadStatus {
+	for i := 0; ; i++ {
+		fcState := forkchoice(i)
+		r := t.TestEngine.TestEngineForkchoiceUpdated(&fcState, nil, timestamp)
+		r.ExpectNoError()
+		status := test.PayloadStatus(r.Response.PayloadStatus.Status)
+		st.add(t, status)
+		if status != test.Syncing {
+			return status
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-t.TimeoutContext.Done():
+			t.Fatalf("FAIL (%s): Timeout waiting for client to sync to %v: %s", t.TestName, fcState.HeadBlockHash, st)
+		}
+	}
+}
+
+// Returns the forkchoice state pointing to the head, and with the safe and
+// finalized blocks advanced one block along the chain on each update, if enabled.
+func (s SyncSpec) forkchoice(head func() *typ.ExecutableData, chain func() []*typ.ExecutableData) func(int) api.ForkchoiceStateV1 {
+	return func(i int) api.ForkchoiceStateV1 {
+		fcState := api.ForkchoiceStateV1{
+			HeadBlockHash: head().BlockHash,
+		}
+		if s.UpdateSafeFinalized {
+			payloads := chain()
+			safeIndex := i
+			if safeIndex > len(payloads)-2 {
+				safeIndex = len(payloads) - 2
+			}
+			if safeIndex >= 0 {
+				fcState.SafeBlockHash = payloads[safeIndex].BlockHash
+			}
+			if safeIndex >= 1 {
+				fcState.FinalizedBlockHash = payloads[safeIndex-1].BlockHash
+			}
+		}
+		return fcState
+	}
+}
+
+func (s SyncSpec) Execute(t *test.Env) {
+	// The chain is built by the peer while the client is disconnected from the CL
+	starter := node.GethNodeEngineStarter{
+		Config: node.GethNodeTestConfiguration{},
+	}
+	peer, err := starter.StartGethNode(t.T, t.TestContext, t.Genesis, t.ClientParams, t.ClientFiles)
+	if err != nil {
+		t.Fatalf("FAIL (%s): Unable to spawn a peer client: %v", t.TestName, err)
+	}
+	t.CLMock.AddEngineClient(peer)
+	t.CLMock.RemoveEngineClient(t.Engine)
+
+	startNumber := t.CLMock.LatestHeader.Number.Uint64()
+	t.Logf("INFO (%s): Building chain of %d payloads on the peer", t.TestName, s.GetChainLength())
+	t.CLMock.ProduceBlocks(int(s.GetChainLength()), sendTransactionCallbacks(t))
+	chain := executedPayloads(t, startNumber)
+
+	head := chain[len(chain)-1]
+	if s.InvalidIndex > 0 {
+		head = s.setInvalidSideChain(t, peer, chain)
+	}
+
+	// Connect the client to the peer, and prompt it to sync to the head
+	if err := peer.AddPeer(t.Engine); err != nil {
+		t.Fatalf("FAIL (%s): Unable to add the client as peer: %v", t.TestName, err)
+	}
+	if s.SendHeadPayload {
+		r := t.TestEngine.TestEngineNewPayload(head)
+		r.ExpectationDescription = "Sent far head payload with unknown parent to the client, expected to be syncing"
+		r.ExpectStatusEither(test.Syncing, test.Accepted)
+	}
+
+	st := &statusTransitions{}
+	headFn := func() *typ.ExecutableData { return head }
+	chainFn := func() []*typ.ExecutableData { return chain }
+	fcState := s.forkchoice(headFn, chainFn)(0)
+	r := t.TestEngine.TestEngineForkchoiceUpdated(&fcState, nil, head.Timestamp)
+	r.ExpectationDescription = "Sent forkchoice update to the far head, which the client can only obtain from the peer"
+	r.ExpectPayloadStatus(test.Syncing)
+	r.ExpectLatestValidHash(nil)
+	st.add(t, test.Syncing)
+
+	if s.HeadChangeLength > 0 {
+		// The peer extends the chain while the client is syncing
+		t.Logf("INFO (%s): Extending chain by %d payloads on the peer", t.TestName, s.HeadChangeLength)
+		t.CLMock.ProduceBlocks(int(s.HeadChangeLength), sendTransactionCallbacks(t))
+		chain = executedPayloads(t, startNumber)
+		head = chain[len(chain)-1]
+	}
+
+	if s.DisconnectPeer {
+		// The peer leaves, and the client must keep syncing until the CL backfills
+		// the chain via newPayload
+		t.CLMock.AddEngineClient(t.Engine)
+		t.CLMock.RemoveEngineClient(peer)
+		if err := peer.Close(); err != nil {
+			t.Fatalf("FAIL (%s): Unable to close the peer: %v", t.TestName, err)
+		}
+		t.Logf("INFO (%s): Peer disconnected, backfilling %d payloads", t.TestName, len(chain))
+		for _, payload := range chain {
+			r := t.TestEngine.TestEngineNewPayload(payload)
+			r.ExpectationDescription = "Sent backfill payload to the client after the peer disconnected"
+			r.ExpectStatusEither(test.Valid, test.Syncing, test.Accepted)
+		}
+	}
+
+	status := waitSync(t, st, s.forkchoice(headFn, chainFn), head.Timestamp)
+	t.Logf("INFO (%s): Client status transitions: %s", t.TestName, st)
+
+	if s.InvalidIndex > 0 {
+		if status != test.Invalid {
+			t.Fatalf("FAIL (%s): Client returned %s on a chain with an invalid payload", t.TestName, status)
+		}
+		// The client must report the last valid payload of the side chain
+		lvh := chain[s.InvalidIndex-2].BlockHash
+		fcState := api.ForkchoiceStateV1{HeadBlockHash: head.BlockHash}
+		r := t.TestEngine.TestEngineForkchoiceUpdated(&fcState, nil, head.Timestamp)
+		r.ExpectPayloadStatus(test.Invalid)
+		r.ExpectLatestValidHash(&lvh)
+
+		// The peer serves the canonical chain again, which the client must sync
+		setPeerChain(t, peer, chain[s.InvalidIndex-2], chain[s.InvalidIndex-1:])
+		head = chain[len(chain)-1]
+		st = &statusTransitions{}
+		status = waitSync(t, st, s.forkchoice(headFn, chainFn), head.Timestamp)
+		t.Logf("INFO (%s): Client status transitions to the canonical chain: %s", t.TestName, st)
+	}
+	if status != test.Valid {
+		t.Fatalf("FAIL (%s): Client returned %s after syncing to %v", t.TestName, status, head.BlockHash)
+	}
+
+	// Verify the final state of the client
+	t.TestEngine.TestHeaderByNumber(nil).ExpectHash(head.BlockHash)
+	t.TestEngine.TestBlockByNumber(nil).ExpectTransactionCountEqual(len(head.Transactions))
+	if s.UpdateSafeFinalized {
+		fcState := s.forkchoice(headFn, chainFn)(len(chain))
+		t.TestEngine.TestHeaderByNumber(hive_rpc.Safe).ExpectHash(fcState.SafeBlockHash)
+		t.TestEngine.TestHeaderByNumber(hive_rpc.Finalized).ExpectHash(fcState.FinalizedBlockHash)
+	}
+	t.Logf("INFO (%s): Client synced to payload %d: %v", t.TestName, head.Number, head.BlockHash)
+
+	// Hand the client back to the CL Mock, which builds the next payloads on top
+	// of the synced chain
+	if !s.DisconnectPeer {
+		t.CLMock.AddEngineClient(t.Engine)
+	}
+}
@@ -0,0 +1,150 @@
+// # Test suite for optimistic sync and backfill tests
+package suite_sync
+
+import (
+	"github.com/ethereum/hive/simulators/ethereum/engine/config"
+	"github.com/ethereum/hive/simulators/ethereum/engine/test"
+)
+
+// Forks on which the sync tests run, all of them active since genesis
+var syncForks = []config.Fork{
+	config.Shanghai,
+	config.Cancun,
+	config.Prague,
+}
+
+// Lengths of the chains built by the peer before the client is prompted to sync
+var syncChainLengths = []uint64{8, 32}
+
+// Returns the timeout of a sync test, which depends on the number of payloads the
+// peer builds, one per second.
+func syncTimeout(payloadCount uint64) int {
+	return 60 + 2*int(payloadCount)
+}
+
+// List of all sync tests
+var Tests = make([]test.Spec, 0)
+
+func init() {
+	for _, fork := range syncForks {
+		for _, length := range syncChainLengths {
+			Tests = append(Tests,
+				SyncSpec{
+					BaseSpec: test.BaseSpec{
+						Name: "Sync From Peer",
+						About: `
+						The peer builds a chain while the client is disconnected from the
+						CL, and the client is then sent a forkchoice update to the head of
+						the chain, which it can only obtain by syncing from the peer.
+
+						Verifications performed:
+						- Forkchoice update to the unknown head returns SYNCING with null
+						  latestValidHash
+						- Client transitions from SYNCING to VALID and never back
+						- Head of the client is the head of the chain once synced
+						`,
+						MainFork:       fork,
+						TimeoutSeconds: syncTimeout(length),
+					},
+					ChainLength: length,
+				},
+				SyncSpec{
+					BaseSpec: test.BaseSpec{
+						Name: "Sync From Peer",
+						About: `
+						Same as the test above, but the head payload is sent to the client
+						via newPayload before the forkchoice update.
+
+						Verifications performed:
+						- NewPayload of the head returns SYNCING or ACCEPTED
+						- Client transitions from SYNCING to VALID and never back
+						- Head of the client is the head of the chain once synced
+						`,
+						MainFork:       fork,
+						TimeoutSeconds: syncTimeout(length),
+					},
+					ChainLength:     length,
+					SendHeadPayload: true,
+				},
+				SyncSpec{
+					BaseSpec: test.BaseSpec{
+						Name: "Sync From Peer, Head Change",
+						About: `
+						The client is prompted to sync to the head of the chain built by
+						the peer, and the peer extends the chain while the client is
+						syncing. The client is then sent forkchoice updates to the new head.
+
+						Verifications performed:
+						- Client transitions from SYNCING to VALID and never back
+						- Head of the client is the new head of the chain once synced
+						`,
+						MainFork:       fork,
+						TimeoutSeconds: syncTimeout(length + length/2),
+					},
+					ChainLength:      length,
+					HeadChangeLength: length / 2,
+				},
+				SyncSpec{
+					BaseSpec: test.BaseSpec{
+						Name: "Sync From Peer, Safe/Finalized Updates",
+						About: `
+						The client is prompted to sync to the head of the chain built by
+						the peer, and each forkchoice update sent during sync advances the
+						safe and finalized blocks one block along the chain.
+
+						Verifications performed:
+						- Client transitions from SYNCING to VALID and never back
+						- Head, safe and finalized blocks of the client are the ones of the
+						  last forkchoice update once synced
+						`,
+						MainFork:       fork,
+						TimeoutSeconds: syncTimeout(length),
+					},
+					ChainLength:         length,
+					UpdateSafeFinalized: true,
+				},
+				SyncSpec{
+					BaseSpec: test.BaseSpec{
+						Name: "Sync From Peer, Invalid Payload",
+						About: `
+						The peer serves a chain which contains a payload with an invalid
+						state root in the middle of the range the client has to sync.
+						Once the client rejects the chain, the peer serves the canonical
+						chain instead.
+
+						Verifications performed:
+						- Client transitions from SYNCING to INVALID and never to VALID
+						- Forkchoice update to the invalid chain returns INVALID with the
+						  last valid payload before the invalid one as latestValidHash
+						- Client syncs the canonical chain served afterwards
+						`,
+						MainFork:       fork,
+						TimeoutSeconds: syncTimeout(length),
+					},
+					ChainLength:  length,
+					InvalidIndex: length / 2,
+				},
+				SyncSpec{
+					BaseSpec: test.BaseSpec{
+						Name: "Sync From Peer, Peer Disconnect",
+						About: `
+						The client is prompted to sync to the head of the chain built by
+						the peer, and the peer disconnects right after. The CL then
+						backfills the chain via newPayload.
+
+						Verifications performed:
+						- NewPayload of each backfilled payload returns VALID, SYNCING or
+						  ACCEPTED
+						- Client transitions from SYNCING to VALID and never back
+						- Head of the client is the head of the chain once synced
+						`,
+						MainFork:       fork,
+						TimeoutSeconds: syncTimeout(length),
+					},
+					ChainLength:    length,
+					DisconnectPeer: true,
+				},
+			)
+		}
+	}
+}
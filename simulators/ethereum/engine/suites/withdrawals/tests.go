@@ -940,6 +940,10 @@ func (ws *WithdrawalsBaseSpec) GetWithdrawalsForkTime() uint64 {
 }
 
 // Generates the fork config, including withdrawals fork timestamp.
+func (ws *WithdrawalsBaseSpec) GetTags() []string {
+	return test.AppendTags(ws.BaseSpec.GetTags(), test.TagWithdrawals)
+}
+
 func (ws *WithdrawalsBaseSpec) GetForkConfig() *config.ForkConfig {
 	return &config.ForkConfig{
 		ShanghaiTimestamp: big.NewInt(int64(ws.GetWithdrawalsForkTime())),
@@ -1450,6 +1454,10 @@ type WithdrawalsSyncSpec struct {
 	SyncShouldFail bool //
 }
 
+func (ws *WithdrawalsSyncSpec) GetTags() []string {
+	return test.AppendTags(ws.WithdrawalsBaseSpec.GetTags(), test.TagSync)
+}
+
 func (ws *WithdrawalsSyncSpec) Execute(t *test.Env) {
 	// Do the base withdrawal test first, skipping base verifications
 	ws.WithdrawalsBaseSpec.SkipBaseVerifications = true
@@ -1504,6 +1512,14 @@ type WithdrawalsReorgSpec struct {
 	SidechainTimeIncrements uint64
 }
 
+func (ws *WithdrawalsReorgSpec) GetTags() []string {
+	tags := test.AppendTags(ws.WithdrawalsBaseSpec.GetTags(), test.TagReorg)
+	if ws.ReOrgViaSync {
+		tags = test.AppendTags(tags, test.TagSync)
+	}
+	return tags
+}
+
 func (ws *WithdrawalsReorgSpec) GetSidechainSplitHeight() uint64 {
 	if ws.ReOrgBlockCount > ws.GetTotalPayloadCount() {
 		panic("invalid payload/re-org configuration")
@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"net/http"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
 	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/hive/hivesim"
 )
@@ -54,7 +56,9 @@ type Env struct {
 	TestTransactionType helper.TestTransactionType
 }
 
-func Run(testSpec Spec, timeout time.Duration, t *hivesim.T, c *hivesim.Client, genesis *core.Genesis, randSource *rand.Rand, cParams hivesim.Params, cFiles hivesim.Params) {
+// Run executes the test spec using c as the main client. Peer clients, if any, are
+// also driven by the CL mocker and take turns with the main client to build payloads.
+func Run(testSpec Spec, timeout time.Duration, t *hivesim.T, c *hivesim.Client, genesis *core.Genesis, randSource *rand.Rand, cParams hivesim.Params, cFiles hivesim.Params, peers ...*hivesim.Client) {
 	// Setup the CL Mocker for this test
 	forkConfig := testSpec.GetForkConfig()
 	clMocker := clmock.NewCLMocker(
@@ -76,7 +80,7 @@ func Run(testSpec Spec, timeout time.Duration, t *hivesim.T, c *hivesim.Client,
 	ec := hive_rpc.NewHiveRPCEngineClient(c, globals.EnginePortHTTP, globals.EthPortHTTP, globals.DefaultJwtTokenSecretBytes, &helper.LoggingRoundTrip{
 		Logger: t,
 		ID:     c.Container,
-		Inner:  http.DefaultTransport,
+		Inner:  hive_rpc.SchemaCheckTransport(t, c.Container, http.DefaultTransport),
 	})
 	defer ec.Close()
 
@@ -101,13 +105,28 @@ func Run(testSpec Spec, timeout time.Duration, t *hivesim.T, c *hivesim.Client,
 		Rand:                randSource,
 	}
 	env.Engines = append(env.Engines, ec)
-	env.TestEngines = append(env.TestEngines, env.TestEngine)
 
 	// Before running the test, make sure Eth and Engine ports are open for the client
 	if err := hive_rpc.CheckEthEngineLive(c); err != nil {
 		t.Fatalf("FAIL (%s): Ports were never open for client: %v", env.TestName, err)
 	}
 
+	// Add the peer clients, which must validate every payload built by another client
+	for _, peer := range peers {
+		if err := hive_rpc.CheckEthEngineLive(peer); err != nil {
+			t.Fatalf("FAIL (%s): Ports were never open for client %s: %v", env.TestName, peer.Type, err)
+		}
+		peerEngine := hive_rpc.NewHiveRPCEngineClient(peer, globals.EnginePortHTTP, globals.EthPortHTTP, globals.DefaultJwtTokenSecretBytes, &helper.LoggingRoundTrip{
+			Logger: t,
+			ID:     peer.Container,
+			Inner:  hive_rpc.SchemaCheckTransport(t, peer.Container, http.DefaultTransport),
+		})
+		defer peerEngine.Close()
+		clMocker.AddEngineClient(peerEngine)
+		env.Engines = append(env.Engines, peerEngine)
+	}
+	clMocker.CrossClientValidation = len(peers) > 0
+
 	// Setup clMocker with client head.
 	clMocker.InitChain(ec)
 
@@ -123,8 +142,11 @@ func Run(testSpec Spec, timeout time.Duration, t *hivesim.T, c *hivesim.Client,
 	env.TimeoutContext = ctx
 	clMocker.TimeoutContext = ctx
 
-	// Create the test-expect object
+	// Create the test-expect objects
 	env.TestEngine = NewTestEngineClient(env, ec)
+	for _, engine := range env.Engines {
+		env.TestEngines = append(env.TestEngines, NewTestEngineClient(env, engine))
+	}
 
 	// Defer producing one last block to verify Execution client did not break after the test
 	defer func() {
@@ -143,3 +165,37 @@ func (t *Env) HandleClientPostRunVerification(ec client.EngineClient) {
 		t.Fatalf("FAIL (%s): Client failed post-run verification: %v", t.TestName, err)
 	}
 }
+
+// Restarts the main client, preserving its data, and replaces the engine client of the
+// environment and of the CL Mock by a new one connected to the restarted client.
+// If jwtSecret is not nil, the client is restarted with the given JWT secret, which is
+// then used by the new engine client. Otherwise the secret is not changed.
+func (t *Env) RestartClient(jwtSecret []byte) error {
+	var opts hivesim.RestartOptions
+	if jwtSecret != nil {
+		opts.Environment = map[string]string{"HIVE_JWT_SECRET": hexutil.Encode(jwtSecret)}
+	} else {
+		jwtSecret = t.HiveEngine.JWTSecretBytes
+	}
+	t.CLMock.RemoveEngineClient(t.Engine)
+	t.Engine.Close()
+	if err := t.Client.Restart(opts); err != nil {
+		return fmt.Errorf("unable to restart client: %v", err)
+	}
+	if err := hive_rpc.CheckEthEngineLive(t.Client); err != nil {
+		return fmt.Errorf("ports were never open for restarted client: %v", err)
+	}
+	ec := hive_rpc.NewHiveRPCEngineClient(t.Client, globals.EnginePortHTTP, globals.EthPortHTTP, jwtSecret, &helper.LoggingRoundTrip{
+		Logger: t,
+		ID:     t.Client.Container,
+		Inner:  hive_rpc.SchemaCheckTransport(t, t.Client.Container, http.DefaultTransport),
+	})
+	t.Engine = ec
+	t.Eth = ec
+	t.HiveEngine = ec
+	t.Engines[0] = ec
+	t.TestEngine = NewTestEngineClient(t, ec)
+	t.TestEngines[0] = t.TestEngine
+	t.CLMock.AddEngineClient(ec)
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/hive/simulators/ethereum/engine/client"
 	"github.com/ethereum/hive/simulators/ethereum/engine/config/cancun"
 	"github.com/ethereum/hive/simulators/ethereum/engine/globals"
+	"github.com/ethereum/hive/simulators/ethereum/engine/helper"
 	typ "github.com/ethereum/hive/simulators/ethereum/engine/types"
 )
 
@@ -286,12 +287,31 @@ func (tec *TestEngineClient) TestEngineNewPayloadV3(payload *typ.ExecutableData)
 	return ret
 }
 
+func (tec *TestEngineClient) TestEngineNewPayloadV4(payload *typ.ExecutableData) *NewPayloadResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.TestContext, globals.RPCTimeout)
+	defer cancel()
+	status, err := tec.Engine.NewPayloadV4(ctx, payload)
+	ret := &NewPayloadResponseExpectObject{
+		ExpectEnv: &ExpectEnv{Env: tec.Env},
+		Payload:   payload,
+		Status:    status,
+		Version:   4,
+		Error:     err,
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
 func (tec *TestEngineClient) TestEngineNewPayload(payload *typ.ExecutableData) *NewPayloadResponseExpectObject {
 	if payload == nil {
 		panic("Payload is nil")
 	}
 	version := tec.EngineAPIVersionResolver.NewPayloadVersion(payload.Timestamp)
-	if version == 3 {
+	if version == 4 {
+		return tec.TestEngineNewPayloadV4(payload)
+	} else if version == 3 {
 		return tec.TestEngineNewPayloadV3(payload)
 	} else if version == 2 {
 		return tec.TestEngineNewPayloadV2(payload)
@@ -422,6 +442,44 @@ func (tec *TestEngineClient) TestEngineGetPayloadV3(payloadID *api.PayloadID) *G
 	return ret
 }
 
+func (tec *TestEngineClient) TestEngineGetPayloadV4(payloadID *api.PayloadID) *GetPayloadResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.TestContext, globals.RPCTimeout)
+	defer cancel()
+	payload, blockValue, blobsBundle, shouldOverride, err := tec.Engine.GetPayloadV4(ctx, payloadID)
+	ret := &GetPayloadResponseExpectObject{
+		ExpectEnv:             &ExpectEnv{Env: tec.Env},
+		Payload:               payload,
+		Version:               4,
+		BlockValue:            blockValue,
+		BlobsBundle:           blobsBundle,
+		ShouldOverrideBuilder: shouldOverride,
+		Error:                 err,
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
+func (tec *TestEngineClient) TestEngineGetPayloadV5(payloadID *api.PayloadID) *GetPayloadResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.TestContext, globals.RPCTimeout)
+	defer cancel()
+	payload, blockValue, blobsBundle, shouldOverride, err := tec.Engine.GetPayloadV5(ctx, payloadID)
+	ret := &GetPayloadResponseExpectObject{
+		ExpectEnv:             &ExpectEnv{Env: tec.Env},
+		Payload:               payload,
+		Version:               5,
+		BlockValue:            blockValue,
+		BlobsBundle:           blobsBundle,
+		ShouldOverrideBuilder: shouldOverride,
+		Error:                 err,
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
 func (tec *TestEngineClient) TestEngineGetPayload(payloadID *api.PayloadID, payloadAttributes *typ.PayloadAttributes) *GetPayloadResponseExpectObject {
 	version := tec.EngineAPIVersionResolver.GetPayloadVersion(payloadAttributes.Timestamp)
 	ctx, cancel := context.WithTimeout(tec.TestContext, globals.RPCTimeout)
@@ -752,6 +810,143 @@ func (exp *GetPayloadBodiesResponseExpectObject) ExpectPayloadBody(index uint64,
 	}
 }
 
+// GetBlobs
+type GetBlobsResponseExpectObject struct {
+	*ExpectEnv
+	VersionedHashes []common.Hash
+	BlobsV1         []*typ.BlobAndProofV1
+	BlobsV2         []*typ.BlobAndProofV2
+	Version         int
+	Error           error
+	ErrorCode       int
+}
+
+func (tec *TestEngineClient) TestEngineGetBlobsV1(versionedHashes []common.Hash) *GetBlobsResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.TestContext, globals.RPCTimeout)
+	defer cancel()
+	blobs, err := tec.Engine.GetBlobsV1(ctx, versionedHashes)
+	ret := &GetBlobsResponseExpectObject{
+		ExpectEnv:       &ExpectEnv{Env: tec.Env},
+		VersionedHashes: versionedHashes,
+		BlobsV1:         blobs,
+		Version:         1,
+		Error:           err,
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
+func (tec *TestEngineClient) TestEngineGetBlobsV2(versionedHashes []common.Hash) *GetBlobsResponseExpectObject {
+	ctx, cancel := context.WithTimeout(tec.TestContext, globals.RPCTimeout)
+	defer cancel()
+	blobs, err := tec.Engine.GetBlobsV2(ctx, versionedHashes)
+	ret := &GetBlobsResponseExpectObject{
+		ExpectEnv:       &ExpectEnv{Env: tec.Env},
+		VersionedHashes: versionedHashes,
+		BlobsV2:         blobs,
+		Version:         2,
+		Error:           err,
+	}
+	if err, ok := err.(rpc.Error); ok {
+		ret.ErrorCode = err.ErrorCode()
+	}
+	return ret
+}
+
+func (exp *GetBlobsResponseExpectObject) ExpectNoError() {
+	if exp.Error != nil {
+		exp.Fatalf("FAIL (%s): Expected no error on EngineGetBlobsV%d: error=%v", exp.TestName, exp.Version, exp.Error)
+	}
+}
+
+func (exp *GetBlobsResponseExpectObject) ExpectError() {
+	if exp.Error == nil {
+		exp.Fatalf("FAIL (%s): Expected error on EngineGetBlobsV%d: hashes=%v", exp.TestName, exp.Version, exp.VersionedHashes)
+	}
+}
+
+func (exp *GetBlobsResponseExpectObject) ExpectErrorCode(code int) {
+	exp.ExpectError()
+	if exp.ErrorCode != code {
+		exp.Fatalf("FAIL (%s): Expected error code on EngineGetBlobsV%d: want=%d, got=%d", exp.TestName, exp.Version, code, exp.ErrorCode)
+	}
+}
+
+func (exp *GetBlobsResponseExpectObject) responseLength() int {
+	if exp.Version == 1 {
+		return len(exp.BlobsV1)
+	}
+	return len(exp.BlobsV2)
+}
+
+// ExpectNullResponse checks that the client returned null, which engine_getBlobsV2
+// does when any of the requested blobs is missing.
+func (exp *GetBlobsResponseExpectObject) ExpectNullResponse() {
+	exp.ExpectNoError()
+	if exp.BlobsV1 != nil || exp.BlobsV2 != nil {
+		exp.Fatalf("FAIL (%s): Expected null response on EngineGetBlobsV%d: got %d items", exp.TestName, exp.Version, exp.responseLength())
+	}
+}
+
+// ExpectMissingBlob checks that the blob at the given index is null in the response.
+func (exp *GetBlobsResponseExpectObject) ExpectMissingBlob(index int) {
+	exp.ExpectNoError()
+	if exp.responseLength() != len(exp.VersionedHashes) {
+		exp.Fatalf("FAIL (%s): Unexpected response length on EngineGetBlobsV%d: want=%d, got=%d", exp.TestName, exp.Version, len(exp.VersionedHashes), exp.responseLength())
+	}
+	if (exp.Version == 1 && exp.BlobsV1[index] != nil) || (exp.Version != 1 && exp.BlobsV2[index] != nil) {
+		exp.Fatalf("FAIL (%s): Expected missing blob on EngineGetBlobsV%d at index %d", exp.TestName, exp.Version, index)
+	}
+}
+
+// HasBlob returns whether the response contains a blob at the given index.
+func (exp *GetBlobsResponseExpectObject) HasBlob(index int) bool {
+	if exp.Version == 1 {
+		return index < len(exp.BlobsV1) && exp.BlobsV1[index] != nil
+	}
+	return index < len(exp.BlobsV2) && exp.BlobsV2[index] != nil
+}
+
+// ExpectBlob checks that the blob at the given index is the one generated by the
+// blob ID, and that the proofs returned with it are valid.
+func (exp *GetBlobsResponseExpectObject) ExpectBlob(index int, blobID helper.BlobID) {
+	exp.ExpectNoError()
+	if exp.responseLength() != len(exp.VersionedHashes) {
+		exp.Fatalf("FAIL (%s): Unexpected response length on EngineGetBlobsV%d: want=%d, got=%d", exp.TestName, exp.Version, len(exp.VersionedHashes), exp.responseLength())
+	}
+	_, commitment, _, err := blobID.GenerateBlob()
+	if err != nil {
+		exp.Fatalf("FAIL (%s): Unable to generate blob %d: %v", exp.TestName, blobID, err)
+	}
+	var blob *typ.Blob
+	if exp.Version == 1 {
+		if exp.BlobsV1[index] == nil {
+			exp.Fatalf("FAIL (%s): Expected blob on EngineGetBlobsV%d at index %d, got null", exp.TestName, exp.Version, index)
+		}
+		blob = &exp.BlobsV1[index].Blob
+		if err := helper.VerifyBlobProof(blob, commitment, &exp.BlobsV1[index].Proof); err != nil {
+			exp.Fatalf("FAIL (%s): Invalid proof on EngineGetBlobsV%d at index %d: %v", exp.TestName, exp.Version, index, err)
+		}
+	} else {
+		if exp.BlobsV2[index] == nil {
+			exp.Fatalf("FAIL (%s): Expected blob on EngineGetBlobsV%d at index %d, got null", exp.TestName, exp.Version, index)
+		}
+		blob = &exp.BlobsV2[index].Blob
+		cellProofs := exp.BlobsV2[index].CellProofs
+		if len(cellProofs) != typ.CellsPerExtBlob {
+			exp.Fatalf("FAIL (%s): Unexpected cell proof count on EngineGetBlobsV%d at index %d: want=%d, got=%d", exp.TestName, exp.Version, index, typ.CellsPerExtBlob, len(cellProofs))
+		}
+		if err := helper.VerifyCellProofs([]typ.Blob{*blob}, []typ.KZGCommitment{*commitment}, cellProofs); err != nil {
+			exp.Fatalf("FAIL (%s): Invalid cell proofs on EngineGetBlobsV%d at index %d: %v", exp.TestName, exp.Version, index, err)
+		}
+	}
+	if ok, err := blobID.VerifyBlob(blob); err != nil || !ok {
+		exp.Fatalf("FAIL (%s): Unexpected blob on EngineGetBlobsV%d at index %d: want=%d, err=%v", exp.TestName, exp.Version, index, blobID, err)
+	}
+}
+
 // BlockNumber
 type BlockNumberResponseExpectObject struct {
 	*ExpectEnv
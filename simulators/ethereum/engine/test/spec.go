@@ -38,6 +38,8 @@ type Spec interface {
 	GetTimeout() int
 	// Get whether mining is disabled for this test
 	IsMiningDisabled() bool
+	// Get the tags used to select the test
+	GetTags() []string
 }
 
 type BaseSpec struct {
@@ -86,6 +88,9 @@ type BaseSpec struct {
 	// Transaction type to use throughout the test
 	TestTransactionType helper.TestTransactionType
 
+	// Tags used to select the test, in addition to the tags of the spec type
+	Tags []string
+
 	// Fork Config
 	MainFork         config.Fork
 	ForkTime         uint64
@@ -183,6 +188,15 @@ func (s BaseSpec) GetForkConfig() *config.ForkConfig {
 	} else if mainFork == config.Cancun {
 		forkConfig.ShanghaiTimestamp = new(big.Int).SetUint64(previousForkTime)
 		forkConfig.CancunTimestamp = new(big.Int).SetUint64(forkTime)
+	} else if mainFork == config.Prague {
+		forkConfig.ShanghaiTimestamp = new(big.Int).SetUint64(previousForkTime)
+		forkConfig.CancunTimestamp = new(big.Int).SetUint64(previousForkTime)
+		forkConfig.PragueTimestamp = new(big.Int).SetUint64(forkTime)
+	} else if mainFork == config.Osaka {
+		forkConfig.ShanghaiTimestamp = new(big.Int).SetUint64(previousForkTime)
+		forkConfig.CancunTimestamp = new(big.Int).SetUint64(previousForkTime)
+		forkConfig.PragueTimestamp = new(big.Int).SetUint64(previousForkTime)
+		forkConfig.OsakaTimestamp = new(big.Int).SetUint64(forkTime)
 	} else {
 		panic(fmt.Errorf("unknown fork: %s", mainFork))
 	}
@@ -232,6 +246,10 @@ func (s BaseSpec) IsMiningDisabled() bool {
 	return s.DisableMining
 }
 
+func (s BaseSpec) GetTags() []string {
+	return s.Tags
+}
+
 var LatestFork = config.ForkConfig{
 	ShanghaiTimestamp: big.NewInt(0),
 }
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tags used to select the tests to run, independently of the suite and fork.
+const (
+	// Tests that re-org the canonical chain
+	TagReorg = "reorg"
+	// Tests that send invalid payloads to the client
+	TagInvalidPayload = "invalid-payload"
+	// Tests that require the client to sync from another client
+	TagSync = "sync"
+	// Tests that send blob transactions or verify blob fields
+	TagBlob = "blob"
+	// Tests that include withdrawals in the payloads
+	TagWithdrawals = "withdrawals"
+	// Tests that verify the Engine API authentication
+	TagAuth = "auth"
+	// Tests that send randomly generated payloads
+	TagFuzz = "fuzz"
+	// Tests that measure the latency of the Engine API calls
+	TagPerf = "perf"
+)
+
+// All the tags that can be used to select tests.
+var AllTags = []string{
+	TagReorg,
+	TagInvalidPayload,
+	TagSync,
+	TagBlob,
+	TagWithdrawals,
+	TagAuth,
+	TagFuzz,
+	TagPerf,
+}
+
+// Returns whether the spec has any of the given tags.
+func HasAnyTag(spec Spec, tags []string) bool {
+	for _, specTag := range spec.GetTags() {
+		for _, tag := range tags {
+			if strings.EqualFold(specTag, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Appends the tags to the tags of a spec, without duplicates.
+func AppendTags(tags []string, extra ...string) []string {
+	result := make([]string, 0, len(tags)+len(extra))
+	seen := make(map[string]bool)
+	for _, tag := range append(append([]string{}, tags...), extra...) {
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// Returns the tags used by the given specs, without duplicates. Known tags are listed
+// in the order of AllTags.
+func SpecTags(specs []Spec) []string {
+	used := make(map[string]bool)
+	var extra []string
+	for _, spec := range specs {
+		for _, tag := range spec.GetTags() {
+			if !used[tag] && !slices.Contains(AllTags, tag) {
+				extra = append(extra, tag)
+			}
+			used[tag] = true
+		}
+	}
+	var tags []string
+	for _, tag := range AllTags {
+		if used[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	return append(tags, extra...)
+}
@@ -17,6 +17,15 @@ import (
 const (
 	BlobCommitmentVersionKZG uint8 = 0x01
 	FieldElementsPerBlob     int   = 4096
+	CellsPerExtBlob          int   = 128
+)
+
+// Blob transaction network wrapper versions
+const (
+	// BlobTxWrapperVersion0 contains one proof per blob (EIP-4844)
+	BlobTxWrapperVersion0 byte = 0
+	// BlobTxWrapperVersion1 contains CellsPerExtBlob cell proofs per blob (EIP-7594)
+	BlobTxWrapperVersion1 byte = 1
 )
 
 type KZGCommitment [48]byte
@@ -112,12 +121,15 @@ type KZGProofs []KZGProof
 type Blobs []Blob
 
 type BlobTxWrapData struct {
+	Version     byte
 	Blobs       Blobs
 	Commitments BlobKzgs
 	Proofs      KZGProofs
 }
 
-// BlobsBundle holds the blobs of an execution payload
+// BlobsBundle holds the blobs of an execution payload.
+// Starting on Osaka (engine_getPayloadV5), Proofs contains the CellsPerExtBlob
+// cell proofs of each blob, in blob order.
 type BlobsBundle struct {
 	Commitments []KZGCommitment `json:"commitments" gencodec:"required"`
 	Blobs       []Blob          `json:"blobs"       gencodec:"required"`
@@ -152,6 +164,21 @@ func (bb *BlobsBundle) FromBeaconBlobsBundle(src *beacon.BlobsBundle) error {
 	return nil
 }
 
+// ProofsAt returns the proofs of the blob at the given index, assuming each blob
+// has proofsPerBlob proofs in the bundle.
+func (bb *BlobsBundle) ProofsAt(idx int, proofsPerBlob int) ([]KZGProof, error) {
+	if bb == nil {
+		return nil, errors.New("nil blob bundle")
+	}
+	if idx < 0 || idx >= len(bb.Blobs) {
+		return nil, fmt.Errorf("blob index out of range: %d", idx)
+	}
+	if len(bb.Proofs) != len(bb.Blobs)*proofsPerBlob {
+		return nil, fmt.Errorf("unexpected proof count: have %d, want %d", len(bb.Proofs), len(bb.Blobs)*proofsPerBlob)
+	}
+	return bb.Proofs[idx*proofsPerBlob : (idx+1)*proofsPerBlob], nil
+}
+
 func (bb *BlobsBundle) VersionedHashes(commitmentVersion byte) (*[]common.Hash, error) {
 	if bb == nil {
 		return nil, errors.New("nil blob bundle")
@@ -166,3 +193,45 @@ func (bb *BlobsBundle) VersionedHashes(commitmentVersion byte) (*[]common.Hash,
 	}
 	return &versionedHashes, nil
 }
+
+// BlobAndProofV1 is a blob returned by engine_getBlobsV1
+type BlobAndProofV1 struct {
+	Blob  Blob     `json:"blob"`
+	Proof KZGProof `json:"proof"`
+}
+
+// BlobAndProofV2 is a blob returned by engine_getBlobsV2
+type BlobAndProofV2 struct {
+	Blob       Blob       `json:"blob"`
+	CellProofs []KZGProof `json:"proofs"`
+}
+
+func (bp *BlobAndProofV1) FromBeaconBlobAndProof(src *beacon.BlobAndProofV1) error {
+	if src == nil {
+		return errors.New("nil blob and proof")
+	}
+	if len(src.Blob) != len(bp.Blob) || len(src.Proof) != len(bp.Proof) {
+		return errors.New("invalid blob and proof length")
+	}
+	copy(bp.Blob[:], src.Blob)
+	copy(bp.Proof[:], src.Proof)
+	return nil
+}
+
+func (bp *BlobAndProofV2) FromBeaconBlobAndProof(src *beacon.BlobAndProofV2) error {
+	if src == nil {
+		return errors.New("nil blob and proof")
+	}
+	if len(src.Blob) != len(bp.Blob) {
+		return errors.New("invalid blob length")
+	}
+	copy(bp.Blob[:], src.Blob)
+	bp.CellProofs = make([]KZGProof, len(src.CellProofs))
+	for i, proof := range src.CellProofs {
+		if len(proof) != len(bp.CellProofs[i]) {
+			return fmt.Errorf("invalid cell proof length at index %d", i)
+		}
+		copy(bp.CellProofs[i][:], proof)
+	}
+	return nil
+}
@@ -19,12 +19,19 @@ func (e ExecutionPayloadEnvelope) MarshalJSON() ([]byte, error) {
 		BlockValue            *hexutil.Big    `json:"blockValue"             gencodec:"required"`
 		BlobsBundle           *BlobsBundle    `json:"blobsBundle,omitempty"`
 		ShouldOverrideBuilder *bool           `json:"shouldOverrideBuilder,omitempty"`
+		ExecutionRequests     []hexutil.Bytes `json:"executionRequests,omitempty"`
 	}
 	var enc ExecutionPayloadEnvelope
 	enc.ExecutionPayload = e.ExecutionPayload
 	enc.BlockValue = (*hexutil.Big)(e.BlockValue)
 	enc.BlobsBundle = e.BlobsBundle
 	enc.ShouldOverrideBuilder = e.ShouldOverrideBuilder
+	if e.ExecutionRequests != nil {
+		enc.ExecutionRequests = make([]hexutil.Bytes, len(e.ExecutionRequests))
+		for k, v := range e.ExecutionRequests {
+			enc.ExecutionRequests[k] = v
+		}
+	}
 	return json.Marshal(&enc)
 }
 
@@ -35,6 +42,7 @@ func (e *ExecutionPayloadEnvelope) UnmarshalJSON(input []byte) error {
 		BlockValue            *hexutil.Big    `json:"blockValue"             gencodec:"required"`
 		BlobsBundle           *BlobsBundle    `json:"blobsBundle,omitempty"`
 		ShouldOverrideBuilder *bool           `json:"shouldOverrideBuilder,omitempty"`
+		ExecutionRequests     []hexutil.Bytes `json:"executionRequests,omitempty"`
 	}
 	var dec ExecutionPayloadEnvelope
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -54,5 +62,11 @@ func (e *ExecutionPayloadEnvelope) UnmarshalJSON(input []byte) error {
 	if dec.ShouldOverrideBuilder != nil {
 		e.ShouldOverrideBuilder = dec.ShouldOverrideBuilder
 	}
+	if dec.ExecutionRequests != nil {
+		e.ExecutionRequests = make([][]byte, len(dec.ExecutionRequests))
+		for k, v := range dec.ExecutionRequests {
+			e.ExecutionRequests[k] = v
+		}
+	}
 	return nil
 }
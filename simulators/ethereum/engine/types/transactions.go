@@ -120,6 +120,15 @@ func (tx *TransactionWithBlobData) MarshalBinary() ([]byte, error) {
 		Proofs      []KZGProof
 	}
 
+	// Starting on Osaka, the wrapper version precedes the blobs
+	type MarshalTypeV1 struct {
+		TxPayload   types.BlobTx
+		Version     byte
+		Blobs       []Blob
+		Commitments []KZGCommitment
+		Proofs      []KZGProof
+	}
+
 	pTo := tx.Tx.To()
 	if pTo == nil {
 		return nil, fmt.Errorf("to address is nil")
@@ -151,7 +160,21 @@ func (tx *TransactionWithBlobData) MarshalBinary() ([]byte, error) {
 		Commitments: tx.BlobData.Commitments,
 		Proofs:      tx.BlobData.Proofs,
 	}
-	payloadBytes, err := rlp.EncodeToBytes(marshalBlobTx)
+	var (
+		payloadBytes []byte
+		err          error
+	)
+	if tx.BlobData.Version == BlobTxWrapperVersion1 {
+		payloadBytes, err = rlp.EncodeToBytes(MarshalTypeV1{
+			TxPayload:   marshalBlobTx.TxPayload,
+			Version:     tx.BlobData.Version,
+			Blobs:       marshalBlobTx.Blobs,
+			Commitments: marshalBlobTx.Commitments,
+			Proofs:      marshalBlobTx.Proofs,
+		})
+	} else {
+		payloadBytes, err = rlp.EncodeToBytes(marshalBlobTx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/hive/hivesim"
+)
+
+// blockCheck is a comparison of client responses for each block.
+type blockCheck struct {
+	name        string
+	description string
+
+	// expected returns the value derived from the test chain. It is nil for checks
+	// which only compare clients with each other.
+	expected func(b *types.Block) any
+	// required is set for checks which all clients must support. Errors fail the
+	// check instead of skipping the client.
+	required bool
+	// query fetches a normalized value from a client.
+	query func(n *node, b *types.Block, signer types.Signer) (any, error)
+}
+
+var checks = []*blockCheck{
+	{
+		name:        "block headers",
+		description: "This compares the block hash, state root, receipts root and logs bloom of all blocks with the test chain.",
+		expected:    expectedHeader,
+		query:       queryHeader,
+		required:    true,
+	},
+	{
+		name:        "receipts",
+		description: "This compares the receipts returned by eth_getBlockReceipts.",
+		query:       queryReceipts,
+	},
+	{
+		name:        "call traces",
+		description: "This compares the output of debug_traceBlockByNumber with the callTracer.",
+		query:       queryTraces,
+	},
+	{
+		name:        "account proofs",
+		description: "This compares eth_getProof results for accounts touched by the block.",
+		query:       queryProofs,
+	},
+}
+
+// result is the response of a client.
+type result struct {
+	source string
+	value  any
+}
+
+// runCheck compares client responses block by block, and fails at the first block
+// where they differ. Unless the check is required, clients which return an error for a
+// block are skipped, since not all clients support all methods or keep historical
+// state. The check fails if no client returned a result for any block.
+func runCheck(t *hivesim.T, check *blockCheck, nodes []*node, chain []*types.Block, signer types.Signer) {
+	var (
+		failures = make([]int, len(nodes))
+		blocks   int
+	)
+	for _, b := range chain {
+		if b.NumberU64() == 0 {
+			continue
+		}
+		blocks++
+		var ref *result
+		if check.expected != nil {
+			ref = &result{source: "test chain", value: check.expected(b)}
+		}
+		var results []*result
+		for i, n := range nodes {
+			v, err := check.query(n, b, signer)
+			if err != nil {
+				if check.required {
+					t.Fatalf("%s: error at block %d: %v", n.Type, b.NumberU64(), err)
+				}
+				if failures[i] == 0 {
+					t.Logf("%s: error at block %d: %v", n.Type, b.NumberU64(), err)
+				}
+				failures[i]++
+				continue
+			}
+			results = append(results, &result{source: n.Type, value: v})
+		}
+		if d := divergence(ref, results); d != "" {
+			t.Fatalf("first divergence at block %d (%x):\n%s", b.NumberU64(), b.Hash(), d)
+		}
+	}
+	allFailed := blocks > 0 && len(nodes) > 0
+	for i, n := range nodes {
+		if failures[i] > 0 {
+			t.Logf("%s: skipped %d blocks because of errors", n.Type, failures[i])
+		}
+		allFailed = allFailed && failures[i] == blocks
+	}
+	if allFailed {
+		t.Fatalf("all clients returned errors for all %d blocks", blocks)
+	}
+}
+
+// divergence describes the differences between the results. If ref is nil, the first
+// result is used as the reference.
+func divergence(ref *result, results []*result) string {
+	if ref == nil {
+		if len(results) == 0 {
+			return ""
+		}
+		ref, results = results[0], results[1:]
+	}
+	var lines []string
+	for _, r := range results {
+		if reflect.DeepEqual(ref.value, r.value) {
+			continue
+		}
+		path, a, b := firstDiff(ref.value, r.value, "")
+		if path == "" {
+			path = "(value)"
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s has %v, %s has %v", path, ref.source, a, r.source, b))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// firstDiff returns the path and values of the first difference between a and b.
+func firstDiff(a, b any, path string) (string, any, any) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			return path, a, b
+		}
+		keys := slices.Collect(maps.Keys(av))
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if !reflect.DeepEqual(av[k], bv[k]) {
+				return firstDiff(av[k], bv[k], path+"."+k)
+			}
+		}
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			return path, a, b
+		}
+		for i := range min(len(av), len(bv)) {
+			if !reflect.DeepEqual(av[i], bv[i]) {
+				return firstDiff(av[i], bv[i], fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+		if len(av) != len(bv) {
+			return path + ".length", len(av), len(bv)
+		}
+	}
+	return path, a, b
+}
+
+func expectedHeader(b *types.Block) any {
+	return map[string]any{
+		"hash":         hexString(b.Hash()),
+		"stateRoot":    hexString(b.Root()),
+		"receiptsRoot": hexString(b.ReceiptHash()),
+		"logsBloom":    hexutil.Encode(b.Bloom().Bytes()),
+	}
+}
+
+func queryHeader(n *node, b *types.Block, signer types.Signer) (any, error) {
+	var header map[string]any
+	if err := n.call(&header, "eth_getBlockByNumber", hexutil.EncodeUint64(b.NumberU64()), false); err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("missing block")
+	}
+	return pick(header, "hash", "stateRoot", "receiptsRoot", "logsBloom"), nil
+}
+
+func queryReceipts(n *node, b *types.Block, signer types.Signer) (any, error) {
+	var receipts []map[string]any
+	if err := n.call(&receipts, "eth_getBlockReceipts", hexutil.EncodeUint64(b.NumberU64())); err != nil {
+		return nil, err
+	}
+	list := make([]any, len(receipts))
+	for i, r := range receipts {
+		m := pick(r, "transactionHash", "type", "status", "cumulativeGasUsed", "gasUsed", "effectiveGasPrice", "contractAddress", "blobGasUsed", "blobGasPrice")
+		logs, _ := r["logs"].([]any)
+		mlogs := make([]any, len(logs))
+		for j, l := range logs {
+			lm, _ := l.(map[string]any)
+			mlogs[j] = pick(lm, "address", "topics", "data", "logIndex")
+		}
+		m["logs"] = mlogs
+		list[i] = m
+	}
+	return list, nil
+}
+
+func queryTraces(n *node, b *types.Block, signer types.Signer) (any, error) {
+	var traces []map[string]any
+	config := map[string]any{"tracer": "callTracer"}
+	if err := n.call(&traces, "debug_traceBlockByNumber", hexutil.EncodeUint64(b.NumberU64()), config); err != nil {
+		return nil, err
+	}
+	list := make([]any, len(traces))
+	for i, tr := range traces {
+		list[i] = normalizeCallFrame(tr["result"])
+	}
+	return list, nil
+}
+
+// normalizeCallFrame removes the parts of callTracer output which are known to differ
+// between clients. Error messages are not compared, only whether the call failed.
+func normalizeCallFrame(v any) any {
+	frame, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	m := pick(frame, "type", "from", "to", "value", "gasUsed", "input", "output")
+	if m["value"] == nil {
+		m["value"] = "0x0"
+	}
+	for _, k := range []string{"input", "output"} {
+		if m[k] == "0x" {
+			delete(m, k)
+		}
+	}
+	m["failed"] = frame["error"] != nil
+	if calls, _ := frame["calls"].([]any); len(calls) > 0 {
+		list := make([]any, len(calls))
+		for i, c := range calls {
+			list[i] = normalizeCallFrame(c)
+		}
+		m["calls"] = list
+	}
+	return m
+}
+
+// proofStorageKeys are the storage slots requested by eth_getProof.
+var proofStorageKeys = []string{
+	hexString(common.Hash{}),
+	hexString(common.BigToHash(big.NewInt(1))),
+	hexString(common.BigToHash(big.NewInt(2))),
+	hexString(common.BigToHash(big.NewInt(3))),
+}
+
+func queryProofs(n *node, b *types.Block, signer types.Signer) (any, error) {
+	var list []any
+	for _, addr := range sampleAccounts(b, signer) {
+		var proof map[string]any
+		if err := n.call(&proof, "eth_getProof", addr, proofStorageKeys, hexutil.EncodeUint64(b.NumberU64())); err != nil {
+			return nil, err
+		}
+		m := pick(proof, "balance", "nonce", "codeHash", "storageHash", "accountProof")
+		m["address"] = hexString(addr)
+		storage, _ := proof["storageProof"].([]any)
+		mstorage := make([]any, len(storage))
+		for i, s := range storage {
+			sm, _ := s.(map[string]any)
+			entry := pick(sm, "value", "proof")
+			if key, ok := sm["key"].(string); ok {
+				// Clients differ in the encoding of the key.
+				entry["key"] = hexString(common.BigToHash(hexBig(key)))
+			}
+			mstorage[i] = entry
+		}
+		m["storageProof"] = mstorage
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// maxSampledAccounts is the maximum number of accounts checked by eth_getProof for
+// each block.
+const maxSampledAccounts = 8
+
+// sampleAccounts returns accounts which are modified by the block: the fee recipient,
+// transaction senders and recipients, created contracts and EIP-7702 authorities.
+func sampleAccounts(b *types.Block, signer types.Signer) []common.Address {
+	list := []common.Address{b.Coinbase()}
+	add := func(addr common.Address) {
+		if len(list) < maxSampledAccounts && !slices.Contains(list, addr) {
+			list = append(list, addr)
+		}
+	}
+	for _, tx := range b.Transactions() {
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		add(sender)
+		if tx.To() != nil {
+			add(*tx.To())
+		} else {
+			add(crypto.CreateAddress(sender, tx.Nonce()))
+		}
+		for _, auth := range tx.SetCodeAuthorizations() {
+			if authority, err := auth.Authority(); err == nil {
+				add(authority)
+			}
+		}
+	}
+	return list
+}
+
+// quantityFields are response fields containing hex-encoded numbers. They are
+// normalized because clients may encode them differently, e.g. with leading zeros.
+var quantityFields = map[string]bool{
+	"balance":           true,
+	"blobGasPrice":      true,
+	"blobGasUsed":       true,
+	"cumulativeGasUsed": true,
+	"effectiveGasPrice": true,
+	"gasUsed":           true,
+	"logIndex":          true,
+	"nonce":             true,
+	"status":            true,
+	"type":              true,
+	"value":             true,
+}
+
+// pick returns the given fields of m in normalized form. Null fields are omitted.
+func pick(m map[string]any, fields ...string) map[string]any {
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		v, ok := m[f]
+		if !ok || v == nil {
+			continue
+		}
+		if s, ok := v.(string); ok && quantityFields[f] && strings.HasPrefix(s, "0x") {
+			out[f] = hexutil.EncodeBig(hexBig(s))
+			continue
+		}
+		out[f] = normalize(v)
+	}
+	return out
+}
+
+// normalize converts hex strings to lower case.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case string:
+		return strings.ToLower(v)
+	case []any:
+		list := make([]any, len(v))
+		for i := range v {
+			list[i] = normalize(v[i])
+		}
+		return list
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k := range v {
+			m[k] = normalize(v[k])
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+// hexBig parses a hex number. Invalid input is returned as zero.
+func hexBig(s string) *big.Int {
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return new(big.Int)
+	}
+	return v
+}
+
+func hexString(v interface{ Hex() string }) string {
+	return strings.ToLower(v.Hex())
+}
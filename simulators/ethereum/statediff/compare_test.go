@@ -0,0 +1,191 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDivergence(t *testing.T) {
+	header := map[string]any{"hash": "0x01", "stateRoot": "0x02"}
+	tests := []struct {
+		name    string
+		ref     *result
+		results []*result
+		want    []string // substrings of the output, empty if the results match
+	}{
+		{
+			name: "no results",
+		},
+		{
+			name: "matching clients",
+			results: []*result{
+				{source: "a", value: header},
+				{source: "b", value: map[string]any{"hash": "0x01", "stateRoot": "0x02"}},
+			},
+		},
+		{
+			name: "matching reference",
+			ref:  &result{source: "test chain", value: header},
+			results: []*result{
+				{source: "a", value: map[string]any{"hash": "0x01", "stateRoot": "0x02"}},
+			},
+		},
+		{
+			name: "diverging client",
+			results: []*result{
+				{source: "a", value: header},
+				{source: "b", value: header},
+				{source: "c", value: map[string]any{"hash": "0x01", "stateRoot": "0x03"}},
+			},
+			want: []string{"  .stateRoot: a has 0x02, c has 0x03"},
+		},
+		{
+			name: "diverging from reference",
+			ref:  &result{source: "test chain", value: header},
+			results: []*result{
+				{source: "a", value: map[string]any{"hash": "0x04", "stateRoot": "0x02"}},
+				{source: "b", value: map[string]any{"hash": "0x01", "stateRoot": "0x02"}},
+			},
+			want: []string{"  .hash: test chain has 0x01, a has 0x04"},
+		},
+		{
+			name: "missing field",
+			ref:  &result{source: "test chain", value: header},
+			results: []*result{
+				{source: "a", value: map[string]any{"hash": "0x01"}},
+			},
+			want: []string{"  .stateRoot: test chain has 0x02, a has <nil>"},
+		},
+		{
+			name: "different values",
+			results: []*result{
+				{source: "a", value: "0x01"},
+				{source: "b", value: "0x02"},
+			},
+			want: []string{"  (value): a has 0x01, b has 0x02"},
+		},
+	}
+	for _, test := range tests {
+		got := divergence(test.ref, test.results)
+		if len(test.want) == 0 {
+			if got != "" {
+				t.Errorf("%s: unexpected divergence:\n%s", test.name, got)
+			}
+			continue
+		}
+		if got != strings.Join(test.want, "\n") {
+			t.Errorf("%s: wrong divergence:\ngot  %q\nwant %q", test.name, got, strings.Join(test.want, "\n"))
+		}
+	}
+}
+
+func TestFirstDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     any
+		wantPath string
+		wantA    any
+		wantB    any
+	}{
+		{
+			name:     "scalar",
+			a:        "0x01",
+			b:        "0x02",
+			wantPath: "",
+			wantA:    "0x01",
+			wantB:    "0x02",
+		},
+		{
+			name:     "first key in order",
+			a:        map[string]any{"b": "1", "a": "1"},
+			b:        map[string]any{"b": "2", "a": "2"},
+			wantPath: ".a",
+			wantA:    "1",
+			wantB:    "2",
+		},
+		{
+			name:     "missing key",
+			a:        map[string]any{"a": "1"},
+			b:        map[string]any{"a": "1", "b": "2"},
+			wantPath: ".b",
+			wantA:    nil,
+			wantB:    "2",
+		},
+		{
+			name:     "nested list",
+			a:        map[string]any{"logs": []any{map[string]any{"data": "0x"}, map[string]any{"data": "0x01"}}},
+			b:        map[string]any{"logs": []any{map[string]any{"data": "0x"}, map[string]any{"data": "0x02"}}},
+			wantPath: ".logs[1].data",
+			wantA:    "0x01",
+			wantB:    "0x02",
+		},
+		{
+			name:     "list length",
+			a:        []any{"1", "2"},
+			b:        []any{"1"},
+			wantPath: ".length",
+			wantA:    2,
+			wantB:    1,
+		},
+		{
+			name:     "type mismatch",
+			a:        map[string]any{"a": []any{"1"}},
+			b:        map[string]any{"a": "1"},
+			wantPath: ".a",
+			wantA:    []any{"1"},
+			wantB:    "1",
+		},
+	}
+	for _, test := range tests {
+		path, a, b := firstDiff(test.a, test.b, "")
+		if path != test.wantPath || !reflect.DeepEqual(a, test.wantA) || !reflect.DeepEqual(b, test.wantB) {
+			t.Errorf("%s: got (%q, %v, %v), want (%q, %v, %v)", test.name, path, a, b, test.wantPath, test.wantA, test.wantB)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  map[string]any
+		fields []string
+		want   map[string]any
+	}{
+		{
+			name:   "quantities",
+			input:  map[string]any{"gasUsed": "0x0005", "nonce": "0x0", "status": "0x01"},
+			fields: []string{"gasUsed", "nonce", "status"},
+			want:   map[string]any{"gasUsed": "0x5", "nonce": "0x0", "status": "0x1"},
+		},
+		{
+			name:   "hex case",
+			input:  map[string]any{"hash": "0xABcd", "topics": []any{"0xEF"}},
+			fields: []string{"hash", "topics"},
+			want:   map[string]any{"hash": "0xabcd", "topics": []any{"0xef"}},
+		},
+		{
+			name:   "missing and null fields",
+			input:  map[string]any{"to": nil, "from": "0xAA"},
+			fields: []string{"to", "from", "contractAddress"},
+			want:   map[string]any{"from": "0xaa"},
+		},
+		{
+			name:   "unpicked fields",
+			input:  map[string]any{"hash": "0x01", "blockHash": "0x02"},
+			fields: []string{"hash"},
+			want:   map[string]any{"hash": "0x01"},
+		},
+		{
+			name:   "nested values",
+			input:  map[string]any{"value": map[string]any{"Key": "0xAB"}},
+			fields: []string{"value"},
+			want:   map[string]any{"value": map[string]any{"Key": "0xab"}},
+		},
+	}
+	for _, test := range tests {
+		if got := pick(test.input, test.fields...); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/hive/hivesim"
+)
+
+var files = map[string]string{
+	"genesis.json": "./chain/genesis.json",
+	"chain.rlp":    "./chain/chain.rlp",
+}
+
+func main() {
+	// Load fork environment.
+	var params hivesim.Params
+	err := common.LoadJSON("chain/forkenv.json", &params)
+	if err != nil {
+		panic(err)
+	}
+
+	suite := hivesim.Suite{
+		Name: "statediff",
+		Description: `This suite imports the same test chain into all clients and compares
+their view of it block by block: block header roots, receipts, call traces and account
+proofs. The first divergence between clients is reported.`,
+	}
+	suite.Add(hivesim.TestSpec{
+		Name:        "launch clients",
+		Description: "This starts all clients with the test chain. The comparisons run as subtests.",
+		AlwaysRun:   true,
+		Run: func(t *hivesim.T) {
+			runStateDiff(t, params)
+		},
+	})
+	hivesim.MustRunSuite(hivesim.New(), suite)
+}
+
+func runStateDiff(t *hivesim.T, params hivesim.Params) {
+	chain, err := loadChain("chain/chain.rlp")
+	if err != nil {
+		t.Fatal("can't load test chain:", err)
+	}
+	var genesis struct {
+		Config struct {
+			ChainID int64 `json:"chainId"`
+		} `json:"config"`
+	}
+	if err := common.LoadJSON("chain/genesis.json", &genesis); err != nil {
+		t.Fatal("can't load genesis:", err)
+	}
+
+	clientTypes, err := t.Sim.ClientTypes()
+	if err != nil {
+		t.Fatal("can't get client types:", err)
+	}
+	var nodes []*node
+	for _, ct := range clientTypes {
+		if !ct.HasRole("eth1") {
+			continue
+		}
+		n := &node{t.StartClient(ct.Name, params, hivesim.WithStaticFiles(files))}
+		if err := n.sendForkchoiceUpdated(); err != nil {
+			t.Errorf("%s: forkchoiceUpdated failed: %v", n.Type, err)
+		}
+		nodes = append(nodes, n)
+	}
+	if len(nodes) < 2 {
+		t.Log("only one client is running, results are compared with the test chain only")
+	}
+
+	signer := types.LatestSignerForChainID(big.NewInt(genesis.Config.ChainID))
+	for _, check := range checks {
+		t.Run(hivesim.TestSpec{
+			Name:        check.name,
+			Description: check.description,
+			Run: func(t *hivesim.T) {
+				runCheck(t, check, nodes, chain, signer)
+			},
+		})
+	}
+}
+
+type node struct {
+	*hivesim.Client
+}
+
+// call performs an RPC call with a timeout.
+func (n *node) call(result any, method string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	return n.RPC().CallContext(ctx, result, method, args...)
+}
+
+// sendForkchoiceUpdated sets the head block of the client, so it considers the chain
+// canonical.
+func (n *node) sendForkchoiceUpdated() error {
+	var request struct {
+		Method string
+		Params []any
+	}
+	if err := common.LoadJSON("chain/headfcu.json", &request); err != nil {
+		return err
+	}
+	var resp engine.ForkChoiceResponse
+	return n.EngineAPI().Call(&resp, request.Method, request.Params...)
+}
+
+// loadChain reads the blocks of a chain.rlp file.
+func loadChain(file string) ([]*types.Block, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var blocks []*types.Block
+	s := rlp.NewStream(bufio.NewReader(f), 0)
+	for i := 0; ; i++ {
+		var b types.Block
+		if err := s.Decode(&b); err == io.EOF {
+			return blocks, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		blocks = append(blocks, &b)
+	}
+}
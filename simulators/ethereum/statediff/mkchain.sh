@@ -0,0 +1,7 @@
+#!/bin/sh
+
+wd="$(pwd)"
+cd ../../..
+go build ./cmd/hivechain
+mkdir -p "$wd/chain"
+./hivechain generate -spec "$wd/chain.yaml" -outdir "$wd/chain"